@@ -7,6 +7,8 @@ import (
 
 	gomarkdown "github.com/gomarkdown/markdown"
 	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/renderer"
 	"github.com/yuin/goldmark/renderer/html"
 	"github.com/yuin/goldmark/util"
 	"gitlab.com/golang-commonmark/markdown"
@@ -37,6 +39,19 @@ func BenchmarkMarkdown(b *testing.B) {
 		doBenchmark(b, r)
 	})
 
+	b.Run("GoldMark-LowAlloc", func(b *testing.B) {
+		markdown := goldmark.New(
+			goldmark.WithParserOptions(parser.WithNodeArena(), parser.WithContextPool()),
+			goldmark.WithRendererOptions(html.WithXHTML(), html.WithUnsafe(), renderer.WithBufWriterPool()),
+		)
+		r := func(src []byte) ([]byte, error) {
+			var out bytes.Buffer
+			err := markdown.Convert(src, &out)
+			return out.Bytes(), err
+		}
+		doBenchmark(b, r)
+	})
+
 	b.Run("CommonMark", func(b *testing.B) {
 		md := markdown.New(markdown.XHTMLOutput(true))
 		r := func(src []byte) ([]byte, error) {
@@ -0,0 +1,116 @@
+package ast
+
+import (
+	textm "github.com/yuin/goldmark/text"
+)
+
+// arenaSlabSize is the number of nodes in a slab.
+const arenaSlabSize = 128
+
+// arenaLinesSize is the number of segments that are reserved for lines of
+// a block.
+const arenaLinesSize = 4
+
+// A NodeArena allocates frequently used nodes in slabs to reduce the number
+// of allocations.
+//
+// Nodes allocated by a NodeArena are not garbage collected until all nodes
+// in the same slab become unreachable, so a NodeArena should be used for
+// documents that are discarded as a whole.
+// Methods of a nil NodeArena allocate nodes individually.
+// A NodeArena is not safe for concurrent use.
+type NodeArena struct {
+	texts            []Text
+	paragraphs       []Paragraph
+	fencedCodeBlocks []FencedCodeBlock
+	codeSpans        []CodeSpan
+	segments         []textm.Segment
+}
+
+// NewNodeArena returns a new NodeArena.
+func NewNodeArena() *NodeArena {
+	return &NodeArena{}
+}
+
+// alloc returns a pointer to the first element of the slab and advances
+// the slab. A new slab is allocated if the slab is empty.
+func alloc[T any](slab *[]T) *T {
+	if len(*slab) == 0 {
+		*slab = make([]T, arenaSlabSize)
+	}
+	v := &(*slab)[0]
+	*slab = (*slab)[1:]
+	return v
+}
+
+// lines returns new lines that have reserved capacities.
+func (a *NodeArena) lines() *textm.Segments {
+	if len(a.segments) < arenaLinesSize {
+		a.segments = make([]textm.Segment, arenaSlabSize*arenaLinesSize)
+	}
+	buf := a.segments[:0:arenaLinesSize]
+	a.segments = a.segments[arenaLinesSize:]
+	return textm.NewSegmentsWithBuffer(buf)
+}
+
+// NewTextSegment returns a new Text node with the given source position.
+func (a *NodeArena) NewTextSegment(v textm.Segment) *Text {
+	if a == nil {
+		return NewTextSegment(v)
+	}
+	n := alloc(&a.texts)
+	n.Segment = v
+	return n
+}
+
+// NewRawTextSegment returns a new Text node with the given source position.
+// The new node should be rendered as raw contents.
+func (a *NodeArena) NewRawTextSegment(v textm.Segment) *Text {
+	if a == nil {
+		return NewRawTextSegment(v)
+	}
+	n := a.NewTextSegment(v)
+	n.SetRaw(true)
+	return n
+}
+
+// MergeOrAppendTextSegment is same as MergeOrAppendTextSegment, but new
+// Text nodes are allocated by this arena.
+func (a *NodeArena) MergeOrAppendTextSegment(parent Node, s textm.Segment) {
+	last := parent.LastChild()
+	t, ok := last.(*Text)
+	if ok && t.Segment.Stop == s.Start && !t.SoftLineBreak() {
+		t.Segment = t.Segment.WithStop(s.Stop)
+	} else {
+		parent.AppendChild(parent, a.NewTextSegment(s))
+	}
+}
+
+// NewParagraph returns a new Paragraph node.
+func (a *NodeArena) NewParagraph() *Paragraph {
+	if a == nil {
+		return NewParagraph()
+	}
+	n := alloc(&a.paragraphs)
+	n.SetLines(a.lines())
+	return n
+}
+
+// NewFencedCodeBlock returns a new FencedCodeBlock node.
+func (a *NodeArena) NewFencedCodeBlock(info *Text) *FencedCodeBlock {
+	if a == nil {
+		return NewFencedCodeBlock(info)
+	}
+	n := alloc(&a.fencedCodeBlocks)
+	n.Info = info
+	n.SetLines(a.lines())
+	return n
+}
+
+// NewCodeSpan returns a new CodeSpan node.
+func (a *NodeArena) NewCodeSpan() *CodeSpan {
+	if a == nil {
+		return NewCodeSpan()
+	}
+	return alloc(&a.codeSpans)
+}
@@ -512,6 +512,91 @@ func DumpHelper(v Node, source []byte, level int, kv map[string]string, cb func(
 	fmt.Printf("%s}\n", indent)
 }
 
+// A SourceOwner interface is implemented by nodes whose descendants refer to
+// a source other than the source of the document.
+//
+// When OwnSource returns a non-nil source, positions, lines and segments of
+// the descendants are offsets in that source, not in the source of the
+// document. Code that reads values of the descendants must take the source
+// from SourceOf, and the descendants can not be mapped back to the source
+// of the document, so code that edits the source of the document must not
+// use their positions.
+type SourceOwner interface {
+	// OwnSource returns a source that descendants of this node refer to.
+	// OwnSource returns nil if descendants refer to the source of the document.
+	OwnSource() []byte
+}
+
+// SourceOf returns a source that the given node refers to.
+// source is a source of the document.
+func SourceOf(n Node, source []byte) []byte {
+	for p := n.Parent(); p != nil; p = p.Parent() {
+		if o, ok := p.(SourceOwner); ok {
+			if s := o.OwnSource(); s != nil {
+				return s
+			}
+		}
+	}
+	return source
+}
+
+// A SegmentShifter interface is implemented by nodes that have segments
+// other than lines and segments of Text nodes.
+type SegmentShifter interface {
+	// ShiftSegments moves segments of this node by the given delta.
+	ShiftSegments(delta int)
+}
+
+// ShiftSegments moves positions and segments of the given node and its
+// descendants by the given delta. This is useful when texts before the node
+// are inserted into or removed from the source.
+// Descendants of SourceOwner nodes that have their own sources are not moved.
+func ShiftSegments(n Node, delta int) {
+	_ = Walk(n, func(c Node, entering bool) (WalkStatus, error) {
+		if !entering {
+			return WalkContinue, nil
+		}
+		if pos := c.Pos(); pos > -1 {
+			c.SetPos(pos + delta)
+		}
+		if c.Type() != TypeInline {
+			lines := c.Lines()
+			for i := range lines.Len() {
+				lines.Set(i, shiftSegment(lines.At(i), delta))
+			}
+		}
+		switch typed := c.(type) {
+		case *Text:
+			typed.Segment = shiftSegment(typed.Segment, delta)
+		case *FencedCodeBlock:
+			if typed.Info != nil {
+				typed.Info.Segment = shiftSegment(typed.Info.Segment, delta)
+			}
+		case *HTMLBlock:
+			typed.ClosureLine = shiftSegment(typed.ClosureLine, delta)
+		case *RawHTML:
+			for i := range typed.Segments.Len() {
+				typed.Segments.Set(i, shiftSegment(typed.Segments.At(i), delta))
+			}
+		case SegmentShifter:
+			typed.ShiftSegments(delta)
+		}
+		if o, ok := c.(SourceOwner); ok && o.OwnSource() != nil {
+			return WalkSkipChildren, nil
+		}
+		return WalkContinue, nil
+	})
+}
+
+func shiftSegment(s textm.Segment, delta int) textm.Segment {
+	if s.Start < 0 {
+		return s
+	}
+	s.Start += delta
+	s.Stop += delta
+	return s
+}
+
 // WalkStatus represents a current status of the Walk function.
 type WalkStatus int
 
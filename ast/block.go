@@ -1,7 +1,9 @@
 package ast
 
 import (
+	"bytes"
 	"fmt"
+	"maps"
 	"strings"
 
 	textm "github.com/yuin/goldmark/text"
@@ -74,6 +76,13 @@ func (n *Document) Kind() NodeKind {
 	return KindDocument
 }
 
+// CloneNode implements Cloner.CloneNode.
+func (n *Document) CloneNode() Node {
+	c := *n
+	c.meta = maps.Clone(n.meta)
+	return &c
+}
+
 // OwnerDocument implements Node.OwnerDocument.
 func (n *Document) OwnerDocument() *Document {
 	return n
@@ -343,6 +352,16 @@ func (n *FencedCodeBlock) Kind() NodeKind {
 	return KindFencedCodeBlock
 }
 
+// CloneNode implements Cloner.CloneNode.
+func (n *FencedCodeBlock) CloneNode() Node {
+	c := *n
+	if n.Info != nil {
+		c.Info = Clone(n.Info).(*Text)
+	}
+	c.language = bytes.Clone(n.language)
+	return &c
+}
+
 // Text implements Node.Text.
 //
 // Deprecated: Use other properties of the node to get the text value(i.e. FencedCodeBlock.Lines).
@@ -611,6 +630,15 @@ func (l *LinkReferenceDefinition) Kind() NodeKind {
 	return KindLinkReferenceDefinition
 }
 
+// CloneNode implements Cloner.CloneNode.
+func (l *LinkReferenceDefinition) CloneNode() Node {
+	c := *l
+	c.Label = bytes.Clone(l.Label)
+	c.Destination = bytes.Clone(l.Destination)
+	c.Title = bytes.Clone(l.Title)
+	return &c
+}
+
 // NewLinkReferenceDefinition returns a new LinkReferenceDefinition node.
 func NewLinkReferenceDefinition(label, destination, title []byte) *LinkReferenceDefinition {
 	return &LinkReferenceDefinition{
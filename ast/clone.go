@@ -0,0 +1,172 @@
+package ast
+
+import (
+	"bytes"
+	"reflect"
+
+	textm "github.com/yuin/goldmark/text"
+)
+
+// A Cloner interface is implemented by nodes that have fields referring to
+// mutable values like slices, maps and pointers.
+//
+// Nodes that do not implement Cloner are copied field by field, so their
+// copies share such values with the original nodes.
+type Cloner interface {
+	// CloneNode returns a copy of this node whose fields do not share
+	// mutable values with this node.
+	// Parents, siblings, children, attributes, positions and lines of the
+	// copy are set by Clone, so implementations can copy the struct and
+	// replace fields specific to the node.
+	CloneNode() Node
+}
+
+// baseNoder is implemented by nodes that embed BaseNode.
+type baseNoder interface {
+	baseNode() *BaseNode
+}
+
+func (n *BaseNode) baseNode() *BaseNode {
+	return n
+}
+
+// Clone returns a deep copy of the given node and its descendants. The copy
+// has neither a parent nor siblings.
+//
+// Nodes that do not embed BaseNode must implement Cloner.
+func Clone(n Node) Node {
+	c := cloneNode(n)
+	for child := n.FirstChild(); child != nil; child = child.NextSibling() {
+		c.AppendChild(c, Clone(child))
+	}
+	return c
+}
+
+func cloneNode(n Node) Node {
+	var c Node
+	if cloner, ok := n.(Cloner); ok {
+		c = cloner.CloneNode()
+	} else {
+		c = shallowCopy(n)
+	}
+	if b, ok := c.(baseNoder); ok {
+		base := b.baseNode()
+		*base = BaseNode{}
+		if o, ok := n.(baseNoder); ok {
+			base.pos = o.baseNode().pos
+		} else if pos := n.Pos(); pos > -1 {
+			base.pos.SetPos(pos)
+		}
+		if attrs := n.Attributes(); attrs != nil {
+			base.attributes = make([]Attribute, len(attrs))
+			for i, attr := range attrs {
+				base.attributes[i] = Attribute{
+					Name:  bytes.Clone(attr.Name),
+					Value: cloneAttributeValue(attr.Value),
+				}
+			}
+		}
+	} else {
+		c.SetParent(nil)
+		c.SetPreviousSibling(nil)
+		c.SetNextSibling(nil)
+	}
+	if n.Type() != TypeInline {
+		c.SetLines(n.Lines().Clone())
+		c.SetBlankPreviousLines(n.HasBlankPreviousLines())
+	}
+	return c
+}
+
+func cloneAttributeValue(v any) any {
+	if b, ok := v.([]byte); ok {
+		return bytes.Clone(b)
+	}
+	return v
+}
+
+// shallowCopy returns a copy of the struct that the given node points to.
+func shallowCopy(n Node) Node {
+	v := reflect.ValueOf(n)
+	if v.Kind() != reflect.Pointer || v.Elem().Kind() != reflect.Struct {
+		panic("ast: can not copy " + v.Type().String() + ", implement ast.Cloner")
+	}
+	c := reflect.New(v.Elem().Type())
+	c.Elem().Set(v.Elem())
+	return c.Interface().(Node)
+}
+
+// Equal returns true if the given nodes and their descendants have the same
+// kinds, field values, attributes, positions and lines. Parents and siblings
+// of the given nodes are not compared.
+func Equal(a, b Node) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	if a.Kind() != b.Kind() || a.Type() != b.Type() || a.Pos() != b.Pos() ||
+		!equalAttributes(a.Attributes(), b.Attributes()) {
+		return false
+	}
+	if a.Type() != TypeInline && (a.HasBlankPreviousLines() != b.HasBlankPreviousLines() ||
+		!equalLines(a.Lines(), b.Lines())) {
+		return false
+	}
+	if !reflect.DeepEqual(fieldsOf(a), fieldsOf(b)) {
+		return false
+	}
+	ca, cb := a.FirstChild(), b.FirstChild()
+	for ; ca != nil && cb != nil; ca, cb = ca.NextSibling(), cb.NextSibling() {
+		if !Equal(ca, cb) {
+			return false
+		}
+	}
+	return ca == nil && cb == nil
+}
+
+// fieldsOf returns a copy of the given node that has only fields specific
+// to the node.
+func fieldsOf(n Node) Node {
+	if _, ok := n.(baseNoder); !ok {
+		return n
+	}
+	c := shallowCopy(n)
+	*c.(baseNoder).baseNode() = BaseNode{}
+	if n.Type() != TypeInline {
+		c.SetLines(&textm.Segments{})
+		c.SetBlankPreviousLines(false)
+	}
+	// caches are not compared.
+	switch v := c.(type) {
+	case *Document:
+		if len(v.meta) == 0 {
+			v.meta = nil
+		}
+	case *FencedCodeBlock:
+		v.language = nil
+	}
+	return c
+}
+
+func equalAttributes(a, b []Attribute) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if !bytes.Equal(a[i].Name, b[i].Name) || !reflect.DeepEqual(a[i].Value, b[i].Value) {
+			return false
+		}
+	}
+	return true
+}
+
+func equalLines(a, b *textm.Segments) bool {
+	if a.Len() != b.Len() {
+		return false
+	}
+	for i := range a.Len() {
+		if a.At(i) != b.At(i) {
+			return false
+		}
+	}
+	return true
+}
@@ -1,6 +1,7 @@
 package ast
 
 import (
+	"bytes"
 	"fmt"
 	"strings"
 
@@ -303,6 +304,13 @@ func (n *String) Kind() NodeKind {
 	return KindString
 }
 
+// CloneNode implements Cloner.CloneNode.
+func (n *String) CloneNode() Node {
+	c := *n
+	c.Value = bytes.Clone(n.Value)
+	return &c
+}
+
 // NewString returns a new String node.
 func NewString(v []byte) *String {
 	return &String{
@@ -400,6 +408,21 @@ type baseLink struct {
 func (n *baseLink) Inline() {
 }
 
+// cloneLink returns a copy of this link that does not share slices with
+// this link.
+func (n *baseLink) cloneLink() baseLink {
+	c := *n
+	c.Destination = bytes.Clone(n.Destination)
+	c.Title = bytes.Clone(n.Title)
+	if n.Reference != nil {
+		c.Reference = &ReferenceLink{
+			Type:  n.Reference.Type,
+			Value: bytes.Clone(n.Reference.Value),
+		}
+	}
+	return c
+}
+
 // ReferenceLinkType defines a kind of reference link.
 type ReferenceLinkType int
 
@@ -478,6 +501,13 @@ func (n *Link) Kind() NodeKind {
 	return KindLink
 }
 
+// CloneNode implements Cloner.CloneNode.
+func (n *Link) CloneNode() Node {
+	c := *n
+	c.baseLink = n.cloneLink()
+	return &c
+}
+
 // NewLink returns a new Link node.
 func NewLink() *Link {
 	c := &Link{
@@ -523,6 +553,13 @@ func (n *Image) Kind() NodeKind {
 	return KindImage
 }
 
+// CloneNode implements Cloner.CloneNode.
+func (n *Image) CloneNode() Node {
+	c := *n
+	c.baseLink = n.cloneLink()
+	return &c
+}
+
 // NewImage returns a new Image node.
 func NewImage(link *Link) *Image {
 	c := &Image{
@@ -585,6 +622,16 @@ func (n *AutoLink) Kind() NodeKind {
 	return KindAutoLink
 }
 
+// CloneNode implements Cloner.CloneNode.
+func (n *AutoLink) CloneNode() Node {
+	c := *n
+	c.Protocol = bytes.Clone(n.Protocol)
+	if n.value != nil {
+		c.value = Clone(n.value).(*Text)
+	}
+	return &c
+}
+
 // URL returns an url of this node.
 func (n *AutoLink) URL(source []byte) []byte {
 	if n.Protocol != nil {
@@ -610,6 +657,11 @@ func (n *AutoLink) Text(source []byte) []byte {
 	return n.value.Value(source)
 }
 
+// ShiftSegments implements SegmentShifter.ShiftSegments.
+func (n *AutoLink) ShiftSegments(delta int) {
+	n.value.Segment = shiftSegment(n.value.Segment, delta)
+}
+
 // NewAutoLink returns a new AutoLink node.
 func NewAutoLink(typ AutoLinkType, value *Text) *AutoLink {
 	return &AutoLink{
@@ -648,6 +700,15 @@ func (n *RawHTML) Kind() NodeKind {
 	return KindRawHTML
 }
 
+// CloneNode implements Cloner.CloneNode.
+func (n *RawHTML) CloneNode() Node {
+	c := *n
+	if n.Segments != nil {
+		c.Segments = n.Segments.Clone()
+	}
+	return &c
+}
+
 // Text implements Node.Text.
 //
 // Deprecated: Use other properties of the node to get the text value(i.e. RawHTML.Segments).
@@ -6,6 +6,8 @@ import (
 
 	. "github.com/yuin/goldmark"
 	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/extension"
+	east "github.com/yuin/goldmark/extension/ast"
 	"github.com/yuin/goldmark/parser"
 	"github.com/yuin/goldmark/testutil"
 	"github.com/yuin/goldmark/text"
@@ -334,3 +336,121 @@ aaaa **b**
 		t.Error("unexpected position for 1st image")
 	}
 }
+
+// cloneTestNode is a third-party node that implements ast.Cloner.
+type cloneTestNode struct {
+	ast.BaseInline
+	Value []byte
+}
+
+var kindCloneTestNode = ast.NewNodeKind("CloneTestNode")
+
+func (n *cloneTestNode) Kind() ast.NodeKind {
+	return kindCloneTestNode
+}
+
+func (n *cloneTestNode) Dump(source []byte, level int) {
+	ast.DumpHelper(n, source, level, nil, nil)
+}
+
+func (n *cloneTestNode) CloneNode() ast.Node {
+	c := *n
+	c.Value = bytes.Clone(n.Value)
+	return &c
+}
+
+func TestCloneAndEqual(t *testing.T) {
+	markdown := New(
+		WithExtensions(extension.GFM, extension.Footnote, extension.DefinitionList),
+		WithParserOptions(parser.WithAttribute()),
+	)
+	source := []byte(`# Title {#title .c}
+
+A [link](/url "t"), <https://example.com>, ![img](/a.png) and ~~del~~[^1].
+
+| a | b |
+|:--|--:|
+| c | d |
+
+` + "```go\ncode\n```" + `
+
+- [x] task
+
+Term
+: Description
+
+[^1]: Note
+`)
+	doc := markdown.Parser().Parse(text.NewReader(source))
+	clone := ast.Clone(doc)
+	if !ast.Equal(doc, clone) {
+		t.Fatal("a clone should be equal to the original")
+	}
+	var b1, b2 bytes.Buffer
+	_ = markdown.Renderer().Render(&b1, source, doc)
+	_ = markdown.Renderer().Render(&b2, source, clone)
+	if b1.String() != b2.String() {
+		t.Errorf("a clone should be rendered as the original:\n%s\n%s", b1.String(), b2.String())
+	}
+
+	// caches are not compared.
+	_ = doc.FirstChild().NextSibling().NextSibling().NextSibling().(*ast.FencedCodeBlock).Language(source)
+	if !ast.Equal(doc, clone) {
+		t.Error("caches should not be compared")
+	}
+
+	// clones do not share values with originals.
+	var link *ast.Link
+	_ = ast.Walk(clone, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		if l, ok := n.(*ast.Link); ok && entering {
+			link = l
+		}
+		return ast.WalkContinue, nil
+	})
+	link.Destination[0] = '#'
+	if ast.Equal(doc, clone) {
+		t.Error("destinations should be copied")
+	}
+	link.Destination[0] = '/'
+	table := clone.FirstChild().NextSibling().NextSibling().(*east.Table)
+	table.Alignments[0] = east.AlignNone
+	if doc.FirstChild().NextSibling().NextSibling().(*east.Table).Alignments[0] != east.AlignLeft {
+		t.Error("alignments should be copied")
+	}
+	table.Alignments[0] = east.AlignLeft
+	heading := clone.FirstChild()
+	heading.SetAttributeString("class", []byte("x"))
+	if v, _ := doc.FirstChild().AttributeString("class"); string(v.([]byte)) != "c" {
+		t.Error("attributes should be copied")
+	}
+	if ast.Equal(doc, clone) {
+		t.Error("attributes should be compared")
+	}
+	heading.SetAttributeString("class", []byte("c"))
+	heading.Lines().Set(0, text.NewSegment(0, 1))
+	if ast.Equal(doc, clone) {
+		t.Error("lines should be compared")
+	}
+
+	// clones are detached.
+	para := ast.Clone(doc.FirstChild().NextSibling())
+	if para.Parent() != nil || para.NextSibling() != nil || para.PreviousSibling() != nil {
+		t.Error("a clone should be detached")
+	}
+	doc.AppendChild(doc, para)
+	if !ast.Equal(para, doc.FirstChild().NextSibling()) {
+		t.Error("a clone should be equal to the original")
+	}
+
+	// third-party nodes
+	n := &cloneTestNode{Value: []byte("a")}
+	n.AppendChild(n, ast.NewString([]byte("b")))
+	c := ast.Clone(n).(*cloneTestNode)
+	if !ast.Equal(n, c) {
+		t.Error("a clone should be equal to the original")
+	}
+	c.Value[0] = 'x'
+	if ast.Equal(n, c) || string(n.Value) != "a" {
+		t.Error("values should be copied by CloneNode")
+	}
+}
@@ -0,0 +1,292 @@
+package goldmark
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"runtime"
+	"runtime/debug"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/yuin/goldmark/text"
+)
+
+// A BatchSource struct is a named Markdown source that is converted by
+// ConvertBatch.
+type BatchSource struct {
+	// Name is a name of the source, for example, a slash-separated path.
+	Name string
+
+	// Source is a UTF-8 Markdown source.
+	Source []byte
+
+	// Err is an error occurred while reading the source. Sources that have
+	// errors are not converted and reported as failed.
+	Err error
+}
+
+// BatchSources is an iterator of BatchSources. BatchSources is compatible
+// with iter.Seq[BatchSource].
+type BatchSources func(yield func(BatchSource) bool)
+
+// SliceSources returns BatchSources that yields the given sources.
+func SliceSources(sources ...BatchSource) BatchSources {
+	return func(yield func(BatchSource) bool) {
+		for _, s := range sources {
+			if !yield(s) {
+				return
+			}
+		}
+	}
+}
+
+// FileSources returns BatchSources that yields files of the given file
+// system that have the given extension, like ".md". Names of the sources
+// are paths of the files. Files are read only when they are yielded.
+func FileSources(fsys fs.FS, ext string) BatchSources {
+	return func(yield func(BatchSource) bool) {
+		_ = fs.WalkDir(fsys, ".", func(name string, d fs.DirEntry, err error) error {
+			if err != nil {
+				if !yield(BatchSource{Name: name, Err: err}) {
+					return fs.SkipAll
+				}
+				return nil
+			}
+			if d.IsDir() || path.Ext(name) != ext {
+				return nil
+			}
+			source, err := fs.ReadFile(fsys, name)
+			if !yield(BatchSource{Name: name, Source: source, Err: err}) {
+				return fs.SkipAll
+			}
+			return nil
+		})
+	}
+}
+
+// BatchOutput receives rendered contents of the named source.
+// output is valid only until the function returns.
+// BatchOutput is called concurrently from multiple goroutines.
+type BatchOutput func(name string, output []byte) error
+
+// ErrNotLocalName is returned by BatchOutputs of FileOutput when the name of
+// the source is not a local path, like "../a.md" or "/a.md".
+var ErrNotLocalName = errors.New("goldmark: name of the source is not a local path")
+
+// FileOutput returns BatchOutput that writes rendered contents to files
+// under the given directory. Paths of the files are names of the sources
+// whose extensions are replaced with the given extension, like ".html".
+// Directories are created as needed. Names that escape the directory are
+// rejected with ErrNotLocalName.
+func FileOutput(dir string, ext string) BatchOutput {
+	return func(name string, output []byte) error {
+		rel := filepath.FromSlash(strings.TrimSuffix(name, path.Ext(name)) + ext)
+		if !filepath.IsLocal(rel) {
+			return ErrNotLocalName
+		}
+		p := filepath.Join(dir, rel)
+		if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+			return err
+		}
+		return os.WriteFile(p, output, 0o644)
+	}
+}
+
+// A BatchConfig struct is a data structure that holds configuration of
+// ConvertBatch.
+type BatchConfig struct {
+	// Parallelism is the maximum number of sources that are converted at
+	// the same time. Defaults to runtime.GOMAXPROCS(0).
+	Parallelism int
+}
+
+// A BatchOption is a functional option type for ConvertBatch.
+type BatchOption func(*BatchConfig)
+
+// WithParallelism is a functional option that limits the number of sources
+// that are converted at the same time.
+func WithParallelism(n int) BatchOption {
+	return func(c *BatchConfig) {
+		c.Parallelism = n
+	}
+}
+
+// A BatchError struct is an error occurred while converting the named
+// source.
+type BatchError struct {
+	// Name is a name of the source.
+	Name string
+
+	// Err is the error.
+	Err error
+
+	index int
+}
+
+// Error implements error.Error.
+func (e *BatchError) Error() string {
+	return e.Name + ": " + e.Err.Error()
+}
+
+// Unwrap returns the underlying error.
+func (e *BatchError) Unwrap() error {
+	return e.Err
+}
+
+// A PanicError struct is an error that reports a panic recovered while
+// converting a source.
+type PanicError struct {
+	// Value is the value passed to panic.
+	Value any
+
+	// Stack is the stack trace of the goroutine that panicked.
+	Stack []byte
+}
+
+// Error implements error.Error.
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("panic: %v", e.Value)
+}
+
+// A BatchStats struct holds statistics of ConvertBatch.
+type BatchStats struct {
+	// Documents is the number of sources including failed sources.
+	Documents int
+
+	// Failed is the number of sources that are failed.
+	Failed int
+
+	// Bytes is the total size of the sources.
+	Bytes int64
+
+	// Elapsed is the wall clock time of ConvertBatch.
+	Elapsed time.Duration
+
+	// Parse, Render and Output are the total time spent for parsing,
+	// rendering and writing outputs of the sources. They may be greater
+	// than Elapsed because sources are converted in parallel.
+	Parse, Render, Output time.Duration
+
+	// Slowest is the name of the source that took the longest time to
+	// convert, and SlowestDuration is the time.
+	Slowest         string
+	SlowestDuration time.Duration
+
+	// Errors are errors of the failed sources in the order of the sources.
+	Errors []*BatchError
+}
+
+// ConvertBatch converts the given sources with the given Markdown object in
+// parallel and passes rendered contents to output.
+//
+// Parsers and renderers of goldmark are safe for concurrent use once they
+// are configured, so m is shared by all goroutines. Errors of the sources
+// do not stop the conversion; they are aggregated in BatchStats.Errors and
+// the returned error that joins them. Panics while converting a source are
+// recovered and reported as PanicErrors of the source.
+func ConvertBatch(m Markdown, sources BatchSources, output BatchOutput, opts ...BatchOption) (*BatchStats, error) {
+	c := &BatchConfig{
+		Parallelism: runtime.GOMAXPROCS(0),
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	parallelism := max(c.Parallelism, 1)
+
+	type job struct {
+		index  int
+		source BatchSource
+	}
+	stats := &BatchStats{}
+	start := time.Now()
+	jobs := make(chan job)
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for i := 0; i < parallelism; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			var buf bytes.Buffer
+			for j := range jobs {
+				buf.Reset()
+				r := convertBatchSource(m, j.source, output, &buf)
+				mu.Lock()
+				stats.add(j.index, j.source, r)
+				mu.Unlock()
+			}
+		}()
+	}
+	index := 0
+	sources(func(s BatchSource) bool {
+		jobs <- job{index, s}
+		index++
+		return true
+	})
+	close(jobs)
+	wg.Wait()
+	stats.Elapsed = time.Since(start)
+
+	sort.Slice(stats.Errors, func(i, j int) bool {
+		return stats.Errors[i].index < stats.Errors[j].index
+	})
+	errs := make([]error, len(stats.Errors))
+	for i, err := range stats.Errors {
+		errs[i] = err
+	}
+	return stats, errors.Join(errs...)
+}
+
+type batchResult struct {
+	parse, render, output time.Duration
+	err                   error
+}
+
+func convertBatchSource(m Markdown, s BatchSource, output BatchOutput, buf *bytes.Buffer) (r batchResult) {
+	defer func() {
+		if v := recover(); v != nil {
+			r.err = &PanicError{Value: v, Stack: debug.Stack()}
+		}
+	}()
+	if s.Err != nil {
+		r.err = s.Err
+		return r
+	}
+	t := time.Now()
+	doc := m.Parser().Parse(text.NewReader(s.Source))
+	r.parse = time.Since(t)
+
+	t = time.Now()
+	r.err = m.Renderer().Render(buf, s.Source, doc)
+	r.render = time.Since(t)
+	if r.err != nil {
+		return r
+	}
+
+	t = time.Now()
+	r.err = output(s.Name, buf.Bytes())
+	r.output = time.Since(t)
+	return r
+}
+
+func (s *BatchStats) add(index int, source BatchSource, r batchResult) {
+	s.Documents++
+	s.Bytes += int64(len(source.Source))
+	s.Parse += r.parse
+	s.Render += r.render
+	s.Output += r.output
+	if d := r.parse + r.render + r.output; d > s.SlowestDuration {
+		s.Slowest = source.Name
+		s.SlowestDuration = d
+	}
+	if r.err != nil {
+		s.Failed++
+		s.Errors = append(s.Errors, &BatchError{Name: source.Name, Err: r.err, index: index})
+	}
+}
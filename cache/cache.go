@@ -0,0 +1,157 @@
+// Package cache implements a cache of converted Markdown documents.
+package cache
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"sync"
+
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/renderer"
+	"github.com/yuin/goldmark/text"
+	"github.com/yuin/goldmark/util"
+)
+
+// An Entry struct is a cached result of a conversion.
+type Entry struct {
+	// Output is rendered contents.
+	Output []byte
+
+	// Document is a parsed document. Document is nil unless WithDocuments
+	// is specified and the store can hold documents.
+	Document ast.Node
+}
+
+// A Store interface stores cached entries. Stores must be safe for
+// concurrent use.
+type Store interface {
+	// Get returns an entry associated with the given key.
+	Get(key string) (*Entry, bool)
+
+	// Put associates the given entry with the given key.
+	// Stores may discard entries at any time.
+	Put(key string, entry *Entry)
+}
+
+// A Config struct is a data structure that holds configuration of the
+// Markdown.
+type Config struct {
+	// Documents is true if parsed documents are cached.
+	Documents bool
+}
+
+// An Option is a functional option type for the Markdown.
+type Option func(*Config)
+
+// WithDocuments is a functional option that caches parsed documents in
+// addition to rendered contents. Cached documents are shared, so they must
+// not be modified.
+func WithDocuments() Option {
+	return func(c *Config) {
+		c.Documents = true
+	}
+}
+
+// Markdown is a goldmark.Markdown that caches converted documents.
+//
+// Cache keys are fingerprints of sources and configurations of the parser
+// and the renderer, so entries are invalidated when the parser or the
+// renderer is replaced with one that has a different configuration.
+// Parsers and renderers that do not implement util.Fingerprinter are
+// fingerprinted by util.Fingerprint.
+//
+// Configurations that have closures, like functions given by
+// extension.WithFootnoteIDPrefixFunction, have fingerprints unique to the
+// parser and the renderer, so their entries are not shared with other
+// Markdown objects or processes.
+type Markdown struct {
+	goldmark.Markdown
+	config Config
+	store  Store
+
+	mu                sync.Mutex
+	parser            parser.Parser
+	renderer          renderer.Renderer
+	configFingerprint []byte
+}
+
+// New returns a new Markdown that caches documents converted by the given
+// Markdown in the given store.
+func New(m goldmark.Markdown, store Store, opts ...Option) *Markdown {
+	c := &Markdown{
+		Markdown: m,
+		store:    store,
+	}
+	for _, opt := range opts {
+		opt(&c.config)
+	}
+	return c
+}
+
+// Key returns a cache key of the given source.
+func (m *Markdown) Key(source []byte) string {
+	h := sha256.New()
+	_, _ = h.Write(m.fingerprint())
+	_, _ = h.Write(source)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// fingerprint returns a fingerprint of the current parser and renderer.
+func (m *Markdown) fingerprint() []byte {
+	p, r := m.Parser(), m.Renderer()
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.configFingerprint == nil || m.parser != p || m.renderer != r {
+		m.parser, m.renderer = p, r
+		m.configFingerprint = append(fingerprintOf(p), fingerprintOf(r)...)
+	}
+	return m.configFingerprint
+}
+
+func fingerprintOf(v any) []byte {
+	if f, ok := v.(util.Fingerprinter); ok {
+		return f.Fingerprint()
+	}
+	return util.Fingerprint(v)
+}
+
+// Convert interprets a UTF-8 bytes source in Markdown and writes rendered
+// contents to a writer w. Rendered contents are cached unless opts are
+// given, because parse options like contexts may change results.
+func (m *Markdown) Convert(source []byte, w io.Writer, opts ...parser.ParseOption) error {
+	if len(opts) != 0 {
+		return m.Markdown.Convert(source, w, opts...)
+	}
+	key := m.Key(source)
+	if entry, ok := m.store.Get(key); ok {
+		_, err := w.Write(entry.Output)
+		return err
+	}
+	doc := m.Parser().Parse(text.NewReader(source))
+	var buf bytes.Buffer
+	if err := m.Renderer().Render(&buf, source, doc); err != nil {
+		return err
+	}
+	entry := &Entry{Output: buf.Bytes()}
+	if m.config.Documents {
+		entry.Document = doc
+	}
+	m.store.Put(key, entry)
+	_, err := w.Write(entry.Output)
+	return err
+}
+
+// Parse parses the given source. A cached document is returned if
+// WithDocuments is specified and the document is cached.
+func (m *Markdown) Parse(source []byte) ast.Node {
+	if m.config.Documents {
+		if entry, ok := m.store.Get(m.Key(source)); ok && entry.Document != nil {
+			return entry.Document
+		}
+	}
+	return m.Parser().Parse(text.NewReader(source))
+}
@@ -0,0 +1,127 @@
+package cache_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/yuin/goldmark"
+	gast "github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/cache"
+	"github.com/yuin/goldmark/extension"
+	"github.com/yuin/goldmark/renderer/html"
+	"github.com/yuin/goldmark/util"
+)
+
+// countingStore counts hits of the underlying store.
+type countingStore struct {
+	cache.Store
+	hits int
+}
+
+func (s *countingStore) Get(key string) (*cache.Entry, bool) {
+	entry, ok := s.Store.Get(key)
+	if ok {
+		s.hits++
+	}
+	return entry, ok
+}
+
+func convert(t *testing.T, m goldmark.Markdown, source string) string {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := m.Convert([]byte(source), &buf); err != nil {
+		t.Fatal(err)
+	}
+	return buf.String()
+}
+
+func TestLRUStore(t *testing.T) {
+	store := &countingStore{Store: cache.NewLRUStore(2)}
+	m := cache.New(goldmark.New(goldmark.WithExtensions(extension.Strikethrough)), store, cache.WithDocuments())
+	if s := convert(t, m, "~~a~~"); s != "<p><del>a</del></p>\n" {
+		t.Errorf("unexpected output: %q", s)
+	}
+	if s := convert(t, m, "~~a~~"); s != "<p><del>a</del></p>\n" || store.hits != 1 {
+		t.Errorf("output should be cached: %q, %d", s, store.hits)
+	}
+	if m.Parse([]byte("~~a~~")) != m.Parse([]byte("~~a~~")) {
+		t.Error("documents should be cached")
+	}
+
+	convert(t, m, "b")
+	convert(t, m, "c")
+	store.hits = 0
+	convert(t, m, "~~a~~")
+	if store.hits != 0 {
+		t.Error("least recently used entries should be discarded")
+	}
+
+	// replacing the renderer invalidates entries. The new renderer does not
+	// render strikethroughs.
+	m.SetRenderer(goldmark.New().Renderer())
+	store.hits = 0
+	if s := convert(t, m, "~~a~~"); s != "<p>a</p>\n" || store.hits != 0 {
+		t.Errorf("entries should be invalidated: %q, %d", s, store.hits)
+	}
+}
+
+func TestDirStore(t *testing.T) {
+	dir := t.TempDir()
+	newMarkdown := func(opts ...goldmark.Option) (*cache.Markdown, *countingStore) {
+		store := &countingStore{Store: cache.NewDirStore(dir)}
+		return cache.New(goldmark.New(opts...), store), store
+	}
+
+	m, _ := newMarkdown(goldmark.WithRendererOptions(html.WithHardWraps()))
+	convert(t, m, "a\nb")
+	files, _ := os.ReadDir(dir)
+	if len(files) != 1 || filepath.Ext(files[0].Name()) == ".tmp" {
+		t.Fatalf("an entry should be written: %v", files)
+	}
+
+	// entries are shared between Markdown objects that have the same
+	// configuration.
+	m, store := newMarkdown(goldmark.WithRendererOptions(html.WithHardWraps()))
+	if s := convert(t, m, "a\nb"); s != "<p>a<br>\nb</p>\n" || store.hits != 1 {
+		t.Errorf("output should be cached: %q, %d", s, store.hits)
+	}
+
+	m, store = newMarkdown()
+	if s := convert(t, m, "a\nb"); s != "<p>a\nb</p>\n" || store.hits != 0 {
+		t.Errorf("entries of other configurations should not be used: %q, %d", s, store.hits)
+	}
+}
+
+func TestKey(t *testing.T) {
+	source := []byte("a[^1]\n\n[^1]: b\n")
+	newMarkdown := func(prefix string) *cache.Markdown {
+		return cache.New(goldmark.New(goldmark.WithExtensions(extension.NewFootnote(
+			extension.WithFootnoteIDPrefixFunction(func(gast.Node) []byte {
+				return []byte(prefix)
+			}),
+		))), cache.NewLRUStore(1))
+	}
+	// closures from the same line may capture different values.
+	if newMarkdown("a").Key(source) == newMarkdown("b").Key(source) {
+		t.Error("configurations that have closures should not share keys")
+	}
+	m := newMarkdown("a")
+	if m.Key(source) != m.Key(source) {
+		t.Error("keys should be stable")
+	}
+
+	// maps are fingerprinted by their contents instead of addresses.
+	type key struct{ v int }
+	newMap := func() map[*key]int {
+		m := map[*key]int{}
+		for i := range 100 {
+			m[&key{i}] = i
+		}
+		return m
+	}
+	if !bytes.Equal(util.Fingerprint(newMap()), util.Fingerprint(newMap())) {
+		t.Error("fingerprints of maps should not depend on addresses")
+	}
+}
@@ -0,0 +1,103 @@
+package cache
+
+import (
+	"container/list"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// lruItem is an element of the list of the lruStore.
+type lruItem struct {
+	key   string
+	entry *Entry
+}
+
+type lruStore struct {
+	capacity int
+	mu       sync.Mutex
+	items    map[string]*list.Element
+	order    *list.List
+}
+
+// NewLRUStore returns a new in-memory Store that holds at most capacity
+// entries. Least recently used entries are discarded first.
+func NewLRUStore(capacity int) Store {
+	return &lruStore{
+		capacity: max(capacity, 1),
+		items:    map[string]*list.Element{},
+		order:    list.New(),
+	}
+}
+
+func (s *lruStore) Get(key string) (*Entry, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	e, ok := s.items[key]
+	if !ok {
+		return nil, false
+	}
+	s.order.MoveToFront(e)
+	return e.Value.(*lruItem).entry, true
+}
+
+func (s *lruStore) Put(key string, entry *Entry) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if e, ok := s.items[key]; ok {
+		e.Value.(*lruItem).entry = entry
+		s.order.MoveToFront(e)
+		return
+	}
+	s.items[key] = s.order.PushFront(&lruItem{key, entry})
+	for s.order.Len() > s.capacity {
+		last := s.order.Back()
+		s.order.Remove(last)
+		delete(s.items, last.Value.(*lruItem).key)
+	}
+}
+
+type dirStore struct {
+	dir string
+}
+
+// NewDirStore returns a new Store that stores rendered contents as files in
+// the given directory. Documents are not stored. Errors of reading and
+// writing files are ignored, so failed entries are just not cached.
+func NewDirStore(dir string) Store {
+	return &dirStore{dir}
+}
+
+func (s *dirStore) path(key string) string {
+	return filepath.Join(s.dir, key)
+}
+
+func (s *dirStore) Get(key string) (*Entry, bool) {
+	output, err := os.ReadFile(s.path(key))
+	if err != nil {
+		return nil, false
+	}
+	return &Entry{Output: output}, true
+}
+
+func (s *dirStore) Put(key string, entry *Entry) {
+	if err := os.MkdirAll(s.dir, 0o755); err != nil {
+		return
+	}
+	// entries are written to temporary files first so that readers do not
+	// read partially written entries.
+	f, err := os.CreateTemp(s.dir, key+".*.tmp")
+	if err != nil {
+		return
+	}
+	_, err = f.Write(entry.Output)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err == nil {
+		err = os.Rename(f.Name(), s.path(key))
+	}
+	if err != nil {
+		_ = os.Remove(f.Name())
+	}
+}
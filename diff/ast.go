@@ -0,0 +1,113 @@
+package diff
+
+import (
+	"fmt"
+
+	"github.com/yuin/goldmark/ast"
+)
+
+// Classes that are added to class attributes of changed nodes.
+const (
+	// ClassInserted is a class of inserted contents.
+	ClassInserted = "diff-inserted"
+
+	// ClassDeleted is a class of deleted contents.
+	ClassDeleted = "diff-deleted"
+
+	// ClassChanged is a class of blocks whose contents are changed.
+	ClassChanged = "diff-changed"
+
+	// ClassMoved is a class of blocks that are moved from other positions.
+	ClassMoved = "diff-moved"
+)
+
+// An Inserted struct represents contents that exist only in the new
+// document.
+type Inserted struct {
+	ast.BaseBlock
+
+	// IsBlock is true if this node wraps blocks.
+	IsBlock bool
+}
+
+// Type implements Node.Type.
+func (n *Inserted) Type() ast.NodeType {
+	if n.IsBlock {
+		return ast.TypeBlock
+	}
+	return ast.TypeInline
+}
+
+// Dump implements Node.Dump.
+func (n *Inserted) Dump(source []byte, level int) {
+	m := map[string]string{
+		"IsBlock": fmt.Sprintf("%v", n.IsBlock),
+	}
+	ast.DumpHelper(n, source, level, m, nil)
+}
+
+// KindInserted is a NodeKind of the Inserted node.
+var KindInserted = ast.NewNodeKind("Inserted")
+
+// Kind implements Node.Kind.
+func (n *Inserted) Kind() ast.NodeKind {
+	return KindInserted
+}
+
+// NewInserted returns a new Inserted node.
+func NewInserted(isBlock bool) *Inserted {
+	return &Inserted{
+		IsBlock: isBlock,
+	}
+}
+
+// A Deleted struct represents contents that exist only in the old document.
+// Children of a Deleted node are nodes of the old document, so they refer
+// to the source of the old document.
+type Deleted struct {
+	ast.BaseBlock
+
+	// IsBlock is true if this node wraps blocks.
+	IsBlock bool
+
+	// Source is a source of the old document.
+	Source []byte
+}
+
+// OwnSource implements ast.SourceOwner.OwnSource.
+func (n *Deleted) OwnSource() []byte {
+	return n.Source
+}
+
+// Type implements Node.Type.
+func (n *Deleted) Type() ast.NodeType {
+	if n.IsBlock {
+		return ast.TypeBlock
+	}
+	return ast.TypeInline
+}
+
+// Dump implements Node.Dump.
+func (n *Deleted) Dump(source []byte, level int) {
+	m := map[string]string{
+		"IsBlock": fmt.Sprintf("%v", n.IsBlock),
+	}
+	ast.DumpHelper(n, n.Source, level, m, nil)
+}
+
+// KindDeleted is a NodeKind of the Deleted node.
+var KindDeleted = ast.NewNodeKind("Deleted")
+
+// Kind implements Node.Kind.
+func (n *Deleted) Kind() ast.NodeKind {
+	return KindDeleted
+}
+
+// NewDeleted returns a new Deleted node that wraps nodes of the old
+// document parsed from the given source.
+func NewDeleted(isBlock bool, source []byte) *Deleted {
+	return &Deleted{
+		IsBlock: isBlock,
+		Source:  source,
+	}
+}
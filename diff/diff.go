@@ -0,0 +1,761 @@
+// Package diff implements a diff of parsed Markdown documents.
+//
+// Diff aligns blocks of two documents and then texts of changed blocks, and
+// merges the documents into a document that has Inserted and Deleted nodes.
+// The merged document is rendered with the Extension as an HTML document
+// that has ins and del elements.
+package diff
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/yuin/goldmark/ast"
+	east "github.com/yuin/goldmark/extension/ast"
+	"github.com/yuin/goldmark/text"
+	"github.com/yuin/goldmark/util"
+)
+
+// minSimilarity is a minimum similarity of blocks that are regarded as a
+// changed block instead of a deleted block and an inserted block.
+const minSimilarity = 0.5
+
+// maxLCSTable is a maximum size of tables that are used to find longest
+// common subsequences. Larger sequences are aligned greedily.
+const maxLCSTable = 1 << 22
+
+// Diff compares the old document parsed from oldSource with the new
+// document parsed from newSource, and returns a merged document.
+//
+// The merged document is the new document that has Inserted and Deleted
+// nodes, so it must be rendered with newSource. Deleted nodes hold nodes of
+// the old document with oldSource. Both documents are modified.
+//
+// Blocks are aligned first. Blocks that have similar contents are regarded
+// as changed blocks, and their texts are aligned word by word. Blocks that
+// are moved without changes are marked with ClassMoved instead of being
+// deleted and inserted. Rows of tables are aligned like blocks.
+func Diff(oldDoc ast.Node, oldSource []byte, newDoc ast.Node, newSource []byte) ast.Node {
+	d := &differ{
+		oldSource: oldSource,
+		newSource: newSource,
+		words:     map[ast.Node][]string{},
+	}
+	d.diffBlocks(oldDoc, newDoc)
+	return newDoc
+}
+
+type differ struct {
+	oldSource []byte
+	newSource []byte
+
+	// words caches words of blocks for similarity calculations.
+	words map[ast.Node][]string
+}
+
+// diffBlocks aligns children of the given blocks.
+func (d *differ) diffBlocks(oldParent, newParent ast.Node) {
+	olds, news := blocks(oldParent), blocks(newParent)
+	oldKeys := make([]string, len(olds))
+	for i, n := range olds {
+		oldKeys[i] = d.blockKey(n, d.oldSource)
+	}
+	newKeys := make([]string, len(news))
+	for i, n := range news {
+		newKeys[i] = d.blockKey(n, d.newSource)
+	}
+	pairs := lcs(oldKeys, newKeys)
+
+	// blocks that are not aligned but have the same contents are moved.
+	matchedOld := make([]bool, len(olds))
+	matchedNew := make([]bool, len(news))
+	for _, p := range pairs {
+		matchedOld[p[0]] = true
+		matchedNew[p[1]] = true
+	}
+	unmatched := map[string]int{}
+	for i, key := range oldKeys {
+		if !matchedOld[i] {
+			unmatched[key]++
+		}
+	}
+	moved := map[string]int{}
+	for j, key := range newKeys {
+		if !matchedNew[j] && unmatched[key] > 0 {
+			unmatched[key]--
+			moved[key]++
+			matchedNew[j] = true
+			d.markInserted(news[j], ClassMoved)
+		}
+	}
+	for i, key := range oldKeys {
+		if !matchedOld[i] && moved[key] > 0 {
+			moved[key]--
+			matchedOld[i] = true
+		}
+	}
+
+	oi, ni := 0, 0
+	for _, p := range append(pairs, [2]int{len(olds), len(news)}) {
+		var anchor ast.Node
+		if p[1] < len(news) {
+			anchor = news[p[1]]
+		}
+		var gapOlds, gapNews []ast.Node
+		for ; oi < p[0]; oi++ {
+			if !matchedOld[oi] {
+				gapOlds = append(gapOlds, olds[oi])
+			}
+		}
+		for ; ni < p[1]; ni++ {
+			if !matchedNew[ni] {
+				gapNews = append(gapNews, news[ni])
+			}
+		}
+		d.diffGap(newParent, gapOlds, gapNews, anchor)
+		oi, ni = p[0]+1, p[1]+1
+	}
+}
+
+// diffGap merges blocks between aligned blocks. Deleted blocks are inserted
+// before the anchor, or appended to the parent if anchor is nil.
+func (d *differ) diffGap(parent ast.Node, olds, news []ast.Node, anchor ast.Node) {
+	if len(olds) == 0 && len(news) == 0 {
+		return
+	}
+	paired := make([]int, len(news))
+	k := 0
+	for j, n := range news {
+		paired[j] = -1
+		for i := k; i < len(olds); i++ {
+			if d.similar(olds[i], n) {
+				paired[j] = i
+				k = i + 1
+				break
+			}
+		}
+	}
+	i := 0
+	for j, n := range news {
+		// deleted blocks precede inserted blocks.
+		next := len(olds)
+		for l := j; l < len(news); l++ {
+			if paired[l] > -1 {
+				next = paired[l]
+				break
+			}
+		}
+		for ; i < next; i++ {
+			d.markDeleted(parent, olds[i], n)
+		}
+		if paired[j] < 0 {
+			d.markInserted(n, ClassInserted)
+			continue
+		}
+		d.diffChanged(olds[i], n)
+		i++
+	}
+	for ; i < len(olds); i++ {
+		d.markDeleted(parent, olds[i], anchor)
+	}
+}
+
+// diffChanged merges the given blocks that have similar contents.
+func (d *differ) diffChanged(oldBlock, newBlock ast.Node) {
+	addClass(newBlock, ClassChanged)
+	switch {
+	case isRow(newBlock):
+		d.diffCells(oldBlock, newBlock)
+	case hasInlines(oldBlock) || hasInlines(newBlock):
+		d.diffInlines(oldBlock, newBlock)
+	default:
+		d.diffBlocks(oldBlock, newBlock)
+	}
+}
+
+// diffCells merges cells of the given table rows column by column.
+func (d *differ) diffCells(oldRow, newRow ast.Node) {
+	oc := oldRow.FirstChild()
+	for nc := newRow.FirstChild(); nc != nil; nc = nc.NextSibling() {
+		if oc == nil {
+			wrapChildren(nc, NewInserted(false), ClassInserted)
+			continue
+		}
+		next := oc.NextSibling()
+		if d.blockKey(oc, d.oldSource) != d.blockKey(nc, d.newSource) {
+			d.diffInlines(oc, nc)
+		}
+		oc = next
+	}
+}
+
+// markInserted marks the given block as an inserted block.
+func (d *differ) markInserted(n ast.Node, class string) {
+	if isItem(n) {
+		addClass(n, class)
+		if class == ClassInserted {
+			wrapContents(n, func(c ast.Node, isBlock bool) ast.Node {
+				return NewInserted(isBlock)
+			}, class)
+		}
+		return
+	}
+	w := NewInserted(true)
+	addClass(w, class)
+	n.Parent().InsertBefore(n.Parent(), n, w)
+	w.AppendChild(w, n)
+}
+
+// markDeleted moves the given block of the old document into the parent
+// before the given node of the new document.
+func (d *differ) markDeleted(parent ast.Node, n ast.Node, before ast.Node) {
+	source := ast.SourceOf(n, d.oldSource)
+	var x ast.Node
+	if isItem(n) {
+		addClass(n, ClassDeleted)
+		wrapContents(n, func(c ast.Node, isBlock bool) ast.Node {
+			if isBlock {
+				return NewDeleted(true, ast.SourceOf(c, source))
+			}
+			return NewDeleted(false, sourceOf(c, source))
+		}, ClassDeleted)
+		n.Parent().RemoveChild(n.Parent(), n)
+		x = n
+	} else {
+		w := NewDeleted(true, source)
+		addClass(w, ClassDeleted)
+		w.AppendChild(w, n)
+		x = w
+	}
+	if before != nil {
+		parent.InsertBefore(parent, before, x)
+	} else {
+		parent.AppendChild(parent, x)
+	}
+}
+
+// similar returns true if the given blocks should be merged as a changed
+// block.
+func (d *differ) similar(oldBlock, newBlock ast.Node) bool {
+	if oldBlock.Kind() != newBlock.Kind() || oldBlock.IsRaw() ||
+		!oldBlock.HasChildren() || !newBlock.HasChildren() {
+		return false
+	}
+	if oldBlock.Kind() == east.KindTableHeader {
+		return true
+	}
+	a, b := d.blockWords(oldBlock, d.oldSource), d.blockWords(newBlock, d.newSource)
+	if len(a)+len(b) == 0 {
+		return true
+	}
+	return float64(2*len(lcs(a, b)))/float64(len(a)+len(b)) >= minSimilarity
+}
+
+// blockWords returns words of texts in the given block.
+func (d *differ) blockWords(n ast.Node, source []byte) []string {
+	if words, ok := d.words[n]; ok {
+		return words
+	}
+	var words []string
+	_ = ast.Walk(n, func(c ast.Node, entering bool) (ast.WalkStatus, error) {
+		if !entering || !hasInlines(c) {
+			return ast.WalkContinue, nil
+		}
+		for _, t := range tokenize(c, sourceOf(c, source)) {
+			if t.key != " " {
+				words = append(words, t.key)
+			}
+		}
+		return ast.WalkSkipChildren, nil
+	})
+	d.words[n] = words
+	return words
+}
+
+// blockKey returns a string that identifies contents of the given block.
+func (d *differ) blockKey(n ast.Node, source []byte) string {
+	var b strings.Builder
+	_ = ast.Walk(n, func(c ast.Node, entering bool) (ast.WalkStatus, error) {
+		if !entering {
+			_ = b.WriteByte(')')
+			return ast.WalkContinue, nil
+		}
+		_, _ = b.WriteString(c.Kind().String())
+		switch v := c.(type) {
+		case *ast.Heading:
+			fmt.Fprintf(&b, ":%d", v.Level)
+		case *ast.List:
+			fmt.Fprintf(&b, ":%c%d", v.Marker, v.Start)
+		case *ast.FencedCodeBlock:
+			if v.Info != nil {
+				_, _ = b.Write(v.Info.Value(source))
+			}
+		}
+		_ = b.WriteByte('(')
+		s := sourceOf(c, source)
+		if c.IsRaw() {
+			lines := c.Lines()
+			for i := range lines.Len() {
+				at := lines.At(i)
+				_, _ = b.Write(at.Value(s))
+			}
+			return ast.WalkSkipChildren, nil
+		}
+		if hasInlines(c) {
+			for _, t := range tokenize(c, s) {
+				_, _ = b.WriteString(t.key)
+				_ = b.WriteByte(0)
+			}
+			return ast.WalkSkipChildren, nil
+		}
+		return ast.WalkContinue, nil
+	})
+	return b.String()
+}
+
+// A token is a unit of inline diffs.
+type token struct {
+	key string
+
+	// node is a node that this token belongs to.
+	node ast.Node
+
+	// segment is a segment of the text if node is an *ast.Text.
+	segment text.Segment
+
+	// isText is true if this token is a part of an *ast.Text.
+	isText bool
+
+	// isBreak is true if this token is a line break of an *ast.Text.
+	isBreak bool
+}
+
+// tokenize splits inline children of the given block into tokens. Texts are
+// split into words, punctuations and spaces.
+func tokenize(block ast.Node, source []byte) []token {
+	var tokens []token
+	tokenizeInlines(block, source, "", &tokens)
+	return tokens
+}
+
+func tokenizeInlines(parent ast.Node, source []byte, context string, tokens *[]token) {
+	for c := parent.FirstChild(); c != nil; c = c.NextSibling() {
+		switch v := c.(type) {
+		case *ast.Text:
+			value := v.Segment.Value(source)
+			if v.Segment.Padding != 0 {
+				*tokens = append(*tokens, token{key: context + string(value), node: v})
+			} else {
+				for i := 0; i < len(value); {
+					j := i + 1
+					key := " "
+					switch {
+					case util.IsSpace(value[i]):
+						for j < len(value) && util.IsSpace(value[j]) {
+							j++
+						}
+					case util.IsPunct(value[i]):
+						key = context + string(value[i:j])
+					default:
+						for j < len(value) && !util.IsSpace(value[j]) && !util.IsPunct(value[j]) {
+							j++
+						}
+						key = context + string(value[i:j])
+					}
+					*tokens = append(*tokens, token{
+						key:     key,
+						node:    v,
+						segment: text.NewSegment(v.Segment.Start+i, v.Segment.Start+j),
+						isText:  true,
+					})
+					i = j
+				}
+			}
+			if v.SoftLineBreak() || v.HardLineBreak() {
+				*tokens = append(*tokens, token{key: " ", node: v, isBreak: true})
+			}
+		case *ast.String:
+			*tokens = append(*tokens, token{key: context + string(v.Value), node: v})
+		default:
+			if isAtomic(c) {
+				*tokens = append(*tokens, token{key: context + atomicKey(c, source), node: c})
+			} else {
+				tokenizeInlines(c, source, context+containerKey(c)+"\x00", tokens)
+			}
+		}
+	}
+}
+
+// isAtomic returns true if the given inline node is compared as a whole.
+func isAtomic(n ast.Node) bool {
+	switch n.Kind() {
+	case ast.KindCodeSpan, ast.KindImage, ast.KindAutoLink, ast.KindRawHTML:
+		return true
+	}
+	return !n.HasChildren()
+}
+
+func atomicKey(n ast.Node, source []byte) string {
+	var b strings.Builder
+	_, _ = b.WriteString(n.Kind().String())
+	_ = b.WriteByte(':')
+	switch v := n.(type) {
+	case *ast.Image:
+		_, _ = b.Write(v.Destination)
+		_ = b.WriteByte(0)
+	case *ast.AutoLink:
+		_, _ = b.Write(v.URL(source))
+	case *ast.RawHTML:
+		for i := range v.Segments.Len() {
+			at := v.Segments.At(i)
+			_, _ = b.Write(at.Value(source))
+		}
+	case *east.TaskCheckBox:
+		fmt.Fprintf(&b, "%v", v.IsChecked)
+	}
+	for _, t := range tokenize(n, source) {
+		_, _ = b.WriteString(t.key)
+	}
+	return b.String()
+}
+
+func containerKey(n ast.Node) string {
+	switch v := n.(type) {
+	case *ast.Emphasis:
+		return fmt.Sprintf("%s:%d", v.Kind(), v.Level)
+	case *ast.Link:
+		return fmt.Sprintf("%s:%s", v.Kind(), v.Destination)
+	}
+	return n.Kind().String()
+}
+
+// diffInlines aligns inline children of the given blocks word by word.
+func (d *differ) diffInlines(oldBlock, newBlock ast.Node) {
+	oldSource := sourceOf(oldBlock, d.oldSource)
+	olds := tokenize(oldBlock, oldSource)
+	news := tokenize(newBlock, sourceOf(newBlock, d.newSource))
+	pairs := lcs(keys(olds), keys(news))
+	if len(pairs) == len(olds) && len(pairs) == len(news) {
+		return
+	}
+	splitTexts(news)
+
+	var deleted []token
+	var inserted *Inserted
+	flush := func(t token) {
+		if len(deleted) == 0 {
+			return
+		}
+		x := newDeletedTokens(deleted, oldBlock, oldSource)
+		deleted = deleted[:0]
+		if t.isBreak {
+			n := outer(t.node)
+			n.Parent().InsertAfter(n.Parent(), n, x)
+		} else {
+			t.node.Parent().InsertBefore(t.node.Parent(), t.node, x)
+		}
+	}
+	oi, ni := 0, 0
+	for _, p := range append(pairs, [2]int{len(olds), len(news)}) {
+		deleted = append(deleted, olds[oi:p[0]]...)
+		for ; ni < p[1]; ni++ {
+			t := news[ni]
+			flush(t)
+			if t.isBreak {
+				continue
+			}
+			if inserted == nil || t.node.PreviousSibling() != inserted {
+				inserted = NewInserted(false)
+				addClass(inserted, ClassInserted)
+				t.node.Parent().InsertBefore(t.node.Parent(), t.node, inserted)
+			}
+			inserted.AppendChild(inserted, t.node)
+		}
+		if p[1] < len(news) {
+			flush(news[p[1]])
+		}
+		oi, ni = p[0]+1, p[1]+1
+	}
+	if len(deleted) != 0 {
+		x := newDeletedTokens(deleted, oldBlock, oldSource)
+		if len(news) == 0 {
+			newBlock.AppendChild(newBlock, x)
+		} else {
+			n := outer(news[len(news)-1].node)
+			n.Parent().InsertAfter(n.Parent(), n, x)
+		}
+	}
+}
+
+// splitTexts splits text nodes of the given tokens so that each token has
+// its own node. Line breaks remain on the last nodes.
+func splitTexts(tokens []token) {
+	for i := 0; i < len(tokens); {
+		t, ok := tokens[i].node.(*ast.Text)
+		j := i + 1
+		for j < len(tokens) && tokens[j].node == tokens[i].node {
+			j++
+		}
+		if !ok || !tokens[i].isText || j-i == 1 {
+			i = j
+			continue
+		}
+		soft, hard := t.SoftLineBreak(), t.HardLineBreak()
+		t.SetSoftLineBreak(false)
+		t.SetHardLineBreak(false)
+		last := t
+		for k := i; k < j; k++ {
+			switch {
+			case tokens[k].isBreak:
+				tokens[k].node = last
+			case k == i:
+				t.Segment = tokens[k].segment
+			default:
+				piece := ast.NewTextSegment(tokens[k].segment)
+				piece.SetRaw(t.IsRaw())
+				last.Parent().InsertAfter(last.Parent(), last, piece)
+				last = piece
+				tokens[k].node = piece
+			}
+		}
+		last.SetSoftLineBreak(soft)
+		last.SetHardLineBreak(hard)
+		i = j
+	}
+}
+
+// newDeletedTokens returns a Deleted node that has the given tokens of the
+// given old block. Inline containers of the tokens like emphases are copied,
+// so changes of formatting like '**a**' to '*a*' keep the old formatting.
+func newDeletedTokens(tokens []token, block ast.Node, source []byte) *Deleted {
+	x := NewDeleted(false, source)
+	addClass(x, ClassDeleted)
+	// originals are containers of the old block that copies are copied from.
+	var originals, copies []ast.Node
+	for _, t := range tokens {
+		containers := containersOf(t.node, block)
+		k := 0
+		for k < len(originals) && k < len(containers) && originals[k] == containers[k] {
+			k++
+		}
+		originals, copies = originals[:k], copies[:k]
+		for _, c := range containers[k:] {
+			var parent ast.Node = x
+			if len(copies) != 0 {
+				parent = copies[len(copies)-1]
+			}
+			cp := copyContainer(c)
+			parent.AppendChild(parent, cp)
+			originals, copies = append(originals, c), append(copies, cp)
+		}
+		var parent ast.Node = x
+		if len(copies) != 0 {
+			parent = copies[len(copies)-1]
+		}
+		switch {
+		case t.isBreak:
+			parent.AppendChild(parent, ast.NewString([]byte(" ")))
+		case t.isText:
+			s := ast.NewTextSegment(t.segment)
+			s.SetRaw(t.node.(*ast.Text).IsRaw())
+			parent.AppendChild(parent, s)
+		default:
+			parent.AppendChild(parent, t.node)
+		}
+	}
+	return x
+}
+
+// containersOf returns inline containers between the given node and the
+// given block that copyContainer can copy, outermost first.
+func containersOf(n, block ast.Node) []ast.Node {
+	var containers []ast.Node
+	for p := n.Parent(); p != nil && p != block; p = p.Parent() {
+		switch p.Kind() {
+		case ast.KindEmphasis, ast.KindLink, east.KindStrikethrough:
+			containers = append([]ast.Node{p}, containers...)
+		}
+	}
+	return containers
+}
+
+// copyContainer returns a copy of the given inline container without children.
+func copyContainer(n ast.Node) ast.Node {
+	var c ast.Node
+	switch v := n.(type) {
+	case *ast.Emphasis:
+		c = ast.NewEmphasis(v.Level)
+	case *ast.Link:
+		link := ast.NewLink()
+		link.Destination = v.Destination
+		link.Title = v.Title
+		c = link
+	default:
+		c = east.NewStrikethrough()
+	}
+	for _, attr := range n.Attributes() {
+		c.SetAttribute(attr.Name, attr.Value)
+	}
+	return c
+}
+
+// outer returns an Inserted node that wraps the given node, or the node
+// itself.
+func outer(n ast.Node) ast.Node {
+	if p, ok := n.Parent().(*Inserted); ok {
+		return p
+	}
+	return n
+}
+
+func keys(tokens []token) []string {
+	result := make([]string, len(tokens))
+	for i, t := range tokens {
+		result[i] = t.key
+	}
+	return result
+}
+
+// lcs returns pairs of indices of a longest common subsequence of a and b.
+func lcs(a, b []string) [][2]int {
+	var pairs [][2]int
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		pairs = append(pairs, [2]int{prefix, prefix})
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix &&
+		a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+	ma, mb := a[prefix:len(a)-suffix], b[prefix:len(b)-suffix]
+	if len(ma)*len(mb) <= maxLCSTable {
+		// table[i*w+j] is a length of a longest common subsequence of
+		// ma[i:] and mb[j:].
+		w := len(mb) + 1
+		table := make([]int32, (len(ma)+1)*w)
+		for i := len(ma) - 1; i >= 0; i-- {
+			for j := len(mb) - 1; j >= 0; j-- {
+				if ma[i] == mb[j] {
+					table[i*w+j] = table[(i+1)*w+j+1] + 1
+				} else {
+					table[i*w+j] = max(table[(i+1)*w+j], table[i*w+j+1])
+				}
+			}
+		}
+		for i, j := 0, 0; i < len(ma) && j < len(mb); {
+			switch {
+			case ma[i] == mb[j]:
+				pairs = append(pairs, [2]int{prefix + i, prefix + j})
+				i++
+				j++
+			case table[(i+1)*w+j] >= table[i*w+j+1]:
+				i++
+			default:
+				j++
+			}
+		}
+	} else {
+		positions := map[string][]int{}
+		for j, s := range mb {
+			positions[s] = append(positions[s], j)
+		}
+		j := 0
+		for i, s := range ma {
+			ps := positions[s]
+			for len(ps) != 0 && ps[0] < j {
+				ps = ps[1:]
+			}
+			positions[s] = ps
+			if len(ps) != 0 {
+				pairs = append(pairs, [2]int{prefix + i, prefix + ps[0]})
+				j = ps[0] + 1
+			}
+		}
+	}
+	for k := suffix; k > 0; k-- {
+		pairs = append(pairs, [2]int{len(a) - k, len(b) - k})
+	}
+	return pairs
+}
+
+// blocks returns children of the given block. Link reference definitions
+// are excluded because they are not rendered.
+func blocks(n ast.Node) []ast.Node {
+	var result []ast.Node
+	for c := n.FirstChild(); c != nil; c = c.NextSibling() {
+		if c.Kind() != ast.KindLinkReferenceDefinition {
+			result = append(result, c)
+		}
+	}
+	return result
+}
+
+func hasInlines(n ast.Node) bool {
+	return n.FirstChild() != nil && n.FirstChild().Type() == ast.TypeInline
+}
+
+// isItem returns true if the given block is marked with class attributes
+// instead of being wrapped.
+func isItem(n ast.Node) bool {
+	return n.Kind() == ast.KindListItem || isRow(n)
+}
+
+func isRow(n ast.Node) bool {
+	return n.Kind() == east.KindTableRow || n.Kind() == east.KindTableHeader
+}
+
+// wrapContents wraps contents of the given list item or table row with
+// wrappers returned by newWrapper. Inline contents of children are wrapped
+// with inline wrappers, and other children are wrapped with block wrappers.
+func wrapContents(n ast.Node, newWrapper func(c ast.Node, isBlock bool) ast.Node, class string) {
+	for c := n.FirstChild(); c != nil; {
+		next := c.NextSibling()
+		if hasInlines(c) {
+			wrapChildren(c, newWrapper(c, false), class)
+		} else if !isRow(n) {
+			w := newWrapper(c, true)
+			addClass(w, class)
+			n.InsertBefore(n, c, w)
+			w.AppendChild(w, c)
+		}
+		c = next
+	}
+}
+
+// wrapChildren moves children of the given node into the wrapper.
+func wrapChildren(n ast.Node, wrapper ast.Node, class string) {
+	if !n.HasChildren() {
+		return
+	}
+	addClass(wrapper, class)
+	for c := n.FirstChild(); c != nil; {
+		next := c.NextSibling()
+		wrapper.AppendChild(wrapper, c)
+		c = next
+	}
+	n.AppendChild(n, wrapper)
+}
+
+// sourceOf returns a source that children of the given node refer to.
+func sourceOf(n ast.Node, source []byte) []byte {
+	if o, ok := n.(ast.SourceOwner); ok && o.OwnSource() != nil {
+		return o.OwnSource()
+	}
+	return ast.SourceOf(n, source)
+}
+
+func addClass(n ast.Node, class string) {
+	if v, ok := n.AttributeString("class"); ok {
+		switch c := v.(type) {
+		case []byte:
+			n.SetAttributeString("class", []byte(string(c)+" "+class))
+			return
+		case string:
+			n.SetAttributeString("class", c+" "+class)
+			return
+		}
+	}
+	n.SetAttributeString("class", []byte(class))
+}
@@ -0,0 +1,109 @@
+package diff_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/diff"
+	"github.com/yuin/goldmark/extension"
+	"github.com/yuin/goldmark/text"
+)
+
+func render(t *testing.T, oldSource, newSource string) string {
+	t.Helper()
+	m := goldmark.New(goldmark.WithExtensions(extension.GFM, diff.Extension))
+	o, n := []byte(oldSource), []byte(newSource)
+	oldDoc := m.Parser().Parse(text.NewReader(o))
+	newDoc := m.Parser().Parse(text.NewReader(n))
+	var buf bytes.Buffer
+	if err := m.Renderer().Render(&buf, n, diff.Diff(oldDoc, o, newDoc, n)); err != nil {
+		t.Fatal(err)
+	}
+	return buf.String()
+}
+
+func TestDiff(t *testing.T) {
+	cases := []struct {
+		name     string
+		old      string
+		new      string
+		expected string
+	}{
+		{
+			name:     "unchanged",
+			old:      "# a\n\nb c\nd\n",
+			new:      "# a\n\nb c d\n",
+			expected: "<h1>a</h1>\n<p>b c d</p>\n",
+		},
+		{
+			name: "words",
+			old:  "The quick brown fox.\n",
+			new:  "The quick red fox *jumps*.\n",
+			expected: `<p class="diff-changed">The quick <del class="diff-deleted">brown</del>` +
+				`<ins class="diff-inserted">red</ins> fox<ins class="diff-inserted"> </ins>` +
+				`<em><ins class="diff-inserted">jumps</ins></em>.</p>` + "\n",
+		},
+		{
+			name: "deleted code spans",
+			old:  "a `b` c\n",
+			new:  "a c\n",
+			expected: `<p class="diff-changed">a <del class="diff-deleted"><code>b</code> </del>c</p>` +
+				"\n",
+		},
+		{
+			name: "formatting",
+			old:  "the words are **bold** or [link](/x) here\n",
+			new:  "the words are *bold* or link here\n",
+			expected: `<p class="diff-changed">the words are <em><del class="diff-deleted"><strong>bold</strong></del>` +
+				`<ins class="diff-inserted">bold</ins></em> or ` +
+				`<del class="diff-deleted"><a href="/x">link</a></del><ins class="diff-inserted">link</ins> here</p>` + "\n",
+		},
+		{
+			name: "blocks",
+			old:  "a\n\n```\ncode\n```\n",
+			new:  "a\n\n---\n\nb\n",
+			expected: "<p>a</p>\n" +
+				`<del class="diff-deleted">` + "\n<pre><code>code\n</code></pre>\n</del>\n" +
+				`<ins class="diff-inserted">` + "\n<hr>\n</ins>\n" +
+				`<ins class="diff-inserted">` + "\n<p>b</p>\n</ins>\n",
+		},
+		{
+			name: "moved",
+			old:  "a\n\nb\n\nc\n",
+			new:  "c\n\na\n\nb\n",
+			expected: `<ins class="diff-moved">` + "\n<p>c</p>\n</ins>\n" +
+				"<p>a</p>\n<p>b</p>\n",
+		},
+		{
+			name: "list items",
+			old:  "- a\n- b\n- c\n",
+			new:  "- a\n- c\n- d\n",
+			expected: `<ul class="diff-changed">` + "\n<li>a</li>\n" +
+				`<li class="diff-deleted"><del class="diff-deleted">b</del></li>` + "\n" +
+				"<li>c</li>\n" +
+				`<li class="diff-inserted"><ins class="diff-inserted">d</ins></li>` + "\n</ul>\n",
+		},
+		{
+			name: "table rows",
+			old:  "| a | b |\n|---|---|\n| c | d |\n| e | f |\n",
+			new:  "| a | b |\n|---|---|\n| c | g |\n| h | i |\n",
+			expected: `<table class="diff-changed">` + "\n<thead>\n<tr>\n<th>a</th>\n<th>b</th>\n</tr>\n</thead>\n" +
+				"<tbody>\n" + `<tr class="diff-changed">` + "\n<td>c</td>\n" +
+				`<td><del class="diff-deleted">d</del><ins class="diff-inserted">g</ins></td>` + "\n</tr>\n" +
+				`<tr class="diff-deleted">` + "\n" +
+				`<td><del class="diff-deleted">e</del></td>` + "\n" +
+				`<td><del class="diff-deleted">f</del></td>` + "\n</tr>\n" +
+				`<tr class="diff-inserted">` + "\n" +
+				`<td><ins class="diff-inserted">h</ins></td>` + "\n" +
+				`<td><ins class="diff-inserted">i</ins></td>` + "\n</tr>\n</tbody>\n</table>\n",
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if s := render(t, c.old, c.new); s != c.expected {
+				t.Errorf("unexpected output:\n%s\nexpected:\n%s", s, c.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,80 @@
+package diff
+
+import (
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/renderer"
+	"github.com/yuin/goldmark/renderer/html"
+	"github.com/yuin/goldmark/util"
+)
+
+// HTMLRenderer is a renderer.NodeRenderer implementation that renders
+// Inserted and Deleted nodes as ins and del elements.
+type HTMLRenderer struct {
+	html.Config
+}
+
+// NewHTMLRenderer returns a new HTMLRenderer.
+func NewHTMLRenderer(opts ...html.Option) renderer.NodeRenderer {
+	r := &HTMLRenderer{
+		Config: html.NewConfig(),
+	}
+	for _, opt := range opts {
+		opt.SetHTMLOption(&r.Config)
+	}
+	return r
+}
+
+// RegisterFuncs implements renderer.NodeRenderer.RegisterFuncs.
+func (r *HTMLRenderer) RegisterFuncs(reg renderer.NodeRendererFuncRegisterer) {
+	reg.Register(KindInserted, r.renderInserted)
+	reg.Register(KindDeleted, r.renderDeleted)
+}
+
+// EditAttributeFilter defines attribute names which ins and del elements can
+// have.
+var EditAttributeFilter = html.GlobalAttributeFilter.ExtendString(`cite,datetime`)
+
+func (r *HTMLRenderer) renderInserted(
+	w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	n := node.(*Inserted)
+	renderEdit(w, n, "ins", n.IsBlock, entering)
+	return ast.WalkContinue, nil
+}
+
+func (r *HTMLRenderer) renderDeleted(
+	w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	n := node.(*Deleted)
+	renderEdit(w, n, "del", n.IsBlock, entering)
+	return ast.WalkContinue, nil
+}
+
+func renderEdit(w util.BufWriter, n ast.Node, tag string, isBlock, entering bool) {
+	if entering {
+		_ = w.WriteByte('<')
+		_, _ = w.WriteString(tag)
+		if n.Attributes() != nil {
+			html.RenderAttributes(w, n, EditAttributeFilter)
+		}
+		_ = w.WriteByte('>')
+	} else {
+		_, _ = w.WriteString("</")
+		_, _ = w.WriteString(tag)
+		_ = w.WriteByte('>')
+	}
+	if isBlock {
+		_ = w.WriteByte('\n')
+	}
+}
+
+type extension struct {
+}
+
+// Extension is an extension that renders documents returned by Diff.
+var Extension = &extension{}
+
+func (e *extension) Extend(m goldmark.Markdown) {
+	m.Renderer().AddOptions(renderer.WithNodeRenderers(
+		util.Prioritized(NewHTMLRenderer(), 500),
+	))
+}
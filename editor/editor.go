@@ -0,0 +1,409 @@
+// Package editor implements functions to edit Markdown sources through
+// parsed ASTs while preserving untouched regions of the sources.
+package editor
+
+import (
+	"bytes"
+	"errors"
+	"sort"
+
+	"github.com/yuin/goldmark/ast"
+	east "github.com/yuin/goldmark/extension/ast"
+	"github.com/yuin/goldmark/util"
+)
+
+var (
+	// ErrUnknownPosition is returned when a position of a node in the source
+	// can not be determined.
+	ErrUnknownPosition = errors.New("editor: position of the node is unknown")
+
+	// ErrReferenceLink is returned when a destination of a reference link is
+	// edited. Destinations of reference links must be edited through their
+	// link reference definitions.
+	ErrReferenceLink = errors.New("editor: destinations of reference links are defined by link reference definitions")
+
+	// ErrInvalidLevel is returned when a heading level is out of range or
+	// can not be applied to the heading.
+	ErrInvalidLevel = errors.New("editor: invalid heading level")
+
+	// ErrNotTopLevel is returned when blocks are inserted after blocks that
+	// are not top level blocks.
+	ErrNotTopLevel = errors.New("editor: blocks can be inserted only after top level blocks")
+
+	// ErrOverlap is returned when recorded edits overlap.
+	ErrOverlap = errors.New("editor: edits overlap")
+
+	// ErrOwnSource is returned when a node refers to a source of an
+	// ast.SourceOwner ancestor, like a cell of a grid table, instead of the
+	// source of the document.
+	ErrOwnSource = errors.New("editor: the node refers to a source other than the source of the document")
+)
+
+// edit is a replacement of a range of a source.
+type edit struct {
+	start, stop int
+	text        []byte
+}
+
+// An Editor records edits against nodes of a document parsed from a source
+// and produces the patched source. Bytes of the source that are not edited
+// are preserved as they are.
+//
+// Positions of nodes refer to the original source, so all edits must be
+// recorded against the document parsed from the source before calling
+// Bytes.
+type Editor struct {
+	source []byte
+	edits  []edit
+}
+
+// New returns a new Editor for the given source. source must be the byte
+// slice that the document is parsed from, because destinations of nodes are
+// located by their addresses.
+func New(source []byte) *Editor {
+	return &Editor{
+		source: source,
+	}
+}
+
+// Replace replaces the range [start, stop) of the source with the given
+// text.
+func (e *Editor) Replace(start, stop int, text []byte) {
+	e.edits = append(e.edits, edit{start, stop, text})
+}
+
+// ReplaceText replaces the source of the given text node with the given
+// value. value is written as it is, so Markdown syntaxes in value are
+// interpreted when the source is parsed again.
+func (e *Editor) ReplaceText(n *ast.Text, value []byte) error {
+	if hasOwnSource(n) {
+		return ErrOwnSource
+	}
+	e.Replace(n.Segment.Start, n.Segment.Stop, value)
+	return nil
+}
+
+// SetChecked checks or unchecks the given task list checkbox.
+func (e *Editor) SetChecked(n *east.TaskCheckBox, checked bool) error {
+	if hasOwnSource(n) {
+		return ErrOwnSource
+	}
+	pos := n.Pos()
+	if pos < 0 || pos+2 >= len(e.source) || e.source[pos] != '[' {
+		return ErrUnknownPosition
+	}
+	mark := []byte{' '}
+	if checked {
+		mark[0] = 'x'
+	}
+	e.Replace(pos+1, pos+2, mark)
+	return nil
+}
+
+// SetDestination replaces a destination of the given *ast.Link, *ast.Image,
+// *ast.LinkReferenceDefinition or *ast.AutoLink.
+//
+// destination is written as it is. Destinations of links, images and link
+// reference definitions are enclosed in angle brackets if they contain
+// spaces or unbalanced parentheses.
+func (e *Editor) SetDestination(n ast.Node, destination []byte) error {
+	if hasOwnSource(n) {
+		return ErrOwnSource
+	}
+	var current []byte
+	switch v := n.(type) {
+	case *ast.Link:
+		if v.Reference != nil {
+			return ErrReferenceLink
+		}
+		current = v.Destination
+	case *ast.Image:
+		if v.Reference != nil {
+			return ErrReferenceLink
+		}
+		current = v.Destination
+	case *ast.LinkReferenceDefinition:
+		current = v.Destination
+	case *ast.AutoLink:
+		start, ok := offsetOf(e.source, v.Label(e.source))
+		if !ok || bytes.ContainsAny(destination, " \t\n<>") {
+			return ErrUnknownPosition
+		}
+		e.Replace(start, start+len(v.Label(e.source)), destination)
+		return nil
+	default:
+		return ErrUnknownPosition
+	}
+	start, ok := offsetOf(e.source, current)
+	if !ok && len(current) == 0 && n.Kind() != ast.KindLinkReferenceDefinition {
+		start, ok = e.emptyDestinationPos(n)
+	}
+	if !ok {
+		return ErrUnknownPosition
+	}
+	stop := start + len(current)
+	bracketed := start > 0 && e.source[start-1] == '<'
+	if !bracketed && needsAngleBrackets(destination) {
+		destination = append(append([]byte{'<'}, escapeAngleBrackets(destination)...), '>')
+	} else if bracketed {
+		destination = escapeAngleBrackets(destination)
+	}
+	e.Replace(start, stop, destination)
+	return nil
+}
+
+// SetHeadingLevel changes a level of the given heading.
+// Setext headings that have a single line are converted into ATX headings
+// if level is greater than 2.
+func (e *Editor) SetHeadingLevel(n *ast.Heading, level int) error {
+	if level < 1 || level > 6 {
+		return ErrInvalidLevel
+	}
+	if hasOwnSource(n) {
+		return ErrOwnSource
+	}
+	pos := n.Pos()
+	if pos < 0 || pos >= len(e.source) {
+		return ErrUnknownPosition
+	}
+	if stop := atxMarkerStop(e.source, pos); stop > 0 {
+		e.Replace(pos, stop, bytes.Repeat([]byte{'#'}, level))
+		return nil
+	}
+
+	// setext headings
+	lines := n.Lines()
+	if lines.Len() == 0 {
+		return ErrUnknownPosition
+	}
+	last := lines.At(lines.Len() - 1)
+	i := bytes.IndexByte(e.source[last.Start:], '\n')
+	if i < 0 {
+		return ErrUnknownPosition
+	}
+	start := last.Start + i + 1
+	for start < len(e.source) && e.source[start] != '=' && e.source[start] != '-' && e.source[start] != '\n' {
+		start++
+	}
+	if start == len(e.source) || e.source[start] == '\n' {
+		return ErrUnknownPosition
+	}
+	stop := start
+	for stop < len(e.source) && e.source[stop] == e.source[start] {
+		stop++
+	}
+	if level <= 2 {
+		c := byte('=')
+		if level == 2 {
+			c = '-'
+		}
+		e.Replace(start, stop, bytes.Repeat([]byte{c}, stop-start))
+		return nil
+	}
+	if lines.Len() != 1 {
+		return ErrInvalidLevel
+	}
+	first := lines.At(0)
+	heading := bytes.Repeat([]byte{'#'}, level)
+	heading = append(heading, ' ')
+	heading = append(heading, util.TrimRightSpace(first.Value(e.source))...)
+	for stop < len(e.source) && e.source[stop] != '\n' {
+		stop++
+	}
+	e.Replace(first.Start, stop, heading)
+	return nil
+}
+
+// atxMarkerStop returns the stop position of the '#' characters at pos if
+// the line is an ATX heading, otherwise -1.
+// Setext headings can start with '#' characters like "#foo" that are not
+// followed by spaces, and such lines are not ATX headings.
+func atxMarkerStop(source []byte, pos int) int {
+	stop := pos
+	for stop < len(source) && source[stop] == '#' {
+		stop++
+	}
+	if stop == pos || stop-pos > 6 {
+		return -1
+	}
+	if stop < len(source) && !util.IsSpace(source[stop]) {
+		return -1
+	}
+	return stop
+}
+
+// InsertBlockAfter inserts the given Markdown blocks after the given top
+// level block. Blank lines are inserted around the blocks as needed.
+func (e *Editor) InsertBlockAfter(n ast.Node, markdown []byte) error {
+	if n.Parent() == nil || n.Parent().Kind() != ast.KindDocument {
+		return ErrNotTopLevel
+	}
+	end := len(e.source)
+	if next := n.NextSibling(); next != nil {
+		pos := blockPos(next)
+		if pos < 0 || pos > len(e.source) {
+			return ErrUnknownPosition
+		}
+		end = bytes.LastIndexByte(e.source[:pos], '\n') + 1
+	}
+	// blocks are inserted after the last non-blank line.
+	for end > 0 {
+		start := bytes.LastIndexByte(e.source[:end-1], '\n') + 1
+		if !util.IsBlank(e.source[start:end]) {
+			break
+		}
+		end = start
+	}
+	var text []byte
+	if end > 0 && e.source[end-1] != '\n' {
+		text = append(text, '\n')
+	}
+	text = append(text, '\n')
+	text = append(text, markdown...)
+	if len(markdown) != 0 && markdown[len(markdown)-1] != '\n' {
+		text = append(text, '\n')
+	}
+	if end < len(e.source) && e.source[end] != '\n' && e.source[end] != '\r' {
+		text = append(text, '\n')
+	}
+	e.Replace(end, end, text)
+	return nil
+}
+
+// Bytes returns the patched source. Edits are applied in the order of their
+// positions, and insertions at the same position are applied in the
+// recorded order.
+func (e *Editor) Bytes() ([]byte, error) {
+	edits := make([]edit, len(e.edits))
+	copy(edits, e.edits)
+	sort.SliceStable(edits, func(i, j int) bool {
+		if edits[i].start == edits[j].start {
+			// insertions precede replacements at the same position.
+			return edits[i].stop == edits[i].start && edits[j].stop != edits[j].start
+		}
+		return edits[i].start < edits[j].start
+	})
+	var result []byte
+	pos := 0
+	for _, ed := range edits {
+		if ed.start < pos || ed.stop < ed.start || ed.stop > len(e.source) {
+			return nil, ErrOverlap
+		}
+		result = append(result, e.source[pos:ed.start]...)
+		result = append(result, ed.text...)
+		pos = ed.stop
+	}
+	result = append(result, e.source[pos:]...)
+	return result, nil
+}
+
+// offsetOf returns an offset of b in the source if b is a slice of the
+// source.
+func offsetOf(source, b []byte) (int, bool) {
+	if len(b) == 0 {
+		return 0, false
+	}
+	offset := cap(source) - cap(b)
+	if offset < 0 || offset+len(b) > len(source) || &source[offset] != &b[0] {
+		return 0, false
+	}
+	return offset, true
+}
+
+// emptyDestinationPos returns a position of the empty destination of the
+// given link or image like '[a]()' and '[a](<>)'. Empty destinations do not
+// refer to the source, so the position is found from '](' after the texts
+// of the link.
+func (e *Editor) emptyDestinationPos(n ast.Node) (int, bool) {
+	pos := n.Pos()
+	if pos < 0 || pos >= len(e.source) {
+		return 0, false
+	}
+	_ = ast.Walk(n, func(c ast.Node, entering bool) (ast.WalkStatus, error) {
+		switch v := c.(type) {
+		case *ast.Text:
+			pos = max(pos, v.Segment.Stop)
+		case *ast.RawHTML:
+			for i := range v.Segments.Len() {
+				pos = max(pos, v.Segments.At(i).Stop)
+			}
+		}
+		return ast.WalkContinue, nil
+	})
+	i := bytes.Index(e.source[pos:], []byte("]("))
+	if i < 0 {
+		return 0, false
+	}
+	start := pos + i + 2
+	j := start
+	for j < len(e.source) && util.IsSpace(e.source[j]) {
+		j++
+	}
+	if bytes.HasPrefix(e.source[j:], []byte("<>")) {
+		return j + 1, true
+	}
+	return start, true
+}
+
+// hasOwnSource returns true if the given node refers to a source of an
+// ast.SourceOwner ancestor.
+func hasOwnSource(n ast.Node) bool {
+	for p := n.Parent(); p != nil; p = p.Parent() {
+		if o, ok := p.(ast.SourceOwner); ok && o.OwnSource() != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// blockPos returns a position of the given block. If the block does not
+// have a position, blockPos returns a position of the first descendant.
+func blockPos(n ast.Node) int {
+	pos := n.Pos()
+	if pos > -1 {
+		return pos
+	}
+	_ = ast.Walk(n, func(c ast.Node, entering bool) (ast.WalkStatus, error) {
+		if entering && c.Pos() > -1 {
+			pos = c.Pos()
+			return ast.WalkStop, nil
+		}
+		return ast.WalkContinue, nil
+	})
+	return pos
+}
+
+func needsAngleBrackets(destination []byte) bool {
+	if len(destination) == 0 || destination[0] == '<' {
+		return true
+	}
+	opened := 0
+	for _, c := range destination {
+		switch {
+		case util.IsSpace(c) || c < 0x20:
+			return true
+		case c == '(':
+			opened++
+		case c == ')':
+			opened--
+			if opened < 0 {
+				return true
+			}
+		}
+	}
+	return opened != 0
+}
+
+func escapeAngleBrackets(destination []byte) []byte {
+	if !bytes.ContainsAny(destination, "<>") {
+		return destination
+	}
+	var result []byte
+	for _, c := range destination {
+		if c == '<' || c == '>' {
+			result = append(result, '\\')
+		}
+		result = append(result, c)
+	}
+	return result
+}
@@ -0,0 +1,179 @@
+package editor_test
+
+import (
+	"testing"
+
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/editor"
+	"github.com/yuin/goldmark/extension"
+	east "github.com/yuin/goldmark/extension/ast"
+	"github.com/yuin/goldmark/testutil"
+	"github.com/yuin/goldmark/text"
+)
+
+const source = `Title
+=====
+
+- [ ] first  task
+- [x] second task
+
+See [docs](/old "title"), ![image](</a b.png>) and <https://example.com>.
+
+Setext
+------
+
+## Section ##
+
+[ref]: /ref
+`
+
+func find[T ast.Node](doc ast.Node, i int) T {
+	var result T
+	_ = ast.Walk(doc, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		if v, ok := n.(T); ok && entering {
+			if i == 0 {
+				result = v
+				return ast.WalkStop, nil
+			}
+			i--
+		}
+		return ast.WalkContinue, nil
+	})
+	return result
+}
+
+func TestEditor(t *testing.T) {
+	markdown := goldmark.New(goldmark.WithExtensions(extension.TaskList))
+	source := []byte(source)
+	doc := markdown.Parser().Parse(text.NewReader(source))
+	e := editor.New(source)
+	for i, err := range []error{
+		e.SetChecked(find[*east.TaskCheckBox](doc, 0), true),
+		e.SetChecked(find[*east.TaskCheckBox](doc, 1), false),
+		e.SetDestination(find[*ast.Link](doc, 0), []byte("/new(1)")),
+		e.SetDestination(find[*ast.Image](doc, 0), []byte("<c>.png")),
+		e.SetDestination(find[*ast.AutoLink](doc, 0), []byte("https://example.org")),
+		e.SetDestination(find[*ast.LinkReferenceDefinition](doc, 0), []byte("/a (b")),
+		e.SetHeadingLevel(find[*ast.Heading](doc, 0), 2),
+		e.SetHeadingLevel(find[*ast.Heading](doc, 1), 3),
+		e.SetHeadingLevel(find[*ast.Heading](doc, 2), 1),
+		e.InsertBlockAfter(find[*ast.List](doc, 0), []byte("> inserted")),
+		e.InsertBlockAfter(find[*ast.LinkReferenceDefinition](doc, 0), []byte("last\n")),
+	} {
+		if err != nil {
+			t.Fatalf("edit %d: %v", i, err)
+		}
+	}
+	if err := e.ReplaceText(find[*ast.Text](doc, 1), []byte("*first*  task")); err != nil {
+		t.Fatal(err)
+	}
+	actual, err := e.Bytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `Title
+-----
+
+- [x] *first*  task
+- [ ] second task
+
+> inserted
+
+See [docs](/new(1) "title"), ![image](<\<c\>.png>) and <https://example.org>.
+
+### Setext
+
+# Section ##
+
+[ref]: </a (b>
+
+last
+`
+	if string(actual) != expected {
+		t.Errorf("unexpected result:\n%s", testutil.DiffPretty([]byte(expected), actual))
+	}
+}
+
+func TestEditorEmptyDestinations(t *testing.T) {
+	source := []byte("[a]() [*b*](<>) ![c](<> \"t\") [](  )\n")
+	doc := goldmark.New().Parser().Parse(text.NewReader(source))
+	e := editor.New(source)
+	for i, destination := range []string{"/a", "/b c", "/c", "/d"} {
+		var n ast.Node = find[*ast.Link](doc, i)
+		if i == 2 {
+			n = find[*ast.Image](doc, 0)
+		} else if i == 3 {
+			n = find[*ast.Link](doc, 2)
+		}
+		if err := e.SetDestination(n, []byte(destination)); err != nil {
+			t.Fatalf("edit %d: %v", i, err)
+		}
+	}
+	actual, err := e.Bytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := "[a](/a) [*b*](</b c>) ![c](</c> \"t\") [](/d  )\n"
+	if string(actual) != expected {
+		t.Errorf("expected %q, but got %q", expected, actual)
+	}
+}
+
+func TestEditorErrors(t *testing.T) {
+	source := []byte("[a][ref] and [b]()\n\n- para\n\n  # h\n\n[ref]: /url\n")
+	doc := goldmark.New().Parser().Parse(text.NewReader(source))
+	e := editor.New(source)
+	if err := e.SetDestination(find[*ast.Link](doc, 0), []byte("/x")); err != editor.ErrReferenceLink {
+		t.Errorf("reference links should not be edited: %v", err)
+	}
+	if err := e.SetHeadingLevel(find[*ast.Heading](doc, 0), 7); err != editor.ErrInvalidLevel {
+		t.Errorf("level should be validated: %v", err)
+	}
+	if err := e.InsertBlockAfter(find[*ast.Heading](doc, 0), []byte("x")); err != editor.ErrNotTopLevel {
+		t.Errorf("blocks should be inserted after top level blocks: %v", err)
+	}
+	e.Replace(0, 3, nil)
+	e.Replace(2, 4, nil)
+	if _, err := e.Bytes(); err != editor.ErrOverlap {
+		t.Errorf("overlapping edits should be reported: %v", err)
+	}
+}
+
+func TestSetHeadingLevel(t *testing.T) {
+	source := []byte("#foo\n===\n\n# bar\n===\n\n#\tbaz\n")
+	doc := goldmark.New().Parser().Parse(text.NewReader(source))
+	e := editor.New(source)
+	for i, level := range []int{2, 3, 4} {
+		if err := e.SetHeadingLevel(find[*ast.Heading](doc, i), level); err != nil {
+			t.Fatalf("heading %d: %v", i, err)
+		}
+	}
+	actual, err := e.Bytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := "#foo\n---\n\n### bar\n===\n\n####\tbaz\n"
+	if string(actual) != expected {
+		t.Errorf("unexpected result:\n%s", testutil.DiffPretty([]byte(expected), actual))
+	}
+}
+
+func TestEditorOwnSource(t *testing.T) {
+	source := []byte("+-----+------------+\n| a   | b          |\n+=====+============+\n| # h | [l](/url)  |\n+-----+------------+\n")
+	markdown := goldmark.New(goldmark.WithExtensions(extension.NewTable(extension.WithTableGridTables())))
+	doc := markdown.Parser().Parse(text.NewReader(source))
+	e := editor.New(source)
+	if err := e.ReplaceText(find[*ast.Text](doc, 0), []byte("x")); err != editor.ErrOwnSource {
+		t.Errorf("texts in grid table cells should not be edited: %v", err)
+	}
+	if err := e.SetHeadingLevel(find[*ast.Heading](doc, 0), 2); err != editor.ErrOwnSource {
+		t.Errorf("headings in grid table cells should not be edited: %v", err)
+	}
+	if err := e.SetDestination(find[*ast.Link](doc, 0), []byte("/x")); err != editor.ErrOwnSource {
+		t.Errorf("links in grid table cells should not be edited: %v", err)
+	}
+	if err := e.InsertBlockAfter(find[*east.Table](doc, 0), []byte("after")); err != nil {
+		t.Errorf("blocks should be inserted after grid tables: %v", err)
+	}
+}
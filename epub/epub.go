@@ -0,0 +1,391 @@
+// Package epub implements functions to build EPUB 3 publications from
+// Markdown documents.
+package epub
+
+import (
+	"archive/zip"
+	"bytes"
+	"crypto/sha1"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/url"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/renderer/html"
+	"github.com/yuin/goldmark/text"
+	"github.com/yuin/goldmark/util"
+)
+
+// Metadata is metadata of a publication.
+type Metadata struct {
+	// Identifier is a unique identifier of the publication like
+	// 'urn:isbn:...'. If Identifier is empty, an identifier is generated
+	// from the sources.
+	Identifier string
+
+	// Title is a title of the publication. If Title is empty, a text of
+	// the first heading is used.
+	Title string
+
+	// Language is a language of the publication. Defaults to 'en'.
+	Language string
+
+	// Creators are authors of the publication.
+	Creators []string
+
+	Publisher   string
+	Date        string
+	Description string
+
+	// Modified is the last modification time of the publication.
+	// Defaults to the current time.
+	Modified time.Time
+}
+
+// A Config struct has configurations for building publications.
+type Config struct {
+	// Markdown is used for parsing and rendering the sources.
+	// The renderer must render documents as XHTML.
+	Markdown goldmark.Markdown
+
+	// FS is a filesystem that images referenced by the sources are read from.
+	// If FS is nil, images are not packaged.
+	FS fs.FS
+
+	// Metadata is default metadata of the publication.
+	// Metadata in front matters of the sources override this.
+	Metadata Metadata
+}
+
+// NewConfig returns a new Config with defaults.
+func NewConfig() Config {
+	return Config{
+		Markdown: goldmark.New(
+			goldmark.WithParserOptions(parser.WithAutoHeadingID()),
+			goldmark.WithRendererOptions(html.WithXHTML()),
+		),
+		Metadata: Metadata{
+			Language: "en",
+		},
+	}
+}
+
+// Option is a functional option type for building publications.
+type Option func(*Config)
+
+// WithMarkdown is an option that specifies a Markdown object that parses
+// and renders the sources. Renderers of the Markdown object must be
+// configured with html.WithXHTML.
+func WithMarkdown(m goldmark.Markdown) Option {
+	return func(c *Config) {
+		c.Markdown = m
+	}
+}
+
+// WithFS is an option that specifies a filesystem that images are read from.
+// Image destinations in the sources are treated as paths in the filesystem.
+func WithFS(fsys fs.FS) Option {
+	return func(c *Config) {
+		c.FS = fsys
+	}
+}
+
+// WithMetadata is an option that specifies default metadata of the publication.
+func WithMetadata(metadata Metadata) Option {
+	return func(c *Config) {
+		language := c.Metadata.Language
+		c.Metadata = metadata
+		if len(c.Metadata.Language) == 0 {
+			c.Metadata.Language = language
+		}
+	}
+}
+
+// A chapter struct is an XHTML file of the publication.
+type chapter struct {
+	name     string
+	title    string
+	document *ast.Document
+	source   []byte
+	headings []*ast.Heading
+}
+
+// An image struct is an image file of the publication.
+type image struct {
+	name      string
+	mediaType string
+	data      []byte
+}
+
+// A book struct holds contents of the publication during building.
+type book struct {
+	config   Config
+	metadata Metadata
+	chapters []*chapter
+	images   []*image
+	ids      int
+}
+
+// Write builds an EPUB 3 publication from the given Markdown sources and
+// writes it to the given writer.
+//
+// Each source is split into chapters at level 1 headings.
+func Write(w io.Writer, sources [][]byte, opts ...Option) error {
+	config := NewConfig()
+	for _, opt := range opts {
+		opt(&config)
+	}
+	b := &book{config: config, metadata: config.Metadata}
+	var meta []map[string]any
+	for _, source := range sources {
+		doc := config.Markdown.Parser().Parse(text.NewReader(source)).(*ast.Document)
+		meta = append(meta, doc.Meta())
+		b.split(doc, source)
+	}
+	b.applyMeta(meta)
+	if len(b.metadata.Identifier) == 0 {
+		hash := sha1.New()
+		for _, source := range sources {
+			_, _ = hash.Write(source)
+		}
+		b.metadata.Identifier = uuid(hash.Sum(nil))
+	}
+	if len(b.metadata.Title) == 0 {
+		b.metadata.Title = "Untitled"
+		for _, c := range b.chapters {
+			if len(c.headings) != 0 {
+				b.metadata.Title = c.title
+				break
+			}
+		}
+	}
+	if b.metadata.Modified.IsZero() {
+		b.metadata.Modified = time.Now()
+	}
+	for _, c := range b.chapters {
+		if err := b.collectImages(c); err != nil {
+			return err
+		}
+	}
+	return b.write(w)
+}
+
+// uuid returns a name based UUID from the given hash.
+func uuid(hash []byte) string {
+	u := hash[:16]
+	u[6] = (u[6] & 0x0f) | 0x50
+	u[8] = (u[8] & 0x3f) | 0x80
+	return fmt.Sprintf("urn:uuid:%x-%x-%x-%x-%x", u[0:4], u[4:6], u[6:8], u[8:10], u[10:16])
+}
+
+// applyMeta applies metadata of front matters. Metadata of earlier
+// sources take precedence.
+func (b *book) applyMeta(meta []map[string]any) {
+	for i := len(meta) - 1; i >= 0; i-- {
+		for key, value := range meta[i] {
+			s := fmt.Sprint(value)
+			switch key {
+			case "identifier", "id", "isbn":
+				b.metadata.Identifier = s
+			case "title":
+				b.metadata.Title = s
+			case "language", "lang":
+				b.metadata.Language = s
+			case "author", "authors", "creator":
+				b.metadata.Creators = nil
+				if values, ok := value.([]any); ok {
+					for _, v := range values {
+						b.metadata.Creators = append(b.metadata.Creators, fmt.Sprint(v))
+					}
+				} else {
+					b.metadata.Creators = []string{s}
+				}
+			case "publisher":
+				b.metadata.Publisher = s
+			case "date":
+				b.metadata.Date = s
+			case "description":
+				b.metadata.Description = s
+			}
+		}
+	}
+}
+
+// split splits the given document into chapters at level 1 headings.
+func (b *book) split(doc *ast.Document, source []byte) {
+	first := len(b.chapters)
+	var current *chapter
+	for c := doc.FirstChild(); c != nil; {
+		next := c.NextSibling()
+		if heading, ok := c.(*ast.Heading); ok && heading.Level == 1 || current == nil {
+			current = &chapter{
+				name:     fmt.Sprintf("chapter-%03d.xhtml", len(b.chapters)+1),
+				document: ast.NewDocument(),
+				source:   source,
+			}
+			b.chapters = append(b.chapters, current)
+		}
+		current.document.AppendChild(current.document, c)
+		c = next
+	}
+	for _, c := range b.chapters[first:] {
+		b.collectHeadings(c)
+	}
+}
+
+// collectHeadings collects headings of the chapter. Headings that do not
+// have ids are given ids.
+func (b *book) collectHeadings(c *chapter) {
+	_ = ast.Walk(c.document, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		heading, ok := n.(*ast.Heading)
+		if !entering || !ok {
+			return ast.WalkContinue, nil
+		}
+		if _, ok := heading.AttributeString("id"); !ok {
+			b.ids++
+			heading.SetAttributeString("id", []byte(fmt.Sprintf("section-%d", b.ids)))
+		}
+		c.headings = append(c.headings, heading)
+		return ast.WalkSkipChildren, nil
+	})
+	if len(c.headings) != 0 {
+		c.title = string(plainText(c.headings[0], c.source))
+	}
+}
+
+// isLocal returns true if the given destination refers to a local file.
+func isLocal(destination []byte) bool {
+	u, err := url.Parse(string(destination))
+	return err == nil && u.Scheme == "" && u.Host == "" && len(u.Path) != 0 && !path.IsAbs(u.Path)
+}
+
+// mediaTypes are media types of the core media types images.
+var mediaTypes = map[string]string{
+	".gif":  "image/gif",
+	".jpg":  "image/jpeg",
+	".jpeg": "image/jpeg",
+	".png":  "image/png",
+	".svg":  "image/svg+xml",
+	".webp": "image/webp",
+}
+
+// collectImages reads images of the chapter from the filesystem and
+// rewrites their destinations.
+func (b *book) collectImages(c *chapter) error {
+	if b.config.FS == nil {
+		return nil
+	}
+	return ast.Walk(c.document, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		img, ok := n.(*ast.Image)
+		if !entering || !ok || !isLocal(img.Destination) {
+			return ast.WalkContinue, nil
+		}
+		u, _ := url.Parse(string(img.Destination))
+		name := path.Clean(u.Path)
+		mediaType, ok := mediaTypes[strings.ToLower(path.Ext(name))]
+		if !ok {
+			return ast.WalkStop, fmt.Errorf("epub: unsupported image type: %s", name)
+		}
+		href := "images/" + name
+		found := false
+		for _, i := range b.images {
+			found = found || i.name == href
+		}
+		if !found {
+			data, err := fs.ReadFile(b.config.FS, name)
+			if err != nil {
+				return ast.WalkStop, fmt.Errorf("epub: %w", err)
+			}
+			b.images = append(b.images, &image{name: href, mediaType: mediaType, data: data})
+		}
+		img.Destination = []byte(href)
+		return ast.WalkContinue, nil
+	})
+}
+
+// plainText returns a text of the given node without any markups.
+func plainText(n ast.Node, source []byte) []byte {
+	var buf bytes.Buffer
+	_ = ast.Walk(n, func(c ast.Node, entering bool) (ast.WalkStatus, error) {
+		if !entering {
+			return ast.WalkContinue, nil
+		}
+		switch typed := c.(type) {
+		case *ast.Text:
+			segment := typed.Segment
+			value := segment.Value(source)
+			if !typed.IsRaw() {
+				value = util.ResolveEntityNames(util.ResolveNumericReferences(util.UnescapePunctuations(value)))
+			}
+			_, _ = buf.Write(value)
+			if typed.SoftLineBreak() || typed.HardLineBreak() {
+				_ = buf.WriteByte(' ')
+			}
+		case *ast.String:
+			_, _ = buf.Write(typed.Value)
+		case *ast.AutoLink:
+			_, _ = buf.Write(typed.Label(source))
+		case *ast.RawHTML:
+			return ast.WalkSkipChildren, nil
+		}
+		return ast.WalkContinue, nil
+	})
+	return bytes.TrimSpace(buf.Bytes())
+}
+
+// write writes the publication as a zip file.
+func (b *book) write(w io.Writer) error {
+	zw := zip.NewWriter(w)
+	modified := b.metadata.Modified.UTC()
+	create := func(name string, method uint16) (io.Writer, error) {
+		return zw.CreateHeader(&zip.FileHeader{Name: name, Method: method, Modified: modified})
+	}
+	// the mimetype file must be the first file and must not be compressed.
+	f, err := create("mimetype", zip.Store)
+	if err != nil {
+		return err
+	}
+	_, _ = io.WriteString(f, "application/epub+zip")
+	files := []struct {
+		name  string
+		write func(io.Writer) error
+	}{
+		{"META-INF/container.xml", writeContainer},
+		{"OEBPS/content.opf", b.writePackage},
+		{"OEBPS/nav.xhtml", b.writeNav},
+	}
+	for _, file := range files {
+		f, err := create(file.name, zip.Deflate)
+		if err != nil {
+			return err
+		}
+		if err := file.write(f); err != nil {
+			return err
+		}
+	}
+	for _, c := range b.chapters {
+		f, err := create("OEBPS/"+c.name, zip.Deflate)
+		if err != nil {
+			return err
+		}
+		if err := b.writeChapter(f, c); err != nil {
+			return err
+		}
+	}
+	for _, i := range b.images {
+		f, err := create("OEBPS/"+i.name, zip.Deflate)
+		if err != nil {
+			return err
+		}
+		if _, err := f.Write(i.data); err != nil {
+			return err
+		}
+	}
+	return zw.Close()
+}
@@ -0,0 +1,141 @@
+package epub_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/epub"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/renderer/html"
+	"github.com/yuin/goldmark/text"
+	"github.com/yuin/goldmark/util"
+)
+
+func readFiles(t *testing.T, data []byte) ([]*zip.File, map[string]string) {
+	t.Helper()
+	r, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	files := map[string]string{}
+	for _, f := range r.File {
+		rc, err := f.Open()
+		if err != nil {
+			t.Fatal(err)
+		}
+		b, _ := io.ReadAll(rc)
+		_ = rc.Close()
+		files[f.Name] = string(b)
+	}
+	return r.File, files
+}
+
+func TestWrite(t *testing.T) {
+	fsys := fstest.MapFS{"img/a.png": {Data: []byte("PNG")}}
+	var buf bytes.Buffer
+	err := epub.Write(&buf, [][]byte{
+		[]byte("Preface.\n\n# One\n\nHello ![a](img/a.png)\n\n## One.1\n\n### Deep\n\n## One.2\n"),
+		[]byte("# Two & *more*\n\ntext\n"),
+	}, epub.WithFS(fsys), epub.WithMetadata(epub.Metadata{
+		Title:    "Handbook",
+		Modified: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	entries, files := readFiles(t, buf.Bytes())
+	if entries[0].Name != "mimetype" || entries[0].Method != zip.Store ||
+		files["mimetype"] != "application/epub+zip" {
+		t.Errorf("mimetype must be the first uncompressed file: %s", entries[0].Name)
+	}
+	for _, name := range []string{
+		"META-INF/container.xml", "OEBPS/content.opf", "OEBPS/nav.xhtml",
+		"OEBPS/chapter-001.xhtml", "OEBPS/chapter-002.xhtml", "OEBPS/chapter-003.xhtml",
+		"OEBPS/images/img/a.png",
+	} {
+		if _, ok := files[name]; !ok {
+			t.Errorf("%s not found", name)
+		}
+	}
+	for name, expected := range map[string][]string{
+		"OEBPS/content.opf": {
+			"<dc:title>Handbook</dc:title>",
+			`<meta property="dcterms:modified">2024-01-02T03:04:05Z</meta>`,
+			`<item id="image-1" href="images/img/a.png" media-type="image/png"/>`,
+			"<itemref idref=\"chapter-1\"/>\n<itemref idref=\"chapter-2\"/>\n<itemref idref=\"chapter-3\"/>",
+		},
+		"OEBPS/nav.xhtml": {
+			`<li><a href="chapter-002.xhtml#one">One</a>
+<ol>
+<li><a href="chapter-002.xhtml#one1">One.1</a>
+<ol>
+<li><a href="chapter-002.xhtml#deep">Deep</a>
+</li>
+</ol>
+</li>
+<li><a href="chapter-002.xhtml#one2">One.2</a>
+</li>
+</ol>
+</li>
+<li><a href="chapter-003.xhtml#two--more">Two &amp; more</a>
+</li>
+</ol>`,
+		},
+		"OEBPS/chapter-001.xhtml": {"<title>Handbook</title>", "<p>Preface.</p>"},
+		"OEBPS/chapter-002.xhtml": {"<title>One</title>", `<img src="images/img/a.png" alt="a" />`},
+	} {
+		for _, e := range expected {
+			if !strings.Contains(files[name], e) {
+				t.Errorf("%s does not contain %q:\n%s", name, e, files[name])
+			}
+		}
+	}
+}
+
+type metaTransformer struct{}
+
+func (metaTransformer) Transform(node *ast.Document, reader text.Reader, pc parser.Context) {
+	node.SetMeta(map[string]any{"title": "Front Matter", "author": []any{"A", "B"}})
+}
+
+func TestWriteMeta(t *testing.T) {
+	markdown := goldmark.New(
+		goldmark.WithParserOptions(
+			parser.WithAutoHeadingID(),
+			parser.WithASTTransformers(util.Prioritized(metaTransformer{}, 100)),
+		),
+		goldmark.WithRendererOptions(html.WithXHTML()),
+	)
+	var buf bytes.Buffer
+	err := epub.Write(&buf, [][]byte{[]byte("# One\n")},
+		epub.WithMarkdown(markdown),
+		epub.WithMetadata(epub.Metadata{Title: "Default"}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, files := readFiles(t, buf.Bytes())
+	for _, e := range []string{
+		"<dc:title>Front Matter</dc:title>",
+		"<dc:creator>A</dc:creator>\n<dc:creator>B</dc:creator>",
+		"<dc:identifier id=\"pub-id\">urn:uuid:",
+	} {
+		if !strings.Contains(files["OEBPS/content.opf"], e) {
+			t.Errorf("content.opf does not contain %q:\n%s", e, files["OEBPS/content.opf"])
+		}
+	}
+}
+
+func TestWriteMissingImage(t *testing.T) {
+	var buf bytes.Buffer
+	err := epub.Write(&buf, [][]byte{[]byte("![a](missing.png)\n")}, epub.WithFS(fstest.MapFS{}))
+	if err == nil {
+		t.Error("an error is expected")
+	}
+}
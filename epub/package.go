@@ -0,0 +1,134 @@
+package epub
+
+import (
+	"bufio"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// escape returns the given text escaped for XML.
+func escape(s string) string {
+	var b strings.Builder
+	_ = xml.EscapeText(&b, []byte(s))
+	return b.String()
+}
+
+func writeContainer(w io.Writer) error {
+	_, err := io.WriteString(w, `<?xml version="1.0" encoding="UTF-8"?>
+<container version="1.0" xmlns="urn:oasis:names:tc:opendocument:xmlns:container">
+<rootfiles>
+<rootfile full-path="OEBPS/content.opf" media-type="application/oebps-package+xml"/>
+</rootfiles>
+</container>
+`)
+	return err
+}
+
+// writePackage writes the package document that has metadata, a manifest
+// and a spine of the publication.
+func (b *book) writePackage(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	m := b.metadata
+	_, _ = fmt.Fprintf(bw, `<?xml version="1.0" encoding="UTF-8"?>
+<package xmlns="http://www.idpf.org/2007/opf" version="3.0" unique-identifier="pub-id" xml:lang="%s">
+<metadata xmlns:dc="http://purl.org/dc/elements/1.1/">
+`, escape(m.Language))
+	_, _ = fmt.Fprintf(bw, "<dc:identifier id=\"pub-id\">%s</dc:identifier>\n", escape(m.Identifier))
+	_, _ = fmt.Fprintf(bw, "<dc:title>%s</dc:title>\n", escape(m.Title))
+	_, _ = fmt.Fprintf(bw, "<dc:language>%s</dc:language>\n", escape(m.Language))
+	for _, creator := range m.Creators {
+		_, _ = fmt.Fprintf(bw, "<dc:creator>%s</dc:creator>\n", escape(creator))
+	}
+	for _, element := range []struct{ name, value string }{
+		{"publisher", m.Publisher},
+		{"date", m.Date},
+		{"description", m.Description},
+	} {
+		if len(element.value) != 0 {
+			_, _ = fmt.Fprintf(bw, "<dc:%s>%s</dc:%s>\n", element.name, escape(element.value), element.name)
+		}
+	}
+	_, _ = fmt.Fprintf(bw, "<meta property=\"dcterms:modified\">%s</meta>\n",
+		m.Modified.UTC().Format("2006-01-02T15:04:05Z"))
+	_, _ = bw.WriteString("</metadata>\n<manifest>\n")
+	_, _ = bw.WriteString(`<item id="nav" href="nav.xhtml" media-type="application/xhtml+xml" properties="nav"/>` + "\n")
+	for i, c := range b.chapters {
+		_, _ = fmt.Fprintf(bw, "<item id=\"chapter-%d\" href=\"%s\" media-type=\"application/xhtml+xml\"/>\n",
+			i+1, c.name)
+	}
+	for i, image := range b.images {
+		_, _ = fmt.Fprintf(bw, "<item id=\"image-%d\" href=\"%s\" media-type=\"%s\"/>\n",
+			i+1, escape(image.name), image.mediaType)
+	}
+	_, _ = bw.WriteString("</manifest>\n<spine>\n")
+	for i := range b.chapters {
+		_, _ = fmt.Fprintf(bw, "<itemref idref=\"chapter-%d\"/>\n", i+1)
+	}
+	_, _ = bw.WriteString("</spine>\n</package>\n")
+	return bw.Flush()
+}
+
+// writeXHTMLHeader writes the beginning of an XHTML content document.
+func (b *book) writeXHTMLHeader(w io.Writer, title string) {
+	language := escape(b.metadata.Language)
+	_, _ = fmt.Fprintf(w, `<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE html>
+<html xmlns="http://www.w3.org/1999/xhtml" xmlns:epub="http://www.idpf.org/2007/ops" xml:lang="%s" lang="%s">
+<head>
+<meta charset="UTF-8" />
+<title>%s</title>
+</head>
+<body>
+`, language, language, escape(title))
+}
+
+// writeNav writes the navigation document. Headings of the chapters are
+// nested by their levels.
+func (b *book) writeNav(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	b.writeXHTMLHeader(bw, b.metadata.Title)
+	_, _ = bw.WriteString("<nav epub:type=\"toc\" id=\"toc\">\n")
+	_, _ = fmt.Fprintf(bw, "<h1>%s</h1>\n", escape(b.metadata.Title))
+	var levels []int
+	for _, c := range b.chapters {
+		for _, heading := range c.headings {
+			switch {
+			case len(levels) == 0 || heading.Level > levels[len(levels)-1]:
+				_, _ = bw.WriteString("<ol>\n")
+				levels = append(levels, heading.Level)
+			default:
+				for len(levels) > 1 && heading.Level < levels[len(levels)-1] &&
+					heading.Level <= levels[len(levels)-2] {
+					_, _ = bw.WriteString("</li>\n</ol>\n")
+					levels = levels[:len(levels)-1]
+				}
+				_, _ = bw.WriteString("</li>\n")
+			}
+			id, _ := heading.AttributeString("id")
+			_, _ = fmt.Fprintf(bw, "<li><a href=\"%s#%s\">%s</a>\n",
+				c.name, escape(string(id.([]byte))), escape(string(plainText(heading, c.source))))
+		}
+	}
+	for range levels {
+		_, _ = bw.WriteString("</li>\n</ol>\n")
+	}
+	_, _ = bw.WriteString("</nav>\n</body>\n</html>\n")
+	return bw.Flush()
+}
+
+// writeChapter writes the chapter as an XHTML content document.
+func (b *book) writeChapter(w io.Writer, c *chapter) error {
+	bw := bufio.NewWriter(w)
+	title := c.title
+	if len(title) == 0 {
+		title = b.metadata.Title
+	}
+	b.writeXHTMLHeader(bw, title)
+	if err := b.config.Markdown.Renderer().Render(bw, c.source, c.document); err != nil {
+		return err
+	}
+	_, _ = bw.WriteString("</body>\n</html>\n")
+	return bw.Flush()
+}
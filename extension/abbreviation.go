@@ -0,0 +1,262 @@
+package extension
+
+import (
+	"bytes"
+	"sort"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/yuin/goldmark"
+	gast "github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/extension/ast"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/renderer"
+	"github.com/yuin/goldmark/renderer/html"
+	"github.com/yuin/goldmark/text"
+	"github.com/yuin/goldmark/util"
+)
+
+var abbreviationListKey = parser.NewContextKey()
+
+type abbreviationDefinitionParser struct {
+}
+
+var defaultAbbreviationDefinitionParser = &abbreviationDefinitionParser{}
+
+// NewAbbreviationDefinitionParser returns a new parser.BlockParser that can parse
+// abbreviation definitions of the Markdown(PHP Markdown Extra) text.
+func NewAbbreviationDefinitionParser() parser.BlockParser {
+	return defaultAbbreviationDefinitionParser
+}
+
+func (b *abbreviationDefinitionParser) Trigger() []byte {
+	return []byte{'*'}
+}
+
+func (b *abbreviationDefinitionParser) Open(
+	parent gast.Node, reader text.Reader, pc parser.Context) (gast.Node, parser.State) {
+	line, segment := reader.PeekLine()
+	pos := pc.BlockOffset()
+	if pos < 0 || line[pos] != '*' {
+		return nil, parser.NoChildren
+	}
+	pos++
+	if pos >= len(line) || line[pos] != '[' {
+		return nil, parser.NoChildren
+	}
+	open := pos + 1
+	closure := util.FindClosure(line[open:], '[', ']', false, false) //nolint:staticcheck
+	if closure < 0 {
+		return nil, parser.NoChildren
+	}
+	closes := open + closure
+	next := closes + 1
+	if next >= len(line) || line[next] != ':' {
+		return nil, parser.NoChildren
+	}
+	label := util.TrimRightSpace(util.TrimLeftSpace(line[open:closes]))
+	if len(label) == 0 {
+		return nil, parser.NoChildren
+	}
+	title := util.TrimRightSpace(util.TrimLeftSpace(line[next+1:]))
+	node := ast.NewAbbreviationDefinition(label, title)
+	node.Lines().Append(segment.TrimRightSpace(reader.Source()))
+	reader.AdvanceToEOL()
+	return node, parser.NoChildren
+}
+
+func (b *abbreviationDefinitionParser) Continue(node gast.Node, reader text.Reader, pc parser.Context) parser.State {
+	return parser.Close
+}
+
+func (b *abbreviationDefinitionParser) Close(node gast.Node, reader text.Reader, pc parser.Context) {
+	def := node.(*ast.AbbreviationDefinition)
+	list := pc.ComputeIfAbsent(abbreviationListKey, func() any {
+		return []*ast.AbbreviationDefinition{}
+	}).([]*ast.AbbreviationDefinition)
+	for _, d := range list {
+		if bytes.Equal(d.Label, def.Label) {
+			return
+		}
+	}
+	pc.Set(abbreviationListKey, append(list, def))
+}
+
+func (b *abbreviationDefinitionParser) CanInterruptParagraph() bool {
+	return true
+}
+
+func (b *abbreviationDefinitionParser) CanAcceptIndentedLine() bool {
+	return false
+}
+
+type abbreviationASTTransformer struct {
+}
+
+var defaultAbbreviationASTTransformer = &abbreviationASTTransformer{}
+
+// NewAbbreviationASTTransformer returns a new parser.ASTTransformer that
+// wraps occurrences of the defined abbreviations with Abbreviation nodes.
+func NewAbbreviationASTTransformer() parser.ASTTransformer {
+	return defaultAbbreviationASTTransformer
+}
+
+func (a *abbreviationASTTransformer) Transform(node *gast.Document, reader text.Reader, pc parser.Context) {
+	tmp := pc.Get(abbreviationListKey)
+	if tmp == nil {
+		return
+	}
+	pc.Set(abbreviationListKey, nil)
+	defs := append([]*ast.AbbreviationDefinition{}, tmp.([]*ast.AbbreviationDefinition)...)
+	// longer labels take precedence over shorter ones like 'HTML5' over 'HTML'.
+	sort.SliceStable(defs, func(i, j int) bool {
+		return len(defs[i].Label) > len(defs[j].Label)
+	})
+
+	var texts []*gast.Text
+	_ = gast.Walk(node, func(n gast.Node, entering bool) (gast.WalkStatus, error) {
+		if !entering {
+			return gast.WalkContinue, nil
+		}
+		switch n.Kind() {
+		case gast.KindCodeSpan, gast.KindLink, gast.KindAutoLink, gast.KindImage,
+			gast.KindRawHTML, ast.KindAbbreviation:
+			return gast.WalkSkipChildren, nil
+		case gast.KindText:
+			if t := n.(*gast.Text); !t.IsRaw() && t.Segment.Padding == 0 {
+				texts = append(texts, t)
+			}
+		}
+		return gast.WalkContinue, nil
+	})
+	source := reader.Source()
+	for _, t := range texts {
+		// texts in cells of grid tables refer to the sources of the cells.
+		a.transformText(t, defs, gast.SourceOf(t, source))
+	}
+}
+
+func (a *abbreviationASTTransformer) transformText(t *gast.Text, defs []*ast.AbbreviationDefinition, source []byte) {
+	parent := t.Parent()
+	segment := t.Segment
+	cursor := segment.Start
+	for i := segment.Start; i < segment.Stop; {
+		if i != segment.Start {
+			if r, _ := utf8.DecodeLastRune(source[segment.Start:i]); isAbbreviationWordRune(r) {
+				_, l := utf8.DecodeRune(source[i:segment.Stop])
+				i += l
+				continue
+			}
+		}
+		var matched *ast.AbbreviationDefinition
+		for _, def := range defs {
+			stop := i + len(def.Label)
+			if stop > segment.Stop || !bytes.Equal(source[i:stop], def.Label) {
+				continue
+			}
+			if stop != segment.Stop {
+				if r, _ := utf8.DecodeRune(source[stop:segment.Stop]); isAbbreviationWordRune(r) {
+					continue
+				}
+			}
+			matched = def
+			break
+		}
+		if matched == nil {
+			_, l := utf8.DecodeRune(source[i:segment.Stop])
+			i += l
+			continue
+		}
+		if cursor < i {
+			parent.InsertBefore(parent, t, gast.NewTextSegment(text.NewSegment(cursor, i)))
+		}
+		stop := i + len(matched.Label)
+		abbr := ast.NewAbbreviation(matched.Title)
+		abbr.AppendChild(abbr, gast.NewTextSegment(text.NewSegment(i, stop)))
+		parent.InsertBefore(parent, t, abbr)
+		cursor = stop
+		i = stop
+	}
+	if cursor == segment.Start {
+		return
+	}
+	if cursor == segment.Stop && !t.SoftLineBreak() && !t.HardLineBreak() {
+		parent.RemoveChild(parent, t)
+		return
+	}
+	// remaining text keeps line break flags of the original text.
+	t.Segment = segment.WithStart(cursor)
+}
+
+func isAbbreviationWordRune(r rune) bool {
+	return r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+// AbbreviationHTMLRenderer is a renderer.NodeRenderer implementation that
+// renders Abbreviation nodes.
+type AbbreviationHTMLRenderer struct {
+	html.Config
+}
+
+// NewAbbreviationHTMLRenderer returns a new AbbreviationHTMLRenderer.
+func NewAbbreviationHTMLRenderer(opts ...html.Option) renderer.NodeRenderer {
+	r := &AbbreviationHTMLRenderer{
+		Config: html.NewConfig(),
+	}
+	for _, opt := range opts {
+		opt.SetHTMLOption(&r.Config)
+	}
+	return r
+}
+
+// RegisterFuncs implements renderer.NodeRenderer.RegisterFuncs.
+func (r *AbbreviationHTMLRenderer) RegisterFuncs(reg renderer.NodeRendererFuncRegisterer) {
+	reg.Register(ast.KindAbbreviation, r.renderAbbreviation)
+	reg.Register(ast.KindAbbreviationDefinition, func(
+		_ util.BufWriter, _ []byte, _ gast.Node, _ bool) (gast.WalkStatus, error) {
+		return gast.WalkSkipChildren, nil
+	})
+}
+
+// AbbreviationAttributeFilter defines attribute names which abbr elements can have.
+var AbbreviationAttributeFilter = html.GlobalAttributeFilter
+
+func (r *AbbreviationHTMLRenderer) renderAbbreviation(
+	w util.BufWriter, source []byte, node gast.Node, entering bool) (gast.WalkStatus, error) {
+	if entering {
+		n := node.(*ast.Abbreviation)
+		_, _ = w.WriteString("<abbr")
+		if len(n.Title) != 0 {
+			_, _ = w.WriteString(` title="`)
+			r.Writer.Write(w, n.Title)
+			_ = w.WriteByte('"')
+		}
+		if n.Attributes() != nil {
+			html.RenderAttributes(w, n, AbbreviationAttributeFilter)
+		}
+		_ = w.WriteByte('>')
+	} else {
+		_, _ = w.WriteString("</abbr>")
+	}
+	return gast.WalkContinue, nil
+}
+
+type abbreviation struct {
+}
+
+// Abbreviation is an extension that allow you to use PHP Markdown Extra Abbreviations.
+var Abbreviation = &abbreviation{}
+
+func (e *abbreviation) Extend(m goldmark.Markdown) {
+	m.Parser().AddOptions(
+		parser.WithBlockParsers(
+			util.Prioritized(NewAbbreviationDefinitionParser(), 999),
+		),
+		parser.WithASTTransformers(
+			util.Prioritized(NewAbbreviationASTTransformer(), 999),
+		),
+	)
+	m.Renderer().AddOptions(renderer.WithNodeRenderers(
+		util.Prioritized(NewAbbreviationHTMLRenderer(), 500),
+	))
+}
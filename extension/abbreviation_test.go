@@ -0,0 +1,21 @@
+package extension
+
+import (
+	"testing"
+
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/renderer/html"
+	"github.com/yuin/goldmark/testutil"
+)
+
+func TestAbbreviation(t *testing.T) {
+	markdown := goldmark.New(
+		goldmark.WithRendererOptions(
+			html.WithUnsafe(),
+		),
+		goldmark.WithExtensions(
+			Abbreviation,
+		),
+	)
+	testutil.DoTestCaseFile(markdown, "_test/abbreviation.txt", t, testutil.ParseCliCaseArg()...)
+}
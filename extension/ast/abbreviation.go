@@ -0,0 +1,96 @@
+package ast
+
+import (
+	"bytes"
+
+	gast "github.com/yuin/goldmark/ast"
+)
+
+// An AbbreviationDefinition struct represents an abbreviation definition of Markdown
+// (PHP Markdown Extra) text like '*[HTML]: HyperText Markup Language'.
+type AbbreviationDefinition struct {
+	gast.BaseBlock
+
+	// Label is an abbreviated text.
+	Label []byte
+
+	// Title is a full text of the abbreviation.
+	Title []byte
+}
+
+// IsRaw implements Node.IsRaw.
+func (n *AbbreviationDefinition) IsRaw() bool {
+	return true
+}
+
+// Dump implements Node.Dump.
+func (n *AbbreviationDefinition) Dump(source []byte, level int) {
+	m := map[string]string{
+		"Label": string(n.Label),
+		"Title": string(n.Title),
+	}
+	gast.DumpHelper(n, source, level, m, nil)
+}
+
+// KindAbbreviationDefinition is a NodeKind of the AbbreviationDefinition node.
+var KindAbbreviationDefinition = gast.NewNodeKind("AbbreviationDefinition")
+
+// Kind implements Node.Kind.
+func (n *AbbreviationDefinition) Kind() gast.NodeKind {
+	return KindAbbreviationDefinition
+}
+
+// CloneNode implements ast.Cloner.CloneNode.
+func (n *AbbreviationDefinition) CloneNode() gast.Node {
+	c := *n
+	c.Label = bytes.Clone(n.Label)
+	c.Title = bytes.Clone(n.Title)
+	return &c
+}
+
+// NewAbbreviationDefinition returns a new AbbreviationDefinition node.
+func NewAbbreviationDefinition(label, title []byte) *AbbreviationDefinition {
+	return &AbbreviationDefinition{
+		Label: label,
+		Title: title,
+	}
+}
+
+// An Abbreviation struct represents an occurrence of the abbreviated text
+// in Markdown(PHP Markdown Extra) text.
+type Abbreviation struct {
+	gast.BaseInline
+
+	// Title is a full text of the abbreviation.
+	Title []byte
+}
+
+// Dump implements Node.Dump.
+func (n *Abbreviation) Dump(source []byte, level int) {
+	m := map[string]string{
+		"Title": string(n.Title),
+	}
+	gast.DumpHelper(n, source, level, m, nil)
+}
+
+// KindAbbreviation is a NodeKind of the Abbreviation node.
+var KindAbbreviation = gast.NewNodeKind("Abbreviation")
+
+// Kind implements Node.Kind.
+func (n *Abbreviation) Kind() gast.NodeKind {
+	return KindAbbreviation
+}
+
+// CloneNode implements ast.Cloner.CloneNode.
+func (n *Abbreviation) CloneNode() gast.Node {
+	c := *n
+	c.Title = bytes.Clone(n.Title)
+	return &c
+}
+
+// NewAbbreviation returns a new Abbreviation node.
+func NewAbbreviation(title []byte) *Abbreviation {
+	return &Abbreviation{
+		Title: title,
+	}
+}
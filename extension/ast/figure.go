@@ -0,0 +1,61 @@
+package ast
+
+import (
+	gast "github.com/yuin/goldmark/ast"
+)
+
+// A Figure struct represents a figure that consists of an image and
+// its caption.
+type Figure struct {
+	gast.BaseBlock
+}
+
+// Dump implements Node.Dump.
+func (n *Figure) Dump(source []byte, level int) {
+	gast.DumpHelper(n, source, level, nil, nil)
+}
+
+// KindFigure is a NodeKind of the Figure node.
+var KindFigure = gast.NewNodeKind("Figure")
+
+// Kind implements Node.Kind.
+func (n *Figure) Kind() gast.NodeKind {
+	return KindFigure
+}
+
+// NewFigure returns a new Figure node.
+func NewFigure() *Figure {
+	return &Figure{}
+}
+
+// A FigureCaption struct represents a caption of a figure.
+type FigureCaption struct {
+	gast.BaseBlock
+
+	// Source is a source that children of this caption refer to.
+	// Source is nil if children refer to the source of the document.
+	Source []byte
+}
+
+// OwnSource implements ast.SourceOwner.OwnSource.
+func (n *FigureCaption) OwnSource() []byte {
+	return n.Source
+}
+
+// Dump implements Node.Dump.
+func (n *FigureCaption) Dump(source []byte, level int) {
+	gast.DumpHelper(n, source, level, nil, nil)
+}
+
+// KindFigureCaption is a NodeKind of the FigureCaption node.
+var KindFigureCaption = gast.NewNodeKind("FigureCaption")
+
+// Kind implements Node.Kind.
+func (n *FigureCaption) Kind() gast.NodeKind {
+	return KindFigureCaption
+}
+
+// NewFigureCaption returns a new FigureCaption node.
+func NewFigureCaption() *FigureCaption {
+	return &FigureCaption{}
+}
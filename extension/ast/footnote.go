@@ -1,6 +1,7 @@
 package ast
 
 import (
+	"bytes"
 	"fmt"
 
 	gast "github.com/yuin/goldmark/ast"
@@ -80,8 +81,21 @@ func NewFootnoteBacklink(index int) *FootnoteBacklink {
 // (PHP Markdown Extra) text.
 type Footnote struct {
 	gast.BaseBlock
+	// Ref is a label of this footnote.
+	// Ref is nil if this footnote is an inline footnote like '^[text]'.
 	Ref   []byte
 	Index int
+
+	// Source is a source that children of this footnote refer to.
+	// Source is nil if children refer to the source of the document.
+	// Footnotes defined in grid table cells and figure captions refer to
+	// sources of the cells and the captions.
+	Source []byte
+}
+
+// OwnSource implements ast.SourceOwner.OwnSource.
+func (n *Footnote) OwnSource() []byte {
+	return n.Source
 }
 
 // Dump implements Node.Dump.
@@ -100,6 +114,13 @@ func (n *Footnote) Kind() gast.NodeKind {
 	return KindFootnote
 }
 
+// CloneNode implements ast.Cloner.CloneNode.
+func (n *Footnote) CloneNode() gast.Node {
+	c := *n
+	c.Ref = bytes.Clone(n.Ref)
+	return &c
+}
+
 // NewFootnote returns a new Footnote node.
 func NewFootnote(ref []byte) *Footnote {
 	return &Footnote{
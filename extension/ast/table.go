@@ -2,6 +2,7 @@ package ast
 
 import (
 	"fmt"
+	"slices"
 	"strings"
 
 	gast "github.com/yuin/goldmark/ast"
@@ -70,6 +71,13 @@ func (n *Table) Kind() gast.NodeKind {
 	return KindTable
 }
 
+// CloneNode implements ast.Cloner.CloneNode.
+func (n *Table) CloneNode() gast.Node {
+	c := *n
+	c.Alignments = slices.Clone(n.Alignments)
+	return &c
+}
+
 // NewTable returns a new Table node.
 func NewTable() *Table {
 	return &Table{
@@ -96,6 +104,13 @@ func (n *TableRow) Kind() gast.NodeKind {
 	return KindTableRow
 }
 
+// CloneNode implements ast.Cloner.CloneNode.
+func (n *TableRow) CloneNode() gast.Node {
+	c := *n
+	c.Alignments = slices.Clone(n.Alignments)
+	return &c
+}
+
 // NewTableRow returns a new TableRow node.
 func NewTableRow(alignments []Alignment) *TableRow {
 	return &TableRow{Alignments: alignments}
@@ -115,6 +130,13 @@ func (n *TableHeader) Kind() gast.NodeKind {
 	return KindTableHeader
 }
 
+// CloneNode implements ast.Cloner.CloneNode.
+func (n *TableHeader) CloneNode() gast.Node {
+	c := *n
+	c.Alignments = slices.Clone(n.Alignments)
+	return &c
+}
+
 // Dump implements Node.Dump.
 func (n *TableHeader) Dump(source []byte, level int) {
 	gast.DumpHelper(n, source, level, nil, nil)
@@ -136,11 +158,32 @@ func NewTableHeader(row *TableRow) *TableHeader {
 type TableCell struct {
 	gast.BaseBlock
 	Alignment Alignment
+
+	// ColSpan is a number of columns that this cell spans.
+	ColSpan int
+
+	// RowSpan is a number of rows that this cell spans.
+	RowSpan int
+
+	// Source is a source that children of this cell refer to.
+	// Source is nil if children refer to the source of the document
+	// like cells of pipe tables. See ast.SourceOwner for how children of
+	// cells that have sources are handled.
+	Source []byte
+}
+
+// OwnSource implements ast.SourceOwner.OwnSource.
+func (n *TableCell) OwnSource() []byte {
+	return n.Source
 }
 
 // Dump implements Node.Dump.
 func (n *TableCell) Dump(source []byte, level int) {
-	gast.DumpHelper(n, source, level, nil, nil)
+	m := map[string]string{
+		"ColSpan": fmt.Sprintf("%d", n.ColSpan),
+		"RowSpan": fmt.Sprintf("%d", n.RowSpan),
+	}
+	gast.DumpHelper(n, source, level, m, nil)
 }
 
 // KindTableCell is a NodeKind of the TableCell node.
@@ -155,5 +198,31 @@ func (n *TableCell) Kind() gast.NodeKind {
 func NewTableCell() *TableCell {
 	return &TableCell{
 		Alignment: AlignNone,
+		ColSpan:   1,
+		RowSpan:   1,
 	}
 }
+
+// A Caption struct represents a caption of a table like 'Table: caption'.
+// A Caption is the first child of the Table.
+type Caption struct {
+	gast.BaseBlock
+}
+
+// Dump implements Node.Dump.
+func (n *Caption) Dump(source []byte, level int) {
+	gast.DumpHelper(n, source, level, nil, nil)
+}
+
+// KindCaption is a NodeKind of the Caption node.
+var KindCaption = gast.NewNodeKind("Caption")
+
+// Kind implements Node.Kind.
+func (n *Caption) Kind() gast.NodeKind {
+	return KindCaption
+}
+
+// NewCaption returns a new Caption node.
+func NewCaption() *Caption {
+	return &Caption{}
+}
@@ -0,0 +1,198 @@
+package extension
+
+import (
+	"github.com/yuin/goldmark"
+	gast "github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/extension/ast"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/renderer"
+	"github.com/yuin/goldmark/renderer/html"
+	"github.com/yuin/goldmark/text"
+	"github.com/yuin/goldmark/util"
+)
+
+type figureASTTransformer struct {
+	parser parser.Parser
+}
+
+// NewFigureASTTransformer returns a parser.ASTTransformer that transforms
+// paragraphs that contain only an image into Figure nodes like the following:
+//
+//	![alt](image.png "caption")
+//
+//	![alt](image.png)
+//	: caption
+//
+// Image titles are parsed as inline Markdown with the given parser and the
+// context of the document, so footnotes in titles are shared with the
+// document. Children of such captions refer to ast.FigureCaption.Source
+// instead of the source of the document.
+func NewFigureASTTransformer(p parser.Parser) parser.ASTTransformer {
+	return &figureASTTransformer{
+		parser: p,
+	}
+}
+
+func (a *figureASTTransformer) Transform(node *gast.Document, reader text.Reader, pc parser.Context) {
+	var paragraphs []gast.Node
+	_ = gast.Walk(node, func(n gast.Node, entering bool) (gast.WalkStatus, error) {
+		if !entering {
+			return gast.WalkContinue, nil
+		}
+		// grid table cells that have a single paragraph have a text block instead.
+		if n.Kind() == gast.KindParagraph ||
+			(n.Kind() == gast.KindTextBlock && n.Parent().Kind() == ast.KindTableCell) {
+			if _, ok := n.FirstChild().(*gast.Image); ok {
+				paragraphs = append(paragraphs, n)
+			}
+			return gast.WalkSkipChildren, nil
+		}
+		return gast.WalkContinue, nil
+	})
+	for _, para := range paragraphs {
+		image := para.FirstChild().(*gast.Image)
+		// paragraphs in grid table cells refer to the sources of the cells.
+		caption := a.captionLine(image, gast.SourceOf(para, reader.Source()))
+		if caption == nil && image.NextSibling() == nil && len(image.Title) != 0 {
+			caption = a.parseTitle(image.Title, pc)
+		}
+		if caption == nil {
+			continue
+		}
+		figure := ast.NewFigure()
+		figure.SetBlankPreviousLines(para.HasBlankPreviousLines())
+		para.Parent().ReplaceChild(para.Parent(), para, figure)
+		figure.AppendChild(figure, image)
+		figure.AppendChild(figure, caption)
+	}
+}
+
+// captionLine returns a caption that consists of lines after the image
+// if the lines start with ':', otherwise nil.
+func (a *figureASTTransformer) captionLine(image *gast.Image, source []byte) *ast.FigureCaption {
+	lineBreak, ok := image.NextSibling().(*gast.Text)
+	if !ok || !lineBreak.Segment.IsEmpty() || !(lineBreak.SoftLineBreak() || lineBreak.HardLineBreak()) {
+		return nil
+	}
+	marker, ok := lineBreak.NextSibling().(*gast.Text)
+	if !ok {
+		return nil
+	}
+	value := marker.Segment.Value(source)
+	if len(value) == 0 || value[0] != ':' || (len(value) > 1 && !util.IsSpace(value[1])) {
+		return nil
+	}
+	caption := ast.NewFigureCaption()
+	parent := image.Parent()
+	parent.RemoveChild(parent, lineBreak)
+	marker.Segment = marker.Segment.WithStart(marker.Segment.Start + 1)
+	marker.Segment = marker.Segment.TrimLeftSpace(source)
+	if marker.Segment.IsEmpty() && !marker.SoftLineBreak() && !marker.HardLineBreak() {
+		parent.RemoveChild(parent, marker)
+	}
+	for c := image.NextSibling(); c != nil; {
+		next := c.NextSibling()
+		caption.AppendChild(caption, c)
+		c = next
+	}
+	return caption
+}
+
+// parseTitle parses the given image title as inline Markdown.
+func (a *figureASTTransformer) parseTitle(title []byte, pc parser.Context) *ast.FigureCaption {
+	caption := ast.NewFigureCaption()
+	caption.Source = title
+	doc := parseFragment(a.parser, title, pc)
+	para, ok := doc.FirstChild().(*gast.Paragraph)
+	if !ok || !isOnlyBlock(para) {
+		// titles like '# title' are not parsed as blocks.
+		caption.Source = nil
+		s := gast.NewString(title)
+		s.SetRaw(true)
+		caption.AppendChild(caption, s)
+		return caption
+	}
+	for c := para.FirstChild(); c != nil; {
+		next := c.NextSibling()
+		caption.AppendChild(caption, c)
+		c = next
+	}
+	return caption
+}
+
+// FigureHTMLRenderer is a renderer.NodeRenderer implementation that
+// renders Figure nodes.
+type FigureHTMLRenderer struct {
+	html.Config
+}
+
+// NewFigureHTMLRenderer returns a new FigureHTMLRenderer.
+func NewFigureHTMLRenderer(opts ...html.Option) renderer.NodeRenderer {
+	r := &FigureHTMLRenderer{
+		Config: html.NewConfig(),
+	}
+	for _, opt := range opts {
+		opt.SetHTMLOption(&r.Config)
+	}
+	return r
+}
+
+// RegisterFuncs implements renderer.NodeRenderer.RegisterFuncs.
+func (r *FigureHTMLRenderer) RegisterFuncs(reg renderer.NodeRendererFuncRegisterer) {
+	reg.Register(ast.KindFigure, r.renderFigure)
+	reg.Register(ast.KindFigureCaption, r.renderFigureCaption)
+}
+
+// FigureAttributeFilter defines attribute names which figure elements can have.
+var FigureAttributeFilter = html.GlobalAttributeFilter
+
+func (r *FigureHTMLRenderer) renderFigure(
+	w util.BufWriter, source []byte, n gast.Node, entering bool) (gast.WalkStatus, error) {
+	if entering {
+		_, _ = w.WriteString("<figure")
+		if n.Attributes() != nil {
+			html.RenderAttributes(w, n, FigureAttributeFilter)
+		}
+		_, _ = w.WriteString(">\n")
+	} else {
+		_, _ = w.WriteString("</figure>\n")
+	}
+	return gast.WalkContinue, nil
+}
+
+// FigureCaptionAttributeFilter defines attribute names which figcaption elements can have.
+var FigureCaptionAttributeFilter = html.GlobalAttributeFilter
+
+func (r *FigureHTMLRenderer) renderFigureCaption(
+	w util.BufWriter, source []byte, n gast.Node, entering bool) (gast.WalkStatus, error) {
+	if entering {
+		_, _ = w.WriteString("\n<figcaption")
+		if n.Attributes() != nil {
+			html.RenderAttributes(w, n, FigureCaptionAttributeFilter)
+		}
+		_ = w.WriteByte('>')
+	} else {
+		_, _ = w.WriteString("</figcaption>\n")
+	}
+	return gast.WalkContinue, nil
+}
+
+type figure struct {
+}
+
+// Figure is an extension that transforms paragraphs that contain only an
+// image into figures with captions.
+var Figure = &figure{}
+
+func (e *figure) Extend(m goldmark.Markdown) {
+	m.Parser().AddOptions(
+		// captions must be parsed after grid table cells and before the
+		// footnote and abbreviation transformers.
+		parser.WithASTTransformers(
+			util.Prioritized(NewFigureASTTransformer(m.Parser()), 200),
+		),
+	)
+	m.Renderer().AddOptions(renderer.WithNodeRenderers(
+		util.Prioritized(NewFigureHTMLRenderer(), 500),
+	))
+}
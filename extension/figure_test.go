@@ -0,0 +1,88 @@
+package extension
+
+import (
+	"testing"
+
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/renderer/html"
+	"github.com/yuin/goldmark/testutil"
+)
+
+func TestFigure(t *testing.T) {
+	markdown := goldmark.New(
+		goldmark.WithRendererOptions(
+			html.WithUnsafe(),
+		),
+		goldmark.WithExtensions(
+			Figure,
+		),
+	)
+	testutil.DoTestCaseFile(markdown, "_test/figure.txt", t, testutil.ParseCliCaseArg()...)
+}
+
+func TestFigureWithDocumentContext(t *testing.T) {
+	markdown := goldmark.New(
+		goldmark.WithExtensions(
+			Figure,
+			Footnote,
+			NewTable(
+				WithTableGridTables(),
+			),
+		),
+	)
+	testutil.DoTestCase(
+		markdown,
+		testutil.MarkdownTestCase{
+			No:          1,
+			Description: "Footnotes in titles belong to the document",
+			Markdown: `text[^1]
+
+![alt](a.png "title[^1]^[inline]")
+
+[^1]: note
+`,
+			Expected: `<p>text<sup id="fnref:1"><a href="#fn:1" class="footnote-ref" role="doc-noteref">1</a></sup></p>
+<figure>
+<img src="a.png" alt="alt" title="title[^1]^[inline]">
+<figcaption>title<sup id="fnref1:1"><a href="#fn:1" class="footnote-ref" role="doc-noteref">1</a></sup><sup id="fnref:2"><a href="#fn:2" class="footnote-ref" role="doc-noteref">2</a></sup></figcaption>
+</figure>
+<div class="footnotes" role="doc-endnotes">
+<hr>
+<ol>
+<li id="fn:1">
+<p>note&#160;<a href="#fnref:1" class="footnote-backref" role="doc-backlink">&#x21a9;&#xfe0e;</a>&#160;<a href="#fnref1:1" class="footnote-backref" role="doc-backlink">&#x21a9;&#xfe0e;</a></p>
+</li>
+<li id="fn:2">
+<p>inline&#160;<a href="#fnref:2" class="footnote-backref" role="doc-backlink">&#x21a9;&#xfe0e;</a></p>
+</li>
+</ol>
+</div>`,
+		},
+		t,
+	)
+
+	testutil.DoTestCase(
+		markdown,
+		testutil.MarkdownTestCase{
+			No:          2,
+			Description: "Images in grid table cells are transformed into figures",
+			Markdown: `+-------------------+
+| ![alt](a.png)     |
+| : *caption*       |
++-------------------+
+`,
+			Expected: `<table>
+<tbody>
+<tr>
+<td><figure>
+<img src="a.png" alt="alt">
+<figcaption><em>caption</em></figcaption>
+</figure>
+</td>
+</tr>
+</tbody>
+</table>`,
+		},
+		t,
+	)
+}
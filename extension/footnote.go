@@ -17,6 +17,7 @@ import (
 
 var footnoteListKey = parser.NewContextKey()
 var footnoteLinkListKey = parser.NewContextKey()
+var inlineFootnoteListKey = parser.NewContextKey()
 
 type footnoteBlockParser struct {
 }
@@ -168,30 +169,142 @@ func (s *footnoteParser) Parse(parent gast.Node, block text.Reader, pc parser.Co
 	}
 
 	fnlink := ast.NewFootnoteLink(index)
+	appendFootnoteLink(fnlink, pc)
+	if line[0] == '!' {
+		parent.AppendChild(parent, gast.NewTextSegment(text.NewSegment(segment.Start, segment.Start+1)))
+	}
+
+	return fnlink
+}
+
+func appendFootnoteLink(fnlink *ast.FootnoteLink, pc parser.Context) {
 	var fnlist []*ast.FootnoteLink
 	if tmp := pc.Get(footnoteLinkListKey); tmp != nil {
 		fnlist = tmp.([]*ast.FootnoteLink)
-	} else {
-		fnlist = []*ast.FootnoteLink{}
-		pc.Set(footnoteLinkListKey, fnlist)
 	}
 	pc.Set(footnoteLinkListKey, append(fnlist, fnlink))
-	if line[0] == '!' {
-		parent.AppendChild(parent, gast.NewTextSegment(text.NewSegment(segment.Start, segment.Start+1)))
+}
+
+type inlineFootnoteParser struct {
+}
+
+var defaultInlineFootnoteParser = &inlineFootnoteParser{}
+
+// NewInlineFootnoteParser returns a new parser.InlineParser that can parse
+// inline footnotes like '^[text]' of the Markdown(Pandoc) text.
+func NewInlineFootnoteParser() parser.InlineParser {
+	return defaultInlineFootnoteParser
+}
+
+func (s *inlineFootnoteParser) Trigger() []byte {
+	return []byte{'^'}
+}
+
+var inlineFootnoteFindClosureOptions = text.FindClosureOptions{
+	CodeSpan: true,
+	Nesting:  true,
+	Newline:  true,
+	Advance:  true,
+}
+
+func (s *inlineFootnoteParser) Parse(parent gast.Node, block text.Reader, pc parser.Context) gast.Node {
+	line, _ := block.PeekLine()
+	if len(line) < 2 || line[1] != '[' || parent.Parent() == nil {
+		return nil
+	}
+	block.Advance(2)
+	segments, found := block.FindClosure('[', ']', inlineFootnoteFindClosureOptions)
+	if !found || segments.Len() == 0 {
+		return nil
+	}
+	blank := true
+	for i := 0; i < segments.Len(); i++ {
+		segment := segments.At(i)
+		if !util.IsBlank(segment.Value(block.Source())) {
+			blank = false
+			break
+		}
+	}
+	if blank {
+		return nil
+	}
+
+	list := pc.ComputeIfAbsent(footnoteListKey, func() any {
+		return ast.NewFootnoteList()
+	}).(*ast.FootnoteList)
+	list.Count++
+	footnote := ast.NewFootnote(nil)
+	footnote.Index = list.Count
+	footnote.SetPos(segments.At(0).Start)
+	paragraph := gast.NewParagraph()
+	paragraph.SetLines(segments)
+	footnote.AppendChild(footnote, paragraph)
+
+	// inserts the footnote after the current block(and preceding inline footnotes)
+	// so that the parser parses inline elements of it later.
+	var at gast.Node = parent
+	for {
+		next, ok := at.NextSibling().(*ast.Footnote)
+		if !ok || next.Ref != nil {
+			break
+		}
+		at = next
 	}
+	at.Parent().InsertAfter(at.Parent(), at, footnote)
 
+	var inlines []*ast.Footnote
+	if tmp := pc.Get(inlineFootnoteListKey); tmp != nil {
+		inlines = tmp.([]*ast.Footnote)
+	}
+	pc.Set(inlineFootnoteListKey, append(inlines, footnote))
+
+	fnlink := ast.NewFootnoteLink(footnote.Index)
+	appendFootnoteLink(fnlink, pc)
 	return fnlink
 }
 
+// FootnotePlacement indicates where footnotes are placed.
+type FootnotePlacement int
+
+const (
+	// FootnotePlacementDocumentEnd places all footnotes at the end of the document.
+	FootnotePlacementDocumentEnd FootnotePlacement = iota
+
+	// FootnotePlacementSectionEnd places footnotes at the end of each section.
+	// Sections are separated by top-level headings whose level is less than or
+	// equal to FootnoteConfig.SectionLevel.
+	FootnotePlacementSectionEnd
+
+	// FootnotePlacementSidenote places each footnote right after the block
+	// that contains the first reference to it.
+	FootnotePlacementSidenote
+)
+
 type footnoteASTTransformer struct {
+	placement    FootnotePlacement
+	sectionLevel int
 }
 
-var defaultFootnoteASTTransformer = &footnoteASTTransformer{}
+var defaultFootnoteASTTransformer = &footnoteASTTransformer{
+	placement:    FootnotePlacementDocumentEnd,
+	sectionLevel: 1,
+}
 
 // NewFootnoteASTTransformer returns a new parser.ASTTransformer that
 // insert a footnote list to the last of the document.
-func NewFootnoteASTTransformer() parser.ASTTransformer {
-	return defaultFootnoteASTTransformer
+// Footnotes are placed according to FootnoteConfig.Placement.
+func NewFootnoteASTTransformer(opts ...FootnoteOption) parser.ASTTransformer {
+	if len(opts) == 0 {
+		return defaultFootnoteASTTransformer
+	}
+	c := NewFootnoteConfig()
+	for _, opt := range opts {
+		opt.SetFootnoteOption(&c)
+	}
+	return &footnoteASTTransformer{
+		placement:    c.Placement,
+		sectionLevel: c.SectionLevel,
+	}
 }
 
 func (a *footnoteASTTransformer) Transform(node *gast.Document, reader text.Reader, pc parser.Context) {
@@ -203,13 +316,24 @@ func (a *footnoteASTTransformer) Transform(node *gast.Document, reader text.Read
 	if tmp := pc.Get(footnoteLinkListKey); tmp != nil {
 		fnlist = tmp.([]*ast.FootnoteLink)
 	}
+	var inlines []*ast.Footnote
+	if tmp := pc.Get(inlineFootnoteListKey); tmp != nil {
+		inlines = tmp.([]*ast.Footnote)
+	}
 
 	pc.Set(footnoteListKey, nil)
 	pc.Set(footnoteLinkListKey, nil)
+	pc.Set(inlineFootnoteListKey, nil)
 
 	if list == nil {
 		return
 	}
+	for _, footnote := range inlines {
+		if parent := footnote.Parent(); parent != nil {
+			parent.RemoveChild(parent, footnote)
+		}
+		list.AppendChild(list, footnote)
+	}
 
 	counter := map[int]int{}
 	if fnlist != nil {
@@ -238,7 +362,7 @@ func (a *footnoteASTTransformer) Transform(node *gast.Document, reader text.Read
 		index := fn.Index
 		if index < 0 {
 			list.RemoveChild(list, footnote)
-		} else {
+		} else if a.placement != FootnotePlacementSidenote {
 			refCount := counter[index]
 			backLink := ast.NewFootnoteBacklink(index)
 			backLink.RefCount = refCount
@@ -255,18 +379,172 @@ func (a *footnoteASTTransformer) Transform(node *gast.Document, reader text.Read
 		}
 		footnote = next
 	}
+	sortFootnotes(list)
+	if list.Count <= 0 {
+		if parent := list.Parent(); parent != nil {
+			parent.RemoveChild(parent, list)
+		}
+		return
+	}
+
+	switch a.placement {
+	case FootnotePlacementSectionEnd:
+		a.placeSectionEnd(node, list)
+	case FootnotePlacementSidenote:
+		a.placeSidenotes(node, list, fnlist)
+	default:
+		node.AppendChild(node, list)
+	}
+}
+
+// definedFootnotes returns footnotes that are defined in the document so far.
+func definedFootnotes(pc parser.Context) []*ast.Footnote {
+	var footnotes []*ast.Footnote
+	if list, ok := pc.Get(footnoteListKey).(*ast.FootnoteList); ok {
+		for c := list.FirstChild(); c != nil; c = c.NextSibling() {
+			if footnote, ok := c.(*ast.Footnote); ok {
+				footnotes = append(footnotes, footnote)
+			}
+		}
+	}
+	if inlines, ok := pc.Get(inlineFootnoteListKey).([]*ast.Footnote); ok {
+		footnotes = append(footnotes, inlines...)
+	}
+	return footnotes
+}
+
+func sortFootnotes(list *ast.FootnoteList) {
 	list.SortChildren(func(n1, n2 gast.Node) int {
 		if n1.(*ast.Footnote).Index < n2.(*ast.Footnote).Index {
 			return -1
 		}
 		return 1
 	})
-	if list.Count <= 0 {
-		list.Parent().RemoveChild(list.Parent(), list)
-		return
+}
+
+func (a *footnoteASTTransformer) placeSectionEnd(node *gast.Document, list *ast.FootnoteList) {
+	if parent := list.Parent(); parent != nil {
+		parent.RemoveChild(parent, list)
+	}
+	footnotes := map[int]*ast.Footnote{}
+	for c := list.FirstChild(); c != nil; c = c.NextSibling() {
+		fn := c.(*ast.Footnote)
+		footnotes[fn.Index] = fn
 	}
 
-	node.AppendChild(node, list)
+	section := ast.NewFootnoteList()
+	var collect func(n gast.Node)
+	collect = func(n gast.Node) {
+		var found []*ast.Footnote
+		_ = gast.Walk(n, func(n gast.Node, entering bool) (gast.WalkStatus, error) {
+			if !entering || n.Kind() != ast.KindFootnoteLink {
+				return gast.WalkContinue, nil
+			}
+			if fn, ok := footnotes[n.(*ast.FootnoteLink).Index]; ok {
+				delete(footnotes, fn.Index)
+				found = append(found, fn)
+			}
+			return gast.WalkContinue, nil
+		})
+		for _, fn := range found {
+			list.RemoveChild(list, fn)
+			section.AppendChild(section, fn)
+			// footnotes referred from this footnote belong to the same section.
+			collect(fn)
+		}
+	}
+	flush := func(at gast.Node) {
+		if !section.HasChildren() {
+			return
+		}
+		section.Count = section.ChildCount()
+		sortFootnotes(section)
+		if at == nil {
+			node.AppendChild(node, section)
+		} else {
+			node.InsertBefore(node, at, section)
+		}
+		section = ast.NewFootnoteList()
+	}
+	for c := node.FirstChild(); c != nil; {
+		next := c.NextSibling()
+		if heading, ok := c.(*gast.Heading); ok && heading.Level <= a.sectionLevel {
+			flush(heading)
+		}
+		collect(c)
+		c = next
+	}
+	// footnotes that are not referred from the document body.
+	for list.HasChildren() {
+		fn := list.FirstChild()
+		list.RemoveChild(list, fn)
+		section.AppendChild(section, fn)
+	}
+	flush(nil)
+}
+
+func (a *footnoteASTTransformer) placeSidenotes(node *gast.Document, list *ast.FootnoteList,
+	fnlist []*ast.FootnoteLink) {
+	footnotes := map[int]*ast.Footnote{}
+	for c := list.FirstChild(); c != nil; c = c.NextSibling() {
+		fn := c.(*ast.Footnote)
+		footnotes[fn.Index] = fn
+	}
+	placed := map[gast.Node]bool{}
+	for _, fnlink := range fnlist {
+		fn, ok := footnotes[fnlink.Index]
+		if !ok {
+			continue
+		}
+		target := sidenoteTarget(fnlink)
+		if target == nil || isAncestorOrSelf(fn, target) {
+			continue
+		}
+		// keeps sidenotes after the same block in order of their references.
+		for placed[target.NextSibling()] {
+			target = target.NextSibling()
+		}
+		delete(footnotes, fn.Index)
+		placed[fn] = true
+		list.RemoveChild(list, fn)
+		target.Parent().InsertAfter(target.Parent(), target, fn)
+	}
+	if parent := list.Parent(); parent != nil {
+		parent.RemoveChild(parent, list)
+	}
+	if list.HasChildren() {
+		list.Count = list.ChildCount()
+		node.AppendChild(node, list)
+	}
+}
+
+func isAncestorOrSelf(ancestor, n gast.Node) bool {
+	for ; n != nil; n = n.Parent() {
+		if n == ancestor {
+			return true
+		}
+	}
+	return false
+}
+
+// sidenoteTarget returns a block that a sidenote for the given footnote link
+// should be placed after.
+func sidenoteTarget(fnlink *ast.FootnoteLink) gast.Node {
+	var target gast.Node = fnlink
+	for target.Type() == gast.TypeInline {
+		if target = target.Parent(); target == nil {
+			return nil
+		}
+	}
+	for parent := target.Parent(); parent != nil; parent = target.Parent() {
+		switch parent.Kind() {
+		case gast.KindDocument, gast.KindBlockquote, gast.KindListItem,
+			ast.KindFootnote, ast.KindDefinitionDescription:
+			return target
+		}
+		target = parent
+	}
+	return nil
 }
 
 // FootnoteConfig holds configuration values for the footnote extension.
@@ -299,6 +577,13 @@ type FootnoteConfig struct {
 
 	// BacklinkHTML is an HTML content for footnote backlinks.
 	BacklinkHTML []byte
+
+	// Placement indicates where footnotes are placed.
+	Placement FootnotePlacement
+
+	// SectionLevel is a maximum heading level that starts a new section
+	// when Placement is FootnotePlacementSectionEnd.
+	SectionLevel int
 }
 
 // FootnoteOption interface is a functional option interface for the extension.
@@ -317,6 +602,8 @@ func NewFootnoteConfig() FootnoteConfig {
 		LinkClass:     []byte("footnote-ref"),
 		BacklinkClass: []byte("footnote-backref"),
 		BacklinkHTML:  []byte("&#x21a9;&#xfe0e;"),
+		Placement:     FootnotePlacementDocumentEnd,
+		SectionLevel:  1,
 	}
 }
 
@@ -337,6 +624,10 @@ func (c *FootnoteConfig) SetOption(name renderer.OptionName, value any) {
 		c.BacklinkClass = value.([]byte)
 	case optFootnoteBacklinkHTML:
 		c.BacklinkHTML = value.([]byte)
+	case optFootnotePlacement:
+		c.Placement = value.(FootnotePlacement)
+	case optFootnoteSectionLevel:
+		c.SectionLevel = value.(int)
 	default:
 		c.Config.SetOption(name, value)
 	}
@@ -500,6 +791,47 @@ func WithFootnoteBacklinkHTML[T []byte | string](a T) FootnoteOption {
 	return &withFootnoteBacklinkHTML{[]byte(a)}
 }
 
+const optFootnotePlacement renderer.OptionName = "FootnotePlacement"
+
+type withFootnotePlacement struct {
+	value FootnotePlacement
+}
+
+func (o *withFootnotePlacement) SetConfig(c *renderer.Config) {
+	c.Options[optFootnotePlacement] = o.value
+}
+
+func (o *withFootnotePlacement) SetFootnoteOption(c *FootnoteConfig) {
+	c.Placement = o.value
+}
+
+// WithFootnotePlacement is a functional option that indicates where footnotes are placed.
+// This option takes effect only when it is given to NewFootnote.
+func WithFootnotePlacement(a FootnotePlacement) FootnoteOption {
+	return &withFootnotePlacement{a}
+}
+
+const optFootnoteSectionLevel renderer.OptionName = "FootnoteSectionLevel"
+
+type withFootnoteSectionLevel struct {
+	value int
+}
+
+func (o *withFootnoteSectionLevel) SetConfig(c *renderer.Config) {
+	c.Options[optFootnoteSectionLevel] = o.value
+}
+
+func (o *withFootnoteSectionLevel) SetFootnoteOption(c *FootnoteConfig) {
+	c.SectionLevel = o.value
+}
+
+// WithFootnoteSectionLevel is a functional option that is a maximum heading level
+// that starts a new section when footnotes are placed at the end of each section.
+// This option takes effect only when it is given to NewFootnote.
+func WithFootnoteSectionLevel(a int) FootnoteOption {
+	return &withFootnoteSectionLevel{a}
+}
+
 // FootnoteHTMLRenderer is a renderer.NodeRenderer implementation that
 // renders FootnoteLink nodes.
 type FootnoteHTMLRenderer struct {
@@ -587,6 +919,9 @@ func (r *FootnoteHTMLRenderer) renderFootnote(
 	w util.BufWriter, source []byte, node gast.Node, entering bool) (gast.WalkStatus, error) {
 	n := node.(*ast.Footnote)
 	is := strconv.Itoa(n.Index)
+	if parent := n.Parent(); parent == nil || parent.Kind() != ast.KindFootnoteList {
+		return r.renderSidenote(w, n, entering)
+	}
 	if entering {
 		_, _ = w.WriteString(`<li id="`)
 		_, _ = w.Write(r.idPrefix(node))
@@ -603,6 +938,24 @@ func (r *FootnoteHTMLRenderer) renderFootnote(
 	return gast.WalkContinue, nil
 }
 
+func (r *FootnoteHTMLRenderer) renderSidenote(
+	w util.BufWriter, n *ast.Footnote, entering bool) (gast.WalkStatus, error) {
+	if entering {
+		_, _ = w.WriteString(`<aside id="`)
+		_, _ = w.Write(r.idPrefix(n))
+		_, _ = w.WriteString(`fn:`)
+		_, _ = w.WriteString(strconv.Itoa(n.Index))
+		_, _ = w.WriteString(`" class="footnote" role="doc-footnote"`)
+		if n.Attributes() != nil {
+			html.RenderAttributes(w, n, html.GlobalAttributeFilter)
+		}
+		_, _ = w.WriteString(">\n")
+	} else {
+		_, _ = w.WriteString("</aside>\n")
+	}
+	return gast.WalkContinue, nil
+}
+
 func (r *FootnoteHTMLRenderer) renderFootnoteList(
 	w util.BufWriter, source []byte, node gast.Node, entering bool) (gast.WalkStatus, error) {
 	if entering {
@@ -616,7 +969,11 @@ func (r *FootnoteHTMLRenderer) renderFootnoteList(
 		} else {
 			_, _ = w.WriteString("\n<hr>\n")
 		}
-		_, _ = w.WriteString("<ol>\n")
+		if fn, ok := node.FirstChild().(*ast.Footnote); ok && fn.Index > 1 {
+			_, _ = fmt.Fprintf(w, "<ol start=\"%d\">\n", fn.Index)
+		} else {
+			_, _ = w.WriteString("<ol>\n")
+		}
 	} else {
 		_, _ = w.WriteString("</ol>\n")
 		_, _ = w.WriteString("</div>\n")
@@ -680,9 +1037,10 @@ func (e *footnote) Extend(m goldmark.Markdown) {
 		),
 		parser.WithInlineParsers(
 			util.Prioritized(NewFootnoteParser(), 101),
+			util.Prioritized(NewInlineFootnoteParser(), 102),
 		),
 		parser.WithASTTransformers(
-			util.Prioritized(NewFootnoteASTTransformer(), 999),
+			util.Prioritized(NewFootnoteASTTransformer(e.options...), 999),
 		),
 	)
 	m.Renderer().AddOptions(renderer.WithNodeRenderers(
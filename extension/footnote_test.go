@@ -139,3 +139,110 @@ Another one.[^2]
 		t,
 	)
 }
+
+func TestFootnotePlacement(t *testing.T) {
+	markdown := goldmark.New(
+		goldmark.WithRendererOptions(
+			html.WithUnsafe(),
+		),
+		goldmark.WithExtensions(
+			NewFootnote(
+				WithFootnotePlacement(FootnotePlacementSectionEnd),
+				WithFootnoteSectionLevel(2),
+			),
+		),
+	)
+
+	testutil.DoTestCase(
+		markdown,
+		testutil.MarkdownTestCase{
+			No:          1,
+			Description: "Footnotes at the end of each section",
+			Markdown: `# Title
+
+foo[^1]
+
+## Section
+
+bar^[inline [^2]]
+
+### Subsection
+
+baz[^1]
+
+[^1]: one
+[^2]: two
+`,
+			Expected: `<h1>Title</h1>
+<p>foo<sup id="fnref:1"><a href="#fn:1" class="footnote-ref" role="doc-noteref">1</a></sup></p>
+<div class="footnotes" role="doc-endnotes">
+<hr>
+<ol>
+<li id="fn:1">
+<p>one&#160;<a href="#fnref:1" class="footnote-backref" role="doc-backlink">&#x21a9;&#xfe0e;</a>&#160;<a href="#fnref1:1" class="footnote-backref" role="doc-backlink">&#x21a9;&#xfe0e;</a></p>
+</li>
+</ol>
+</div>
+<h2>Section</h2>
+<p>bar<sup id="fnref:2"><a href="#fn:2" class="footnote-ref" role="doc-noteref">2</a></sup></p>
+<h3>Subsection</h3>
+<p>baz<sup id="fnref1:1"><a href="#fn:1" class="footnote-ref" role="doc-noteref">1</a></sup></p>
+<div class="footnotes" role="doc-endnotes">
+<hr>
+<ol start="2">
+<li id="fn:2">
+<p>inline <sup id="fnref:3"><a href="#fn:3" class="footnote-ref" role="doc-noteref">3</a></sup>&#160;<a href="#fnref:2" class="footnote-backref" role="doc-backlink">&#x21a9;&#xfe0e;</a></p>
+</li>
+<li id="fn:3">
+<p>two&#160;<a href="#fnref:3" class="footnote-backref" role="doc-backlink">&#x21a9;&#xfe0e;</a></p>
+</li>
+</ol>
+</div>`,
+		},
+		t,
+	)
+
+	markdown = goldmark.New(
+		goldmark.WithRendererOptions(
+			html.WithUnsafe(),
+		),
+		goldmark.WithExtensions(
+			NewFootnote(
+				WithFootnotePlacement(FootnotePlacementSidenote),
+			),
+		),
+	)
+
+	testutil.DoTestCase(
+		markdown,
+		testutil.MarkdownTestCase{
+			No:          2,
+			Description: "Footnotes as sidenotes",
+			Markdown: `foo[^1] bar^[inline]
+
+- item[^2]
+- item
+
+[^1]: one
+[^2]: two
+[^3]: unused
+`,
+			Expected: `<p>foo<sup id="fnref:1"><a href="#fn:1" class="footnote-ref" role="doc-noteref">1</a></sup> bar<sup id="fnref:2"><a href="#fn:2" class="footnote-ref" role="doc-noteref">2</a></sup></p>
+<aside id="fn:1" class="footnote" role="doc-footnote">
+<p>one</p>
+</aside>
+<aside id="fn:2" class="footnote" role="doc-footnote">
+<p>inline</p>
+</aside>
+<ul>
+<li>item<sup id="fnref:3"><a href="#fn:3" class="footnote-ref" role="doc-noteref">3</a></sup>
+<aside id="fn:3" class="footnote" role="doc-footnote">
+<p>two</p>
+</aside>
+</li>
+<li>item</li>
+</ul>`,
+		},
+		t,
+	)
+}
@@ -0,0 +1,357 @@
+package extension
+
+import (
+	"bytes"
+	"unicode/utf8"
+
+	gast "github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/extension/ast"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/text"
+	"github.com/yuin/goldmark/util"
+)
+
+var gridTableCellListKey = parser.NewContextKey()
+
+type gridTableParser struct {
+	captions bool
+}
+
+var defaultGridTableParser = &gridTableParser{}
+
+// NewGridTableParser returns a new parser.BlockParser that can parse
+// grid tables of the Markdown(Pandoc) text like the following:
+//
+//	+---------+-----------+
+//	| Fruit   | Price     |
+//	+=========+===========+
+//	| Bananas | - $1.34   |
+//	|         | - cheap   |
+//	+---------+-----------+
+//
+// Cells are parsed as Markdown documents by the parser.ASTTransformer
+// that is returned by NewGridTableASTTransformer.
+func NewGridTableParser(opts ...TableOption) parser.BlockParser {
+	if len(opts) == 0 {
+		return defaultGridTableParser
+	}
+	c := NewTableConfig()
+	for _, opt := range opts {
+		opt.SetTableOption(&c)
+	}
+	return &gridTableParser{
+		captions: c.Captions,
+	}
+}
+
+func (b *gridTableParser) Trigger() []byte {
+	return []byte{'+'}
+}
+
+func (b *gridTableParser) Open(parent gast.Node, reader text.Reader, pc parser.Context) (gast.Node, parser.State) {
+	line, segment := reader.PeekLine()
+	pos := pc.BlockOffset()
+	if pos < 0 || line[pos] != '+' {
+		return nil, parser.NoChildren
+	}
+	boundaries, alignments := parseGridTableBorder(line[pos:], '-')
+	if boundaries == nil {
+		return nil, parser.NoChildren
+	}
+	node := ast.NewTable()
+	node.Alignments = alignments
+	node.Lines().Append(segment)
+	reader.AdvanceToEOL()
+	return node, parser.NoChildren
+}
+
+func (b *gridTableParser) Continue(node gast.Node, reader text.Reader, pc parser.Context) parser.State {
+	line, segment := reader.PeekLine()
+	line = util.TrimLeftSpace(line)
+	if len(line) == 0 || (line[0] != '+' && line[0] != '|') {
+		return parser.Close
+	}
+	node.Lines().Append(segment)
+	reader.AdvanceToEOL()
+	return parser.Continue | parser.NoChildren
+}
+
+func (b *gridTableParser) Close(node gast.Node, reader text.Reader, pc parser.Context) {
+	table := node.(*ast.Table)
+	source := reader.Source()
+	lines := *table.Lines()
+	table.SetLines(text.NewSegments())
+
+	values := make([][]byte, 0, lines.Len())
+	for i := 0; i < lines.Len(); i++ {
+		segment := lines.At(i)
+		values = append(values, util.TrimRightSpace(util.TrimLeftSpace(segment.Value(source))))
+	}
+	boundaries, _ := parseGridTableBorder(values[0], '-')
+	// alignments can be specified in the header separator line.
+	for _, line := range values[1:] {
+		if bs, alignments := parseGridTableBorder(line, '='); len(bs) == len(boundaries) {
+			for _, alignment := range alignments {
+				if alignment != ast.AlignNone {
+					table.Alignments = alignments
+					break
+				}
+			}
+			break
+		}
+	}
+
+	var cells []*ast.TableCell
+	var rowLines [][]byte
+	for _, line := range values[1:] {
+		if line[0] != '+' {
+			rowLines = append(rowLines, line)
+			continue
+		}
+		if len(rowLines) == 0 {
+			continue
+		}
+		row := b.parseRow(rowLines, boundaries, table.Alignments, &cells)
+		rowLines = nil
+		if !table.HasChildren() {
+			if bs, _ := parseGridTableBorder(line, '='); len(bs) == len(boundaries) {
+				table.AppendChild(table, ast.NewTableHeader(row))
+				continue
+			}
+		}
+		table.AppendChild(table, row)
+	}
+	if len(rowLines) != 0 { // the table does not have a bottom border
+		table.AppendChild(table, b.parseRow(rowLines, boundaries, table.Alignments, &cells))
+	}
+	if !table.HasChildren() { // only borders, this is not a table
+		last := lines.At(lines.Len() - 1)
+		lines.Set(lines.Len()-1, last.TrimRightSpace(source))
+		para := gast.NewParagraph()
+		para.SetLines(&lines)
+		table.Parent().ReplaceChild(table.Parent(), table, para)
+		return
+	}
+	if b.captions {
+		attachPrecedingTableCaption(table, reader)
+	}
+
+	list := pc.ComputeIfAbsent(gridTableCellListKey, func() any {
+		return []*ast.TableCell{}
+	}).([]*ast.TableCell)
+	pc.Set(gridTableCellListKey, append(list, cells...))
+}
+
+func (b *gridTableParser) parseRow(lines [][]byte, boundaries []int,
+	alignments []ast.Alignment, cells *[]*ast.TableCell) *ast.TableRow {
+	row := ast.NewTableRow(alignments)
+	last := len(boundaries) - 1
+	start := 0
+	for i := 1; i <= last; i++ {
+		// columns that are not separated by '|' are merged.
+		if i != last && !isGridTableColumnBorder(lines, boundaries[i]) {
+			continue
+		}
+		cell := ast.NewTableCell()
+		cell.Alignment = alignments[start]
+		cell.ColSpan = i - start
+		contents := make([][]byte, 0, len(lines))
+		for _, line := range lines {
+			from := gridTableIndex(line, boundaries[start]+1)
+			to := gridTableIndex(line, boundaries[i])
+			contents = append(contents, util.TrimRightSpace(line[from:to]))
+		}
+		cell.Source = dedentGridTableCell(contents)
+		row.AppendChild(row, cell)
+		*cells = append(*cells, cell)
+		start = i
+	}
+	return row
+}
+
+// parseGridTableBorder parses a border line like '+----+:---:+' and returns
+// display columns of the '+' characters and alignments of the columns.
+// c is a character that is used in the border line.
+func parseGridTableBorder(line []byte, c byte) ([]int, []ast.Alignment) {
+	line = util.TrimRightSpace(line)
+	if len(line) < 3 || line[0] != '+' || line[len(line)-1] != '+' {
+		return nil, nil
+	}
+	boundaries := []int{0}
+	var alignments []ast.Alignment
+	start := 1
+	for i := 1; i < len(line); i++ {
+		if line[i] != '+' {
+			continue
+		}
+		col := line[start:i]
+		left := len(col) > 0 && col[0] == ':'
+		if left {
+			col = col[1:]
+		}
+		right := len(col) > 0 && col[len(col)-1] == ':'
+		if right {
+			col = col[:len(col)-1]
+		}
+		if len(col) == 0 || len(bytes.Trim(col, string(c))) != 0 {
+			return nil, nil
+		}
+		switch {
+		case left && right:
+			alignments = append(alignments, ast.AlignCenter)
+		case left:
+			alignments = append(alignments, ast.AlignLeft)
+		case right:
+			alignments = append(alignments, ast.AlignRight)
+		default:
+			alignments = append(alignments, ast.AlignNone)
+		}
+		boundaries = append(boundaries, i)
+		start = i + 1
+	}
+	return boundaries, alignments
+}
+
+func isGridTableColumnBorder(lines [][]byte, column int) bool {
+	for _, line := range lines {
+		i := gridTableIndex(line, column)
+		if i >= len(line) || line[i] != '|' {
+			return false
+		}
+	}
+	return true
+}
+
+// gridTableIndex returns a byte index of the given display column in the line.
+func gridTableIndex(line []byte, column int) int {
+	w := 0
+	for i := 0; i < len(line); {
+		if w >= column {
+			return i
+		}
+		r, l := utf8.DecodeRune(line[i:])
+		if util.IsEastAsianWideRune(r) {
+			w += 2
+		} else {
+			w++
+		}
+		i += l
+	}
+	return len(line)
+}
+
+// dedentGridTableCell removes common indentation of the lines and
+// joins them with newlines.
+func dedentGridTableCell(lines [][]byte) []byte {
+	indent := -1
+	for _, line := range lines {
+		if util.IsBlank(line) {
+			continue
+		}
+		if w := len(line) - len(util.TrimLeftSpace(line)); indent < 0 || w < indent {
+			indent = w
+		}
+	}
+	var buf bytes.Buffer
+	for _, line := range lines {
+		if !util.IsBlank(line) {
+			_, _ = buf.Write(line[indent:])
+		}
+		_ = buf.WriteByte('\n')
+	}
+	return buf.Bytes()
+}
+
+func (b *gridTableParser) CanInterruptParagraph() bool {
+	return false
+}
+
+func (b *gridTableParser) CanAcceptIndentedLine() bool {
+	return false
+}
+
+type gridTableASTTransformer struct {
+	parser parser.Parser
+}
+
+// NewGridTableASTTransformer returns a parser.ASTTransformer that parses
+// contents of grid table cells as Markdown with the given parser.
+// Cells are parsed with the context of the document, so heading IDs, link
+// references and footnotes are shared with the document. Only blocks and
+// inlines are parsed: ASTTransformers that run after this transformer
+// transform contents of the cells together with the document.
+// Children of the cells refer to ast.TableCell.Source instead of the source
+// of the document.
+func NewGridTableASTTransformer(p parser.Parser) parser.ASTTransformer {
+	return &gridTableASTTransformer{
+		parser: p,
+	}
+}
+
+func (a *gridTableASTTransformer) Transform(node *gast.Document, reader text.Reader, pc parser.Context) {
+	// cells may have grid tables that add cells to the list.
+	for {
+		lst := pc.Get(gridTableCellListKey)
+		if lst == nil {
+			return
+		}
+		pc.Set(gridTableCellListKey, nil)
+		for _, cell := range lst.([]*ast.TableCell) {
+			a.parseCell(cell, node, reader, pc)
+		}
+	}
+}
+
+func (a *gridTableASTTransformer) parseCell(cell *ast.TableCell,
+	node *gast.Document, reader text.Reader, pc parser.Context) {
+	doc := parseFragment(a.parser, cell.Source, pc)
+	// pipe tables in the cell have been parsed after the table transformer.
+	if pc.Get(escapedPipeCellListKey) != nil {
+		defaultTableASTTransformer.Transform(node, reader, pc)
+	}
+	// a cell that has a single paragraph is rendered like cells of pipe tables.
+	// inline footnotes are placed after the paragraph until they are moved
+	// to the footnote list.
+	if para, ok := doc.FirstChild().(*gast.Paragraph); ok && isOnlyBlock(para) {
+		textBlock := gast.NewTextBlock()
+		textBlock.SetLines(para.Lines())
+		for c := para.FirstChild(); c != nil; {
+			next := c.NextSibling()
+			textBlock.AppendChild(textBlock, c)
+			c = next
+		}
+		doc.ReplaceChild(doc, para, textBlock)
+	}
+	for c := doc.FirstChild(); c != nil; {
+		next := c.NextSibling()
+		cell.AppendChild(cell, c)
+		c = next
+	}
+}
+
+func isOnlyBlock(n gast.Node) bool {
+	for c := n.NextSibling(); c != nil; c = c.NextSibling() {
+		if c.Kind() != ast.KindFootnote {
+			return false
+		}
+	}
+	return true
+}
+
+// parseFragment parses the given source that is a part of the document,
+// like a cell of a grid table, with the context of the document.
+// Footnotes defined in the source are moved to the footnote list of the
+// document later, so they refer to the source by ast.Footnote.Source.
+func parseFragment(p parser.Parser, source []byte, pc parser.Context) gast.Node {
+	defined := map[*ast.Footnote]bool{}
+	for _, footnote := range definedFootnotes(pc) {
+		defined[footnote] = true
+	}
+	doc := p.Parse(text.NewReader(source), parser.WithContext(pc), parser.WithoutASTTransformers())
+	for _, footnote := range definedFootnotes(pc) {
+		if !defined[footnote] {
+			footnote.Source = source
+		}
+	}
+	return doc
+}
@@ -160,6 +160,9 @@ func (s *linkifyParser) Trigger() []byte {
 	return []byte{' ', '*', '_', '~', '('}
 }
 
+// Concurrent implements parser.ConcurrentInlineParser.Concurrent.
+func (s *linkifyParser) Concurrent() {}
+
 var (
 	protoHTTP  = []byte("http:")
 	protoHTTPS = []byte("https:")
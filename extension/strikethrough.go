@@ -43,6 +43,9 @@ func (s *strikethroughParser) Trigger() []byte {
 	return []byte{'~'}
 }
 
+// Concurrent implements parser.ConcurrentInlineParser.Concurrent.
+func (s *strikethroughParser) Concurrent() {}
+
 func (s *strikethroughParser) Parse(parent gast.Node, block text.Reader, pc parser.Context) gast.Node {
 	before := block.PrecendingCharacter()
 	line, segment := block.PeekLine()
@@ -50,6 +50,21 @@ type TableConfig struct {
 
 	// TableCellAlignMethod indicates how are table celss aligned.
 	TableCellAlignMethod TableCellAlignMethod
+
+	// CellSpans is true if colspans(empty '||' cells) and rowspans('^^' cells) are enabled.
+	CellSpans bool
+
+	// MultilineRows is true if rows ending with a backslash continue to the next line.
+	MultilineRows bool
+
+	// GridTables is true if grid tables are enabled.
+	GridTables bool
+
+	// HeaderlessTables is true if pipe tables that start with a delimiter row are enabled.
+	HeaderlessTables bool
+
+	// Captions is true if captions like 'Table: caption' are enabled.
+	Captions bool
 }
 
 // TableOption interface is a functional option interface for the extension.
@@ -72,6 +87,16 @@ func (c *TableConfig) SetOption(name renderer.OptionName, value any) {
 	switch name {
 	case optTableCellAlignMethod:
 		c.TableCellAlignMethod = value.(TableCellAlignMethod)
+	case optTableCellSpans:
+		c.CellSpans = value.(bool)
+	case optTableMultilineRows:
+		c.MultilineRows = value.(bool)
+	case optTableGridTables:
+		c.GridTables = value.(bool)
+	case optTableHeaderlessTables:
+		c.HeaderlessTables = value.(bool)
+	case optTableCaptions:
+		c.Captions = value.(bool)
 	default:
 		c.Config.SetOption(name, value)
 	}
@@ -121,6 +146,113 @@ func WithTableCellAlignMethod(a TableCellAlignMethod) TableOption {
 	return &withTableCellAlignMethod{a}
 }
 
+const optTableCellSpans renderer.OptionName = "TableCellSpans"
+
+type withTableCellSpans struct {
+}
+
+func (o *withTableCellSpans) SetConfig(c *renderer.Config) {
+	c.Options[optTableCellSpans] = true
+}
+
+func (o *withTableCellSpans) SetTableOption(c *TableConfig) {
+	c.CellSpans = true
+}
+
+// WithTableCellSpans is a functional option that enables colspans and rowspans.
+// An empty cell like '||' merges into the left cell and
+// a cell that consists of '^^' merges into the above cell.
+// This option takes effect only when it is given to NewTable.
+func WithTableCellSpans() TableOption {
+	return &withTableCellSpans{}
+}
+
+const optTableMultilineRows renderer.OptionName = "TableMultilineRows"
+
+type withTableMultilineRows struct {
+}
+
+func (o *withTableMultilineRows) SetConfig(c *renderer.Config) {
+	c.Options[optTableMultilineRows] = true
+}
+
+func (o *withTableMultilineRows) SetTableOption(c *TableConfig) {
+	c.MultilineRows = true
+}
+
+// WithTableMultilineRows is a functional option that enables multiline rows.
+// A row that ends with a backslash continues to the next line and
+// contents of the next line are appended to the cells in the same columns.
+// Lines of a cell are in the same paragraph: cells can not have multiple
+// paragraphs, and empty cells of continuation lines are ignored.
+// Use grid tables for cells that have multiple paragraphs.
+// This option takes effect only when it is given to NewTable.
+func WithTableMultilineRows() TableOption {
+	return &withTableMultilineRows{}
+}
+
+const optTableGridTables renderer.OptionName = "TableGridTables"
+
+type withTableGridTables struct {
+}
+
+func (o *withTableGridTables) SetConfig(c *renderer.Config) {
+	c.Options[optTableGridTables] = true
+}
+
+func (o *withTableGridTables) SetTableOption(c *TableConfig) {
+	c.GridTables = true
+}
+
+// WithTableGridTables is a functional option that enables Pandoc style grid tables.
+// Cells of grid tables can contain block elements like lists and code blocks.
+// This option takes effect only when it is given to NewTable.
+func WithTableGridTables() TableOption {
+	return &withTableGridTables{}
+}
+
+const optTableHeaderlessTables renderer.OptionName = "TableHeaderlessTables"
+
+type withTableHeaderlessTables struct {
+}
+
+func (o *withTableHeaderlessTables) SetConfig(c *renderer.Config) {
+	c.Options[optTableHeaderlessTables] = true
+}
+
+func (o *withTableHeaderlessTables) SetTableOption(c *TableConfig) {
+	c.HeaderlessTables = true
+}
+
+// WithTableHeaderlessTables is a functional option that enables pipe tables
+// whose first row is a delimiter row. Such tables do not have a header.
+// This option takes effect only when it is given to NewTable.
+func WithTableHeaderlessTables() TableOption {
+	return &withTableHeaderlessTables{}
+}
+
+const optTableCaptions renderer.OptionName = "TableCaptions"
+
+type withTableCaptions struct {
+}
+
+func (o *withTableCaptions) SetConfig(c *renderer.Config) {
+	c.Options[optTableCaptions] = true
+}
+
+func (o *withTableCaptions) SetTableOption(c *TableConfig) {
+	c.Captions = true
+}
+
+// WithTableCaptions is a functional option that enables table captions.
+// A paragraph that starts with 'Table:' or ':' just before or after a table
+// becomes a caption of the table. Attributes like '{#tbl:id}' at the end of
+// the caption are set to the table.
+// This option takes effect only when it is given to NewTable.
+func WithTableCaptions() TableOption {
+	return &withTableCaptions{}
+}
+
 func isTableDelim(bs []byte) bool {
 	if w, _ := util.IndentWidth(bs, 0); w > 3 {
 		return false
@@ -143,50 +275,375 @@ var tableDelimCenter = regexp.MustCompile(`^\s*\:\-+\:\s*$`)
 var tableDelimNone = regexp.MustCompile(`^\s*\-+\s*$`)
 
 type tableParagraphTransformer struct {
+	cellSpans        bool
+	multilineRows    bool
+	headerlessTables bool
+	captions         bool
 }
 
 var defaultTableParagraphTransformer = &tableParagraphTransformer{}
 
 // NewTableParagraphTransformer returns  a new ParagraphTransformer
 // that can transform paragraphs into tables.
-func NewTableParagraphTransformer() parser.ParagraphTransformer {
-	return defaultTableParagraphTransformer
+func NewTableParagraphTransformer(opts ...TableOption) parser.ParagraphTransformer {
+	if len(opts) == 0 {
+		return defaultTableParagraphTransformer
+	}
+	c := NewTableConfig()
+	for _, opt := range opts {
+		opt.SetTableOption(&c)
+	}
+	return &tableParagraphTransformer{
+		cellSpans:        c.CellSpans,
+		multilineRows:    c.MultilineRows,
+		headerlessTables: c.HeaderlessTables,
+		captions:         c.Captions,
+	}
 }
 
 func (b *tableParagraphTransformer) Transform(node *gast.Paragraph, reader text.Reader, pc parser.Context) {
 	ppos := node.Pos()
 	lines := node.Lines()
+	if b.captions {
+		if table, ok := node.PreviousSibling().(*ast.Table); ok && !hasTableCaption(table) {
+			if caption := parseTableCaption(table, lines, reader); caption != nil {
+				node.Parent().RemoveChild(node.Parent(), node)
+				return
+			}
+		}
+	}
 	if lines.Len() < 2 {
 		return
 	}
+	if b.headerlessTables {
+		if alignments := b.parseDelimiter(lines.At(0), reader); alignments != nil {
+			table := ast.NewTable()
+			table.Alignments = alignments
+			table.SetPos(ppos)
+			b.parseBody(table, lines, 1, reader, pc)
+			node.Parent().ReplaceChild(node.Parent(), node, table)
+			if b.captions {
+				attachPrecedingTableCaption(table, reader)
+			}
+			return
+		}
+	}
 	for i := 1; i < lines.Len(); i++ {
 		alignments := b.parseDelimiter(lines.At(i), reader)
 		if alignments == nil {
 			continue
 		}
-		header := b.parseRow(lines.At(i-1), alignments, true, reader, pc)
-		if header == nil || len(alignments) != header.ChildCount() {
+		start := i - 1
+		for b.multilineRows && start > 0 && b.isContinuedRow(lines.At(start-1), reader) {
+			start--
+		}
+		header := b.parseRows(lines, start, i, alignments, true, reader, pc)
+		if header == nil || len(alignments) != b.columns(header) {
 			return
 		}
 		table := ast.NewTable()
 		table.Alignments = alignments
 		table.SetPos(ppos)
 		table.AppendChild(table, ast.NewTableHeader(header))
-		for j := i + 1; j < lines.Len(); j++ {
-			table.AppendChild(table, b.parseRow(lines.At(j), alignments, false, reader, pc))
-		}
-		node.Lines().SetSliced(0, i-1)
+		b.parseBody(table, lines, i+1, reader, pc)
+		node.Lines().SetSliced(0, start)
 		node.Parent().InsertAfter(node.Parent(), node, table)
 		if node.Lines().Len() == 0 {
 			node.Parent().RemoveChild(node.Parent(), node)
 		} else {
-			last := node.Lines().At(i - 2)
+			last := node.Lines().At(start - 1)
 			last.Stop = last.Stop - 1 // trim last newline(\n)
-			node.Lines().Set(i-2, last)
+			node.Lines().Set(start-1, last)
+		}
+		if b.captions {
+			attachPrecedingTableCaption(table, reader)
+		}
+	}
+}
+
+// attachPrecedingTableCaption makes the paragraph just before the table
+// a caption of the table if the paragraph looks like a caption.
+func attachPrecedingTableCaption(table *ast.Table, reader text.Reader) {
+	if hasTableCaption(table) {
+		return
+	}
+	if para, ok := table.PreviousSibling().(*gast.Paragraph); ok {
+		if caption := parseTableCaption(table, para.Lines(), reader); caption != nil {
+			para.Parent().RemoveChild(para.Parent(), para)
 		}
 	}
 }
 
+// parseBody parses lines[from:] as rows of the table.
+// If captions are enabled, lines after a caption line like 'Table: caption'
+// are parsed as a caption of the table.
+func (b *tableParagraphTransformer) parseBody(table *ast.Table, lines *text.Segments, from int,
+	reader text.Reader, pc parser.Context) {
+	to := lines.Len()
+	if b.captions {
+		for j := from; j < to; j++ {
+			segment := lines.At(j)
+			if isTableCaptionLine(segment.Value(reader.Source())) {
+				to = j
+				break
+			}
+		}
+	}
+	for j := from; j < to; {
+		end := j + 1
+		for b.multilineRows && end < to && b.isContinued(lines.At(end-1), reader) {
+			end++
+		}
+		table.AppendChild(table, b.parseRows(lines, j, end, table.Alignments, false, reader, pc))
+		j = end
+	}
+	if b.cellSpans {
+		b.applyRowSpans(table, reader.Source())
+	}
+	if to != lines.Len() {
+		rest := text.NewSegments()
+		for j := to; j < lines.Len(); j++ {
+			rest.Append(lines.At(j))
+		}
+		parseTableCaption(table, rest, reader)
+	}
+}
+
+// isTableCaptionLine returns true if the given line starts with 'Table:' or ':'.
+func isTableCaptionLine(line []byte) bool {
+	return tableCaptionMarkerLength(util.TrimLeftSpace(line)) > 0
+}
+
+func tableCaptionMarkerLength(line []byte) int {
+	if bytes.HasPrefix(line, []byte("Table:")) || bytes.HasPrefix(line, []byte("table:")) {
+		return 6
+	}
+	if len(line) > 1 && line[0] == ':' && util.IsSpace(line[1]) {
+		return 1
+	}
+	return 0
+}
+
+func hasTableCaption(table *ast.Table) bool {
+	return table.FirstChild() != nil && table.FirstChild().Kind() == ast.KindCaption
+}
+
+// parseTableCaption parses the given lines as a caption of the table and
+// inserts the caption into the table. parseTableCaption returns nil if the
+// lines are not a caption.
+func parseTableCaption(table *ast.Table, lines *text.Segments, reader text.Reader) *ast.Caption {
+	source := reader.Source()
+	if lines.Len() == 0 {
+		return nil
+	}
+	first := lines.At(0)
+	line := first.Value(source)
+	trimmed := util.TrimLeftSpace(line)
+	length := tableCaptionMarkerLength(trimmed)
+	if length == 0 {
+		return nil
+	}
+	first = first.WithStart(first.Start + len(line) - len(trimmed) + length)
+	first = first.TrimLeftSpace(source)
+	if lines.Len() == 1 && util.IsBlank(first.Value(source)) {
+		return nil
+	}
+	segments := text.NewSegments()
+	segments.Append(first)
+	for i := 1; i < lines.Len(); i++ {
+		segment := lines.At(i)
+		segments.Append(segment.TrimLeftSpace(source))
+	}
+	lastIndex := segments.Len() - 1
+	last := segments.At(lastIndex)
+	segments.Set(lastIndex, last.TrimRightSpace(source))
+	parseTableCaptionAttributes(table, segments, source)
+	caption := ast.NewCaption()
+	caption.SetLines(segments)
+	if table.FirstChild() == nil {
+		table.AppendChild(table, caption)
+	} else {
+		table.InsertBefore(table, table.FirstChild(), caption)
+	}
+	return caption
+}
+
+// parseTableCaptionAttributes parses attributes like '{#tbl:id}' at the end
+// of the caption and sets them to the table.
+func parseTableCaptionAttributes(table *ast.Table, segments *text.Segments, source []byte) {
+	lastIndex := segments.Len() - 1
+	lastLine := segments.At(lastIndex)
+	lr := text.NewReader(lastLine.Value(source))
+	for {
+		c := lr.Peek()
+		if c == text.EOF || c == '\n' {
+			return
+		}
+		if c == '\\' {
+			lr.Advance(1)
+			if util.IsPunct(lr.Peek()) {
+				lr.Advance(1)
+			}
+			continue
+		}
+		if c == '{' {
+			sl, start := lr.Position()
+			attrs, ok := parser.ParseAttributes(lr)
+			if ok {
+				if nl, _ := lr.PeekLine(); nl == nil || util.IsBlank(nl) {
+					for _, attr := range attrs {
+						table.SetAttribute(attr.Name, attr.Value)
+					}
+					lastLine.Stop = lastLine.Start + start.Start
+					segments.Set(lastIndex, lastLine.TrimRightSpace(source))
+					return
+				}
+			}
+			lr.SetPosition(sl, start)
+		}
+		lr.Advance(1)
+	}
+}
+
+// isContinued returns true if the given line ends with a backslash that is not escaped.
+func (b *tableParagraphTransformer) isContinued(segment text.Segment, reader text.Reader) bool {
+	line := util.TrimRightSpace(segment.Value(reader.Source()))
+	n := 0
+	for i := len(line) - 1; i >= 0 && line[i] == '\\'; i-- {
+		n++
+	}
+	return n%2 == 1
+}
+
+// isContinuedRow returns true if the given line looks like a row that
+// continues to the next line.
+func (b *tableParagraphTransformer) isContinuedRow(segment text.Segment, reader text.Reader) bool {
+	return bytes.IndexByte(segment.Value(reader.Source()), '|') > -1 && b.isContinued(segment, reader)
+}
+
+// parseRows parses lines[from:to] as a row.
+// Cells in continuation lines are merged into the cells in the same columns of the first line.
+func (b *tableParagraphTransformer) parseRows(lines *text.Segments, from, to int,
+	alignments []ast.Alignment, isHeader bool, reader text.Reader, pc parser.Context) *ast.TableRow {
+	source := reader.Source()
+	lineAt := func(i int) text.Segment {
+		segment := lines.At(i)
+		if i < to-1 {
+			segment = segment.TrimRightSpace(source)
+			segment.Stop-- // trim a trailing backslash
+		}
+		return segment
+	}
+	row := b.parseRow(lineAt(from), alignments, isHeader, reader, pc)
+	if to-from < 2 {
+		return row
+	}
+	var columns []*ast.TableCell
+	for c := row.FirstChild(); c != nil; c = c.NextSibling() {
+		cell := c.(*ast.TableCell)
+		for k := 0; k < cell.ColSpan; k++ {
+			columns = append(columns, cell)
+		}
+	}
+	for i := from + 1; i < to; i++ {
+		col := 0
+		next := b.parseRow(lineAt(i), alignments, isHeader, reader, pc)
+		for c := next.FirstChild(); c != nil; c = c.NextSibling() {
+			cell := c.(*ast.TableCell)
+			if col >= len(columns) {
+				break
+			}
+			target := columns[col]
+			col += cell.ColSpan
+			for j := 0; j < cell.Lines().Len(); j++ {
+				segment := cell.Lines().At(j)
+				// blank lines are not paragraph breaks.
+				if segment.IsEmpty() {
+					continue
+				}
+				appendTableCellLine(target, segment)
+			}
+			b.replaceEscapedPipeCell(cell, target, pc)
+		}
+	}
+	return row
+}
+
+// appendTableCellLine appends the given line to the cell.
+// Lines of a multiline cell are held by TextBlock children of the cell
+// because segments of cells do not end with a newline.
+func appendTableCellLine(cell *ast.TableCell, segment text.Segment) {
+	if !cell.HasChildren() && cell.Lines().Len() == 0 {
+		cell.Lines().Append(segment)
+		return
+	}
+	if cell.Lines().Len() != 0 {
+		first := gast.NewTextBlock()
+		first.SetLines(cell.Lines())
+		cell.SetLines(text.NewSegments())
+		cell.AppendChild(cell, first)
+	}
+	line := gast.NewTextBlock()
+	line.Lines().Append(segment)
+	cell.AppendChild(cell, line)
+}
+
+func (b *tableParagraphTransformer) replaceEscapedPipeCell(old, cell *ast.TableCell, pc parser.Context) {
+	lst := pc.Get(escapedPipeCellListKey)
+	if lst == nil {
+		return
+	}
+	for _, v := range lst.([]*escapedPipeCell) {
+		if v.Cell == old {
+			v.Cell = cell
+		}
+	}
+}
+
+func (b *tableParagraphTransformer) columns(row *ast.TableRow) int {
+	n := 0
+	for c := row.FirstChild(); c != nil; c = c.NextSibling() {
+		n += c.(*ast.TableCell).ColSpan
+	}
+	return n
+}
+
+func (b *tableParagraphTransformer) applyRowSpans(table *ast.Table, source []byte) {
+	above := make([]*ast.TableCell, len(table.Alignments))
+	for r := table.FirstChild(); r != nil; r = r.NextSibling() {
+		if r.Kind() != ast.KindTableRow {
+			continue
+		}
+		col := 0
+		spanned := map[*ast.TableCell]bool{}
+		for c := r.FirstChild(); c != nil; {
+			next := c.NextSibling()
+			cell := c.(*ast.TableCell)
+			if col < len(above) && above[col] != nil && isRowSpanMarker(cell, source) {
+				if origin := above[col]; !spanned[origin] {
+					origin.RowSpan++
+					spanned[origin] = true
+				}
+				r.RemoveChild(r, cell)
+			} else {
+				for k := col; k < col+cell.ColSpan && k < len(above); k++ {
+					above[k] = cell
+				}
+			}
+			col += cell.ColSpan
+			c = next
+		}
+	}
+}
+
+func isRowSpanMarker(cell *ast.TableCell, source []byte) bool {
+	if cell.Lines().Len() != 1 {
+		return false
+	}
+	segment := cell.Lines().At(0)
+	return bytes.Equal(segment.Value(source), []byte("^^"))
+}
+
 func (b *tableParagraphTransformer) parseRow(segment text.Segment,
 	alignments []ast.Alignment, isHeader bool, reader text.Reader, pc parser.Context) *ast.TableRow {
 	npos := segment
@@ -242,6 +699,11 @@ func (b *tableParagraphTransformer) parseRow(segment text.Segment,
 				}
 			}
 		}
+		if b.cellSpans && closure == pos && row.LastChild() != nil { // '||' merges into the left cell
+			row.LastChild().(*ast.TableCell).ColSpan++
+			pos = closure + 1
+			continue
+		}
 		seg := text.NewSegment(segment.Start+pos, segment.Start+closure)
 		seg = seg.TrimLeftSpace(source)
 		seg = seg.TrimRightSpace(source)
@@ -249,6 +711,11 @@ func (b *tableParagraphTransformer) parseRow(segment text.Segment,
 		row.AppendChild(row, node)
 		pos = closure + 1
 	}
+	if b.cellSpans && pos == limit && limit < len(line) && row.LastChild() != nil &&
+		(isHeader || i < len(alignments)) { // trailing '||'
+		row.LastChild().(*ast.TableCell).ColSpan++
+		i++
+	}
 	for ; i < len(alignments); i++ {
 		row.AppendChild(row, ast.NewTableCell())
 	}
@@ -296,6 +763,9 @@ func NewTableASTTransformer() parser.ASTTransformer {
 	return defaultTableASTTransformer
 }
 
+// Local implements parser.LocalASTTransformer.Local.
+func (a *tableASTTransformer) Local() {}
+
 func (a *tableASTTransformer) Transform(node *gast.Document, reader text.Reader, pc parser.Context) {
 	lst := pc.Get(escapedPipeCellListKey)
 	if lst == nil {
@@ -364,6 +834,7 @@ func (r *TableHTMLRenderer) RegisterFuncs(reg renderer.NodeRendererFuncRegistere
 	reg.Register(ast.KindTableHeader, r.renderTableHeader)
 	reg.Register(ast.KindTableRow, r.renderTableRow)
 	reg.Register(ast.KindTableCell, r.renderTableCell)
+	reg.Register(ast.KindCaption, r.renderCaption)
 }
 
 // TableAttributeFilter defines attribute names which table elements can have.
@@ -393,6 +864,25 @@ func (r *TableHTMLRenderer) renderTable(
 	return gast.WalkContinue, nil
 }
 
+// TableCaptionAttributeFilter defines attribute names which <caption> elements can have.
+//
+// - align: Deprecated since HTML4, Obsolete since HTML5.
+var TableCaptionAttributeFilter = html.GlobalAttributeFilter.ExtendString(`align`)
+
+func (r *TableHTMLRenderer) renderCaption(
+	w util.BufWriter, source []byte, n gast.Node, entering bool) (gast.WalkStatus, error) {
+	if entering {
+		_, _ = w.WriteString("<caption")
+		if n.Attributes() != nil {
+			html.RenderAttributes(w, n, TableCaptionAttributeFilter)
+		}
+		_ = w.WriteByte('>')
+	} else {
+		_, _ = w.WriteString("</caption>\n")
+	}
+	return gast.WalkContinue, nil
+}
+
 // TableHeaderAttributeFilter defines attribute names which <thead> elements can have.
 //
 // - align: Deprecated since HTML4, Obsolete since HTML5
@@ -433,6 +923,9 @@ var TableRowAttributeFilter = html.GlobalAttributeFilter.ExtendString(`align,bgc
 func (r *TableHTMLRenderer) renderTableRow(
 	w util.BufWriter, source []byte, n gast.Node, entering bool) (gast.WalkStatus, error) {
 	if entering {
+		if prev := n.PreviousSibling(); prev == nil || prev.Kind() == ast.KindCaption { // a table without a header
+			_, _ = w.WriteString("<tbody>\n")
+		}
 		_, _ = w.WriteString("<tr")
 		if n.Attributes() != nil {
 			html.RenderAttributes(w, n, TableRowAttributeFilter)
@@ -524,6 +1017,16 @@ func (r *TableHTMLRenderer) renderTableCell(
 				n.SetAttributeString("style", cob.Bytes())
 			}
 		}
+		if n.ColSpan > 1 {
+			if _, ok := n.AttributeString("colspan"); !ok {
+				_, _ = fmt.Fprintf(w, ` colspan="%d"`, n.ColSpan)
+			}
+		}
+		if n.RowSpan > 1 {
+			if _, ok := n.AttributeString("rowspan"); !ok {
+				_, _ = fmt.Fprintf(w, ` rowspan="%d"`, n.RowSpan)
+			}
+		}
 		if n.Attributes() != nil {
 			if tag == "td" {
 				html.RenderAttributes(w, n, TableTdCellAttributeFilter) // <td>
@@ -557,12 +1060,28 @@ func NewTable(opts ...TableOption) goldmark.Extender {
 func (e *table) Extend(m goldmark.Markdown) {
 	m.Parser().AddOptions(
 		parser.WithParagraphTransformers(
-			util.Prioritized(NewTableParagraphTransformer(), 200),
+			util.Prioritized(NewTableParagraphTransformer(e.options...), 200),
 		),
 		parser.WithASTTransformers(
 			util.Prioritized(defaultTableASTTransformer, 0),
 		),
 	)
+	c := NewTableConfig()
+	for _, opt := range e.options {
+		opt.SetTableOption(&c)
+	}
+	if c.GridTables {
+		m.Parser().AddOptions(
+			parser.WithBlockParsers(
+				util.Prioritized(NewGridTableParser(e.options...), 100),
+			),
+			// cells must be parsed before the footnote and abbreviation
+			// transformers so that they see footnotes and texts of the cells.
+			parser.WithASTTransformers(
+				util.Prioritized(NewGridTableASTTransformer(m.Parser()), 100),
+			),
+		)
+	}
 	m.Renderer().AddOptions(renderer.WithNodeRenderers(
 		util.Prioritized(NewTableHTMLRenderer(e.options...), 500),
 	))
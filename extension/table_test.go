@@ -392,3 +392,523 @@ func TestTableFuzzedPanics(t *testing.T) {
 		t,
 	)
 }
+
+func TestTableWithCellSpans(t *testing.T) {
+	markdown := goldmark.New(
+		goldmark.WithRendererOptions(
+			html.WithXHTML(),
+			html.WithUnsafe(),
+		),
+		goldmark.WithExtensions(
+			NewTable(
+				WithTableCellSpans(),
+			),
+		),
+	)
+	testutil.DoTestCase(
+		markdown,
+		testutil.MarkdownTestCase{
+			No:          1,
+			Description: "Empty cells merge into the left cells and '^^' cells merge into the above cells",
+			Markdown: `
+| Name | Details ||
+| ---- | :-: | --- |
+| a | b ||
+| ^^ | c | d |
+| e | ^^ | f |
+| g || h |
+`,
+			Expected: `<table>
+<thead>
+<tr>
+<th>Name</th>
+<th align="center" colspan="2">Details</th>
+</tr>
+</thead>
+<tbody>
+<tr>
+<td rowspan="2">a</td>
+<td align="center" colspan="2">b</td>
+</tr>
+<tr>
+<td align="center" rowspan="2">c</td>
+<td>d</td>
+</tr>
+<tr>
+<td>e</td>
+<td>f</td>
+</tr>
+<tr>
+<td colspan="2">g</td>
+<td>h</td>
+</tr>
+</tbody>
+</table>`,
+		},
+		t,
+	)
+
+	markdown = goldmark.New(
+		goldmark.WithRendererOptions(
+			html.WithXHTML(),
+			html.WithUnsafe(),
+		),
+		goldmark.WithExtensions(
+			Table,
+		),
+	)
+	testutil.DoTestCase(
+		markdown,
+		testutil.MarkdownTestCase{
+			No:          2,
+			Description: "Cell spans are disabled by default",
+			Markdown: `
+| a || b |
+| --- | --- | --- |
+| c | d | e |
+| ^^ | f | g |
+`,
+			Expected: `<table>
+<thead>
+<tr>
+<th>a</th>
+<th></th>
+<th>b</th>
+</tr>
+</thead>
+<tbody>
+<tr>
+<td>c</td>
+<td>d</td>
+<td>e</td>
+</tr>
+<tr>
+<td>^^</td>
+<td>f</td>
+<td>g</td>
+</tr>
+</tbody>
+</table>`,
+		},
+		t,
+	)
+}
+
+func TestTableWithMultilineRows(t *testing.T) {
+	markdown := goldmark.New(
+		goldmark.WithRendererOptions(
+			html.WithXHTML(),
+			html.WithUnsafe(),
+		),
+		goldmark.WithExtensions(
+			NewTable(
+				WithTableMultilineRows(),
+			),
+		),
+	)
+	testutil.DoTestCase(
+		markdown,
+		testutil.MarkdownTestCase{
+			No:          1,
+			Description: "Rows ending with a backslash continue to the next line",
+			Markdown: `
+| Name | Description |\
+|      | (optional)  |
+| ---- | ----------- |
+| foo  | first line  |\
+|      | *second* line \| with a pipe |
+| bar  | ` + "`a\\|b`" + ` \\
+`,
+			Expected: `<table>
+<thead>
+<tr>
+<th>Name</th>
+<th>Description
+(optional)</th>
+</tr>
+</thead>
+<tbody>
+<tr>
+<td>foo</td>
+<td>first line
+<em>second</em> line | with a pipe</td>
+</tr>
+<tr>
+<td>bar</td>
+<td><code>a|b</code> \</td>
+</tr>
+</tbody>
+</table>`,
+		},
+		t,
+	)
+	testutil.DoTestCase(
+		markdown,
+		testutil.MarkdownTestCase{
+			No:          2,
+			Description: "Blank continuation lines are not paragraph breaks",
+			Markdown: `
+| a | b |
+| - | - |
+| x | first |\
+|   |       |\
+|   | second |
+`,
+			Expected: `<table>
+<thead>
+<tr>
+<th>a</th>
+<th>b</th>
+</tr>
+</thead>
+<tbody>
+<tr>
+<td>x</td>
+<td>first
+second</td>
+</tr>
+</tbody>
+</table>`,
+		},
+		t,
+	)
+}
+
+func TestTableWithGridTables(t *testing.T) {
+	markdown := goldmark.New(
+		goldmark.WithRendererOptions(
+			html.WithXHTML(),
+			html.WithUnsafe(),
+		),
+		goldmark.WithExtensions(
+			NewTable(
+				WithTableGridTables(),
+			),
+		),
+	)
+	testutil.DoTestCase(
+		markdown,
+		testutil.MarkdownTestCase{
+			No:          1,
+			Description: "Cells of grid tables can contain block elements",
+			Markdown: `
++-----------+--------+--------------------+
+| Fruit     | Price  | Advantages         |
++===========+:======:+====================+
+| Bananas   | $1.34  | - built-in wrapper |
+|           |        | - bright color     |
++-----------+--------+--------------------+
+| [Oranges] | $2.10  | ` + "```" + `go              |
+|           |        | x := 1             |
+|           |        | ` + "```" + `                |
++-----------+--------+--------------------+
+| 日本語    | spans two columns           |
++-----------+--------+--------------------+
+
+[Oranges]: /oranges
+`,
+			Expected: `<table>
+<thead>
+<tr>
+<th>Fruit</th>
+<th align="center">Price</th>
+<th>Advantages</th>
+</tr>
+</thead>
+<tbody>
+<tr>
+<td>Bananas</td>
+<td align="center">$1.34</td>
+<td><ul>
+<li>built-in wrapper</li>
+<li>bright color</li>
+</ul>
+</td>
+</tr>
+<tr>
+<td><a href="/oranges">Oranges</a></td>
+<td align="center">$2.10</td>
+<td><pre><code class="language-go">x := 1
+</code></pre>
+</td>
+</tr>
+<tr>
+<td>日本語</td>
+<td align="center" colspan="2">spans two columns</td>
+</tr>
+</tbody>
+</table>`,
+		},
+		t,
+	)
+
+	testutil.DoTestCase(
+		markdown,
+		testutil.MarkdownTestCase{
+			No:          2,
+			Description: "Grid tables without a header",
+			Markdown: `
+> +---+-----+
+> | a | *b* |
+> +---+-----+
+> | c |     |
+> +---+-----+
+`,
+			Expected: `<blockquote>
+<table>
+<tbody>
+<tr>
+<td>a</td>
+<td><em>b</em></td>
+</tr>
+<tr>
+<td>c</td>
+<td></td>
+</tr>
+</tbody>
+</table>
+</blockquote>`,
+		},
+		t,
+	)
+}
+
+func TestTableWithGridTablesDocumentContext(t *testing.T) {
+	markdown := goldmark.New(
+		goldmark.WithParserOptions(
+			parser.WithAutoHeadingID(),
+		),
+		goldmark.WithRendererOptions(
+			html.WithXHTML(),
+		),
+		goldmark.WithExtensions(
+			NewTable(
+				WithTableGridTables(),
+			),
+			Footnote,
+			Abbreviation,
+		),
+	)
+	testutil.DoTestCase(
+		markdown,
+		testutil.MarkdownTestCase{
+			No:          1,
+			Description: "Headings in cells have IDs unique in the document",
+			Markdown: `
+# Intro
+
++----------+
+| # Intro  |
++----------+
+`,
+			Expected: `<h1 id="intro">Intro</h1>
+<table>
+<tbody>
+<tr>
+<td><h1 id="intro-1">Intro</h1>
+</td>
+</tr>
+</tbody>
+</table>`,
+		},
+		t,
+	)
+
+	testutil.DoTestCase(
+		markdown,
+		testutil.MarkdownTestCase{
+			No:          2,
+			Description: "Footnotes and abbreviations in cells belong to the document",
+			Markdown: `
++-------------+----------+
+| a^[inline]  | b[^1]    |
++-------------+----------+
+| HTML        |          |
++-------------+----------+
+
+[^1]: note
+
+*[HTML]: HyperText Markup Language
+`,
+			Expected: `<table>
+<tbody>
+<tr>
+<td>a<sup id="fnref:1"><a href="#fn:1" class="footnote-ref" role="doc-noteref">1</a></sup></td>
+<td>b<sup id="fnref:2"><a href="#fn:2" class="footnote-ref" role="doc-noteref">2</a></sup></td>
+</tr>
+<tr>
+<td><abbr title="HyperText Markup Language">HTML</abbr></td>
+<td></td>
+</tr>
+</tbody>
+</table>
+<div class="footnotes" role="doc-endnotes">
+<hr />
+<ol>
+<li id="fn:1">
+<p>inline&#160;<a href="#fnref:1" class="footnote-backref" role="doc-backlink">&#x21a9;&#xfe0e;</a></p>
+</li>
+<li id="fn:2">
+<p>note&#160;<a href="#fnref:2" class="footnote-backref" role="doc-backlink">&#x21a9;&#xfe0e;</a></p>
+</li>
+</ol>
+</div>`,
+		},
+		t,
+	)
+}
+
+func TestTableWithHeaderlessTables(t *testing.T) {
+	markdown := goldmark.New(
+		goldmark.WithRendererOptions(
+			html.WithXHTML(),
+			html.WithUnsafe(),
+		),
+		goldmark.WithExtensions(
+			NewTable(
+				WithTableHeaderlessTables(),
+			),
+		),
+	)
+	testutil.DoTestCase(
+		markdown,
+		testutil.MarkdownTestCase{
+			No:          1,
+			Description: "Pipe tables whose first row is a delimiter row do not have a header",
+			Markdown: `
+| --- | :-: |
+| a   | b   |
+| c   | d   |
+`,
+			Expected: `<table>
+<tbody>
+<tr>
+<td>a</td>
+<td align="center">b</td>
+</tr>
+<tr>
+<td>c</td>
+<td align="center">d</td>
+</tr>
+</tbody>
+</table>`,
+		},
+		t,
+	)
+}
+
+func TestTableWithCaptions(t *testing.T) {
+	markdown := goldmark.New(
+		goldmark.WithRendererOptions(
+			html.WithXHTML(),
+			html.WithUnsafe(),
+		),
+		goldmark.WithExtensions(
+			NewTable(
+				WithTableCaptions(),
+				WithTableGridTables(),
+			),
+		),
+	)
+	testutil.DoTestCase(
+		markdown,
+		testutil.MarkdownTestCase{
+			No:          1,
+			Description: "Captions after tables",
+			Markdown: `
+| a | b |
+| - | - |
+| c | d |
+Table: A *simple* table {#tbl:simple}
+
++---+
+| e |
++---+
+
+: A grid table
+`,
+			Expected: `<table id="tbl:simple">
+<caption>A <em>simple</em> table</caption>
+<thead>
+<tr>
+<th>a</th>
+<th>b</th>
+</tr>
+</thead>
+<tbody>
+<tr>
+<td>c</td>
+<td>d</td>
+</tr>
+</tbody>
+</table>
+<table>
+<caption>A grid table</caption>
+<tbody>
+<tr>
+<td>e</td>
+</tr>
+</tbody>
+</table>`,
+		},
+		t,
+	)
+
+	testutil.DoTestCase(
+		markdown,
+		testutil.MarkdownTestCase{
+			No:          2,
+			Description: "Captions before tables",
+			Markdown: `
+: Before {.wide}
+
+| a |
+| - |
+| b |
+
+Table: only one caption
+
+: not a caption
+`,
+			Expected: `<table class="wide">
+<caption>Before</caption>
+<thead>
+<tr>
+<th>a</th>
+</tr>
+</thead>
+<tbody>
+<tr>
+<td>b</td>
+</tr>
+</tbody>
+</table>
+<p>Table: only one caption</p>
+<p>: not a caption</p>`,
+		},
+		t,
+	)
+
+	testutil.DoTestCase(
+		goldmark.New(goldmark.WithExtensions(Table)),
+		testutil.MarkdownTestCase{
+			No:          3,
+			Description: "Captions are disabled by default",
+			Markdown: `
+| a |
+| - |
+
+Table: caption
+`,
+			Expected: `<table>
+<thead>
+<tr>
+<th>a</th>
+</tr>
+</thead>
+</table>
+<p>Table: caption</p>`,
+		},
+		t,
+	)
+}
@@ -31,6 +31,9 @@ func (s *taskCheckBoxParser) Trigger() []byte {
 	return []byte{'['}
 }
 
+// Concurrent implements parser.ConcurrentInlineParser.Concurrent.
+func (s *taskCheckBoxParser) Concurrent() {}
+
 func (s *taskCheckBoxParser) Parse(parent gast.Node, block text.Reader, pc parser.Context) gast.Node {
 	// Given AST structure must be like
 	// - List
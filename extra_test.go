@@ -2,18 +2,28 @@ package goldmark_test
 
 import (
 	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
 	"os"
+	"path/filepath"
 	"strconv"
 	"strings"
+	"sync"
 	"testing"
+	"testing/fstest"
 	"time"
 
 	. "github.com/yuin/goldmark"
 	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/extension"
 	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/renderer"
 	"github.com/yuin/goldmark/renderer/html"
 	"github.com/yuin/goldmark/testutil"
 	"github.com/yuin/goldmark/text"
+	"github.com/yuin/goldmark/util"
 )
 
 var testTimeoutMultiplier = 1.0
@@ -279,3 +289,294 @@ func TestDangerousURLWithReferences(t *testing.T) {
 		t.Error("Dangerous image should not be rendered:\n" + string(testutil.DiffPretty(expected, b.Bytes())))
 	}
 }
+
+func TestReparse(t *testing.T) {
+	markdown := New(WithParserOptions(parser.WithAutoHeadingID()))
+	source := []byte("# Title\n\nfirst paragraph\n\nsecond [link]\n\n- a\n- b\n\nlast <http://example.com>\n\n[link]: /url\n")
+	cases := []struct {
+		desc        string
+		edit        parser.Edit
+		incremental bool
+	}{
+		{"insert into a paragraph", parser.Edit{Start: 15, Stop: 15, Text: []byte("*new* ")}, true},
+		{"join paragraphs", parser.Edit{Start: 24, Stop: 26, Text: []byte("\n")}, true},
+		{"open a fenced code block", parser.Edit{Start: 9, Stop: 9, Text: []byte("```\n")}, false},
+		{"remove a link reference definition", parser.Edit{Start: 77, Stop: 90}, false},
+	}
+	for _, c := range cases {
+		doc := markdown.Parser().Parse(text.NewReader(source))
+		result, newSource, changed, err := parser.Reparse(markdown.Parser(), doc, source, c.edit)
+		if err != nil {
+			t.Fatalf("%s: %v", c.desc, err)
+		}
+		if (result == doc) != c.incremental {
+			t.Errorf("%s: incremental should be %v", c.desc, c.incremental)
+		}
+		if len(changed) == 0 {
+			t.Errorf("%s: changed nodes should not be empty", c.desc)
+		}
+		var expected, actual bytes.Buffer
+		_ = markdown.Renderer().Render(&expected, newSource, markdown.Parser().Parse(text.NewReader(newSource)))
+		_ = markdown.Renderer().Render(&actual, newSource, result)
+		if !bytes.Equal(expected.Bytes(), actual.Bytes()) {
+			t.Errorf("%s:\n%s", c.desc, testutil.DiffPretty(expected.Bytes(), actual.Bytes()))
+		}
+	}
+	for _, edit := range []parser.Edit{{Start: 5, Stop: 4}, {Start: -1, Stop: 0}, {Start: 0, Stop: len(source) + 1}} {
+		doc := markdown.Parser().Parse(text.NewReader(source))
+		if _, _, _, err := parser.Reparse(markdown.Parser(), doc, source, edit); err != parser.ErrInvalidEdit {
+			t.Errorf("edit %+v should be rejected: %v", edit, err)
+		}
+	}
+}
+
+func TestConvertStream(t *testing.T) {
+	markdown := New(WithParserOptions(parser.WithAutoHeadingID()))
+	var source bytes.Buffer
+	source.WriteString("# Title\n\n[first]: /first\n\n")
+	for i := 0; i < 3000; i++ {
+		fmt.Fprintf(&source, "## Section %d\n\nA paragraph with [first] and [last] links.\n\n```\ncode\n```\n\n", i)
+	}
+	source.WriteString("# Title\n\n[last]: /last\n")
+
+	var expected, actual bytes.Buffer
+	_ = markdown.Convert(source.Bytes(), &expected)
+	err := ConvertStream(markdown, bytes.NewReader(source.Bytes()), &actual, parser.WithReferencePrescan())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(expected.Bytes(), actual.Bytes()) {
+		t.Errorf("streaming conversion should be same as Convert:\n%s",
+			testutil.DiffPretty(expected.Bytes(), actual.Bytes()))
+	}
+	if !bytes.Contains(actual.Bytes(), []byte(`<h1 id="title-1">`)) {
+		t.Error("heading IDs should be unique across closed blocks")
+	}
+
+	unclosed := "para\n\n```\n" + strings.Repeat("code\n", 100000)
+	err = ConvertStream(markdown, strings.NewReader(unclosed), io.Discard, parser.WithMaxBlockSize(64*1024))
+	if err != parser.ErrBlockTooLarge {
+		t.Errorf("unclosed blocks should be limited: %v", err)
+	}
+	err = ConvertStream(markdown, bytes.NewReader(source.Bytes()), io.Discard, parser.WithMaxBlockSize(64*1024))
+	if err != nil {
+		t.Errorf("closed blocks should not be limited: %v", err)
+	}
+
+	actual.Reset()
+	err = ConvertStream(markdown, strings.NewReader(source.String()), &actual)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.HasPrefix(actual.Bytes(), expected.Bytes()[:100]) {
+		t.Errorf("closed blocks should be rendered:\n%s", actual.Bytes()[:100])
+	}
+	if n := bytes.Count(actual.Bytes(), []byte(`href="/last"`)); n == 0 || n == 3000 {
+		t.Errorf("only links in blocks after the definition should refer to it: %d", n)
+	}
+}
+
+func TestAllocationOptions(t *testing.T) {
+	bs, err := os.ReadFile("_test/spec.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var testCases []commonmarkSpecTestCase
+	if err := json.Unmarshal(bs, &testCases); err != nil {
+		t.Fatal(err)
+	}
+	markdown := New(WithRendererOptions(html.WithXHTML(), html.WithUnsafe()))
+	lowAlloc := New(
+		WithParserOptions(parser.WithNodeArena(), parser.WithContextPool()),
+		WithRendererOptions(html.WithXHTML(), html.WithUnsafe(), renderer.WithBufWriterPool()),
+	)
+	// runs twice so that pooled objects are reused.
+	for i := 0; i < 2; i++ {
+		for _, c := range testCases {
+			var expected, actual bytes.Buffer
+			_ = markdown.Convert([]byte(c.Markdown), &expected)
+			_ = lowAlloc.Convert([]byte(c.Markdown), &actual)
+			if !bytes.Equal(expected.Bytes(), actual.Bytes()) {
+				t.Errorf("example %d:\n%s", c.Example, testutil.DiffPretty(expected.Bytes(), actual.Bytes()))
+			}
+		}
+	}
+
+	pc := parser.NewContext()
+	_ = markdown.Parser().Parse(text.NewReader([]byte("[a]: /url\n")), parser.WithContext(pc))
+	if _, ok := pc.Reference("a"); !ok {
+		t.Fatal("reference should be defined")
+	}
+	pc.(parser.ContextResetter).Reset()
+	if _, ok := pc.Reference("a"); ok {
+		t.Error("references should be cleared by Reset")
+	}
+}
+
+func TestConvertBatch(t *testing.T) {
+	markdown := New(
+		WithExtensions(extension.GFM, extension.Footnote),
+		WithParserOptions(parser.WithAutoHeadingID()),
+	)
+	var sources []BatchSource
+	for i := 0; i < 200; i++ {
+		sources = append(sources, BatchSource{
+			Name:   fmt.Sprintf("doc%d.md", i),
+			Source: []byte(fmt.Sprintf("# Title %d\n\n| a |\n| - |\n| ~~b~~ |\n\nfootnote[^1]\n\n[^1]: note %d\n", i, i)),
+		})
+	}
+	sources = append(sources,
+		BatchSource{Name: "broken.md", Err: os.ErrNotExist},
+		BatchSource{Name: "panic.md", Source: []byte("panic\n")})
+
+	var mu sync.Mutex
+	outputs := map[string][]byte{}
+	stats, err := ConvertBatch(markdown, SliceSources(sources...), func(name string, output []byte) error {
+		if name == "panic.md" {
+			panic("output failed")
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		outputs[name] = bytes.Clone(output)
+		return nil
+	}, WithParallelism(8))
+	if !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("errors of sources should be aggregated: %v", err)
+	}
+	if stats.Documents != 202 || stats.Failed != 2 || stats.Errors[0].Name != "broken.md" ||
+		stats.Errors[1].Name != "panic.md" || !strings.Contains(stats.Errors[1].Error(), "output failed") {
+		t.Errorf("unexpected stats: %+v", stats)
+	}
+	var perr *PanicError
+	if !errors.As(stats.Errors[1], &perr) || perr.Value != "output failed" || len(perr.Stack) == 0 ||
+		stats.Errors[1].Error() != "panic.md: panic: output failed" {
+		t.Errorf("panics should be reported as PanicErrors: %v", stats.Errors[1])
+	}
+	for _, s := range sources[:200] {
+		var expected bytes.Buffer
+		_ = markdown.Convert(s.Source, &expected)
+		if !bytes.Equal(expected.Bytes(), outputs[s.Name]) {
+			t.Errorf("%s:\n%s", s.Name, testutil.DiffPretty(expected.Bytes(), outputs[s.Name]))
+		}
+	}
+
+	dir := t.TempDir()
+	fsys := fstest.MapFS{
+		"a.md":          {Data: []byte("# a\n")},
+		"sub/b.md":      {Data: []byte("*b*\n")},
+		"sub/other.txt": {Data: []byte("other\n")},
+	}
+	stats, err = ConvertBatch(markdown, FileSources(fsys, ".md"), FileOutput(dir, ".html"))
+	if err != nil || stats.Documents != 2 {
+		t.Fatalf("unexpected result: %+v, %v", stats, err)
+	}
+	b, err := os.ReadFile(filepath.Join(dir, "sub", "b.html"))
+	if err != nil || string(b) != "<p><em>b</em></p>\n" {
+		t.Errorf("unexpected output: %q, %v", b, err)
+	}
+
+	out := filepath.Join(dir, "out")
+	escapes := SliceSources(
+		BatchSource{Name: "../escape.md", Source: []byte("x\n")},
+		BatchSource{Name: "/abs.md", Source: []byte("x\n")},
+		BatchSource{Name: "sub/../ok.md", Source: []byte("x\n")},
+	)
+	stats, err = ConvertBatch(markdown, escapes, FileOutput(out, ".html"))
+	if !errors.Is(err, ErrNotLocalName) || stats.Failed != 2 {
+		t.Errorf("names out of the directory should be rejected: %+v, %v", stats, err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "escape.html")); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("files should not be written out of the directory: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(out, "ok.html")); err != nil {
+		t.Errorf("local names should be written: %v", err)
+	}
+}
+
+func TestParallelInlineParsing(t *testing.T) {
+	var source bytes.Buffer
+	for i := 0; i < 1000; i++ {
+		fmt.Fprintf(&source, "## Section %d\n\n*emphasis* __strong__ `code` ~~del~~ \"quoted\" https://example.com/%d\n\n", i, i)
+		fmt.Fprintf(&source, "- [x] [link][ref%d] and a footnote[^%d]\n- <http://example.com>\n\n", i, i)
+		fmt.Fprintf(&source, "| a | b |\n| - | - |\n| **c** | d |\n\n[ref%d]: /url%d\n[^%d]: note *%d*\n\n", i, i, i, i)
+		fmt.Fprintf(&source, "para with note^[inline *note* %d] here.\n\n", i)
+	}
+	extensions := WithExtensions(extension.GFM, extension.Footnote, extension.Typographer)
+	markdown := New(extensions, WithParserOptions(parser.WithAutoHeadingID()))
+	parallel := New(extensions, WithParserOptions(parser.WithAutoHeadingID(), parser.WithNodeArena(),
+		parser.WithParallelInlineParsing(4)))
+
+	var expected bytes.Buffer
+	_ = markdown.Convert(source.Bytes(), &expected)
+	if !bytes.Contains(expected.Bytes(), []byte("<p>inline <em>note</em> 999&#160;")) {
+		t.Fatal("inline footnotes should be parsed")
+	}
+	for i := 0; i < 3; i++ {
+		var actual bytes.Buffer
+		_ = parallel.Convert(source.Bytes(), &actual)
+		if !bytes.Equal(expected.Bytes(), actual.Bytes()) {
+			t.Fatalf("parallel parsing should be same as sequential parsing:\n%s",
+				testutil.DiffPretty(expected.Bytes(), actual.Bytes()))
+		}
+	}
+}
+
+// plainTextRenderer renders texts, skips children of headings and stops
+// after the first paragraph if excerpt is true.
+type plainTextRenderer struct {
+	excerpt bool
+}
+
+func (r *plainTextRenderer) RegisterFuncs(reg renderer.NodeRendererFuncRegisterer) {
+	reg.Register(ast.KindText, func(w util.BufWriter, source []byte, n ast.Node, entering bool) (ast.WalkStatus, error) {
+		if entering {
+			_, _ = w.Write(n.(*ast.Text).Value(source))
+		}
+		return ast.WalkContinue, nil
+	})
+	reg.Register(ast.KindHeading, func(w util.BufWriter, source []byte, n ast.Node, entering bool) (ast.WalkStatus, error) {
+		return ast.WalkSkipChildren, nil
+	})
+	reg.Register(ast.KindParagraph, func(w util.BufWriter, source []byte, n ast.Node, entering bool) (ast.WalkStatus, error) {
+		if entering {
+			return ast.WalkContinue, nil
+		}
+		_ = w.WriteByte('\n')
+		if r.excerpt {
+			return ast.WalkStop, nil
+		}
+		return ast.WalkContinue, nil
+	})
+}
+
+func TestRenderMulti(t *testing.T) {
+	source := []byte("# Title\n\nfirst *paragraph*\n\n## Section\n\nsecond [link](/url)\n")
+	markdown := New()
+	renderers := []renderer.Renderer{
+		markdown.Renderer(),
+		renderer.NewRenderer(renderer.WithNodeRenderers(util.Prioritized(&plainTextRenderer{}, 100))),
+		renderer.NewRenderer(renderer.WithNodeRenderers(util.Prioritized(&plainTextRenderer{excerpt: true}, 100))),
+	}
+	doc := markdown.Parser().Parse(text.NewReader(source))
+	outputs := make([]bytes.Buffer, len(renderers))
+	targets := make([]renderer.Target, len(renderers))
+	for i, r := range renderers {
+		targets[i] = renderer.Target{Renderer: r, Writer: &outputs[i]}
+	}
+	if err := renderer.RenderMulti(source, doc, targets...); err != nil {
+		t.Fatal(err)
+	}
+	for i, r := range renderers {
+		var expected bytes.Buffer
+		_ = r.Render(&expected, source, doc)
+		if !bytes.Equal(expected.Bytes(), outputs[i].Bytes()) {
+			t.Errorf("target %d:\n%s", i, testutil.DiffPretty(expected.Bytes(), outputs[i].Bytes()))
+		}
+	}
+	if s := outputs[1].String(); s != "first paragraph\nsecond link\n" {
+		t.Errorf("unexpected text: %q", s)
+	}
+	if s := outputs[2].String(); s != "first paragraph\n" {
+		t.Errorf("unexpected excerpt: %q", s)
+	}
+}
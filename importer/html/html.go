@@ -0,0 +1,528 @@
+// Package html implements an importer that converts HTML into goldmark AST.
+//
+// Elements that have counterparts in Markdown like headings, lists, links
+// and tables are converted into the corresponding nodes. Other elements are
+// kept as ast.HTMLBlock and ast.RawHTML nodes, so documents can be rendered
+// by any goldmark renderer.
+package html
+
+import (
+	"bytes"
+	"strconv"
+	"strings"
+
+	"github.com/yuin/goldmark/ast"
+	east "github.com/yuin/goldmark/extension/ast"
+	"github.com/yuin/goldmark/text"
+	"github.com/yuin/goldmark/util"
+)
+
+// Import converts the given HTML into a document.
+// Segments of the nodes in the document refer to the returned source,
+// so the source must be passed to renderers with the document.
+//
+// Import never fails: like web browsers, broken HTML is parsed leniently.
+func Import(src []byte) (ast.Node, []byte) {
+	im := &importer{html: src}
+	doc := ast.NewDocument()
+	im.convertBlocks(doc, parse(src).children)
+	return doc, im.source
+}
+
+type importer struct {
+	// html is the HTML to import.
+	html []byte
+
+	// source is a source that segments of nodes refer to.
+	source []byte
+
+	// space is true if the last inline content ends with a whitespace.
+	space bool
+}
+
+// segment appends the given value to the source and returns a segment
+// of the value.
+func (im *importer) segment(value []byte) text.Segment {
+	start := len(im.source)
+	im.source = append(im.source, value...)
+	return text.NewSegment(start, len(im.source))
+}
+
+// raw returns a segment of the given range of the HTML.
+func (im *importer) raw(start, stop int) text.Segment {
+	return im.segment(im.html[start:stop])
+}
+
+// isInline returns true if the given node is a part of inline contents.
+func isInline(n *node) bool {
+	return n.typ == textNode || (n.typ == elementNode && !isBlockElement(n.name))
+}
+
+// isBlank returns true if the given nodes have no visible contents.
+func isBlank(nodes []*node) bool {
+	for _, n := range nodes {
+		if n.typ != textNode || len(util.TrimLeftSpace(n.text)) != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// hasParagraph returns true if the given element has paragraphs.
+func hasParagraph(n *node) bool {
+	for _, c := range n.children {
+		if c.name == "p" {
+			return true
+		}
+	}
+	return false
+}
+
+// isTight returns true if inline contents of the given block are rendered
+// without paragraphs.
+func isTight(n ast.Node) bool {
+	switch typed := n.(type) {
+	case *ast.ListItem:
+		list, ok := typed.Parent().(*ast.List)
+		return ok && list.IsTight
+	case *east.DefinitionDescription:
+		return typed.IsTight
+	}
+	return false
+}
+
+// convertBlocks converts the given nodes into blocks and appends them to
+// the parent. Consecutive inline contents are converted into paragraphs.
+func (im *importer) convertBlocks(parent ast.Node, nodes []*node) {
+	var inlines []*node
+	flush := func() {
+		if !isBlank(inlines) {
+			var block ast.Node = ast.NewParagraph()
+			if isTight(parent) {
+				block = ast.NewTextBlock()
+			}
+			im.convertInlineBlock(block, inlines)
+			parent.AppendChild(parent, block)
+		}
+		inlines = inlines[:0]
+	}
+	for _, n := range nodes {
+		if isInline(n) {
+			inlines = append(inlines, n)
+			continue
+		}
+		flush()
+		im.convertBlock(parent, n)
+	}
+	flush()
+}
+
+// convertBlock converts the given block element.
+func (im *importer) convertBlock(parent ast.Node, n *node) {
+	var block ast.Node
+	switch n.name {
+	case "":
+		// comments.
+		block = im.htmlBlock(ast.HTMLBlockType2, n)
+	case "h1", "h2", "h3", "h4", "h5", "h6":
+		heading := ast.NewHeading(int(n.name[1] - '0'))
+		if id, ok := n.attr("id"); ok {
+			heading.SetAttributeString("id", id)
+		}
+		im.convertInlineBlock(heading, n.children)
+		block = heading
+	case "p":
+		block = ast.NewParagraph()
+		im.convertInlineBlock(block, n.children)
+	case "blockquote":
+		block = ast.NewBlockquote()
+		im.convertBlocks(block, n.children)
+	case "ul", "ol":
+		block = im.convertList(n)
+	case "dl":
+		block = im.convertDefinitionList(n)
+	case "pre":
+		block = im.convertCodeBlock(n)
+	case "hr":
+		block = ast.NewThematicBreak()
+	case "table":
+		block = im.convertTable(n)
+	case "head":
+		// metadata is not a content.
+		return
+	case "script", "style", "textarea":
+		block = im.htmlBlock(ast.HTMLBlockType1, n)
+	case "address", "article", "aside", "body", "center", "div", "footer", "header",
+		"html", "main", "nav", "section":
+		// containers are transparent.
+		im.convertBlocks(parent, n.children)
+		return
+	case "li", "dd", "dt", "tr", "td", "th", "tbody", "thead", "tfoot", "caption":
+		// misplaced elements are treated as containers.
+		im.convertBlocks(parent, n.children)
+		return
+	default:
+		block = im.htmlBlock(ast.HTMLBlockType6, n)
+	}
+	parent.AppendChild(parent, block)
+}
+
+// htmlBlock returns a HTML block that has the HTML of the given node.
+func (im *importer) htmlBlock(typ ast.HTMLBlockType, n *node) ast.Node {
+	block := ast.NewHTMLBlock(typ)
+	value := bytes.TrimRight(im.html[n.start:n.stop], "\r\n")
+	for _, line := range bytes.SplitAfter(value, []byte("\n")) {
+		block.Lines().Append(im.segment(line))
+	}
+	im.source = append(im.source, '\n')
+	lines := block.Lines()
+	last := lines.At(lines.Len() - 1)
+	lines.Set(lines.Len()-1, last.WithStop(last.Stop+1))
+	return block
+}
+
+func (im *importer) convertList(n *node) ast.Node {
+	marker := byte('-')
+	if n.name == "ol" {
+		marker = '.'
+	}
+	list := ast.NewList(marker)
+	if start, ok := n.attr("start"); ok {
+		list.Start, _ = strconv.Atoi(string(start))
+	} else if n.name == "ol" {
+		list.Start = 1
+	}
+	list.IsTight = true
+	for _, c := range n.children {
+		if c.name == "li" && hasParagraph(c) {
+			list.IsTight = false
+		}
+	}
+	for _, c := range n.children {
+		if c.name != "li" {
+			continue
+		}
+		item := ast.NewListItem(2)
+		list.AppendChild(list, item)
+		im.convertBlocks(item, c.children)
+	}
+	return list
+}
+
+func (im *importer) convertDefinitionList(n *node) ast.Node {
+	list := east.NewDefinitionList(0, nil)
+	for _, c := range n.children {
+		switch c.name {
+		case "dt":
+			term := east.NewDefinitionTerm()
+			im.convertInlineBlock(term, c.children)
+			list.AppendChild(list, term)
+		case "dd":
+			description := east.NewDefinitionDescription()
+			description.IsTight = !hasParagraph(c)
+			im.convertBlocks(description, c.children)
+			list.AppendChild(list, description)
+		}
+	}
+	return list
+}
+
+// languageOf returns a language of the given code element from its class
+// like 'language-go'.
+func languageOf(n *node) []byte {
+	class, _ := n.attr("class")
+	for _, name := range strings.Fields(string(class)) {
+		for _, prefix := range []string{"language-", "lang-"} {
+			if strings.HasPrefix(name, prefix) {
+				return []byte(name[len(prefix):])
+			}
+		}
+	}
+	return nil
+}
+
+func (im *importer) convertCodeBlock(n *node) ast.Node {
+	language := languageOf(n)
+	for _, c := range n.children {
+		if c.name == "code" && language == nil {
+			language = languageOf(c)
+		}
+	}
+	var info *ast.Text
+	if language != nil {
+		info = ast.NewTextSegment(im.segment(language))
+	}
+	block := ast.NewFencedCodeBlock(info)
+	value := n.textContent()
+	// a newline after the start tag is ignored.
+	if bytes.HasPrefix(value, []byte("\n")) {
+		value = value[1:]
+	}
+	if len(value) == 0 {
+		return block
+	}
+	if !bytes.HasSuffix(value, []byte("\n")) {
+		value = append(value[:len(value):len(value)], '\n')
+	}
+	for _, line := range bytes.SplitAfter(value[:len(value)-1], []byte("\n")) {
+		segment := im.segment(line)
+		if !bytes.HasSuffix(line, []byte("\n")) {
+			im.source = append(im.source, '\n')
+			segment = segment.WithStop(segment.Stop + 1)
+		}
+		block.Lines().Append(segment)
+	}
+	return block
+}
+
+// alignmentOf returns an alignment of the given table cell.
+func alignmentOf(n *node) east.Alignment {
+	value, ok := n.attr("align")
+	if !ok {
+		style, _ := n.attr("style")
+		for _, declaration := range strings.Split(string(style), ";") {
+			name, v, found := strings.Cut(declaration, ":")
+			if found && strings.TrimSpace(name) == "text-align" {
+				value = []byte(strings.TrimSpace(v))
+			}
+		}
+	}
+	switch strings.ToLower(string(value)) {
+	case "left":
+		return east.AlignLeft
+	case "right":
+		return east.AlignRight
+	case "center":
+		return east.AlignCenter
+	}
+	return east.AlignNone
+}
+
+// tableRows returns rows of the given table and whether the first row is
+// a header row.
+func tableRows(n *node) ([]*node, bool) {
+	var rows []*node
+	header := false
+	for _, c := range n.children {
+		switch c.name {
+		case "tr":
+			rows = append(rows, c)
+		case "thead", "tbody", "tfoot":
+			for _, gc := range c.children {
+				if gc.name == "tr" {
+					header = header || (c.name == "thead" && len(rows) == 0)
+					rows = append(rows, gc)
+				}
+			}
+		}
+	}
+	if len(rows) != 0 && !header {
+		// rows that consist of th elements are headers.
+		header = true
+		for _, c := range rows[0].children {
+			if c.name == "td" {
+				header = false
+			}
+		}
+	}
+	return rows, header
+}
+
+func (im *importer) convertTable(n *node) ast.Node {
+	table := east.NewTable()
+	for _, c := range n.children {
+		if c.name == "caption" {
+			caption := east.NewCaption()
+			im.convertInlineBlock(caption, c.children)
+			table.AppendChild(table, caption)
+		}
+	}
+	rows, header := tableRows(n)
+	for _, row := range rows {
+		columns := 0
+		for _, c := range row.children {
+			if c.name == "td" || c.name == "th" {
+				span, _ := c.attr("colspan")
+				columns += max(atoi(span), 1)
+			}
+		}
+		for len(table.Alignments) < columns {
+			table.Alignments = append(table.Alignments, east.AlignNone)
+		}
+	}
+	if len(rows) != 0 {
+		column := 0
+		for _, c := range rows[0].children {
+			if c.name == "td" || c.name == "th" {
+				table.Alignments[column] = alignmentOf(c)
+				span, _ := c.attr("colspan")
+				column += max(atoi(span), 1)
+			}
+		}
+	}
+	for i, r := range rows {
+		row := east.NewTableRow(table.Alignments)
+		for _, c := range r.children {
+			if c.name != "td" && c.name != "th" {
+				continue
+			}
+			cell := east.NewTableCell()
+			cell.Alignment = alignmentOf(c)
+			colspan, _ := c.attr("colspan")
+			cell.ColSpan = max(atoi(colspan), 1)
+			rowspan, _ := c.attr("rowspan")
+			cell.RowSpan = max(atoi(rowspan), 1)
+			im.convertInlineBlock(cell, c.children)
+			row.AppendChild(row, cell)
+		}
+		if i == 0 && header {
+			table.AppendChild(table, east.NewTableHeader(row))
+		} else {
+			table.AppendChild(table, row)
+		}
+	}
+	return table
+}
+
+func atoi(value []byte) int {
+	i, _ := strconv.Atoi(strings.TrimSpace(string(value)))
+	return i
+}
+
+// convertInlineBlock converts the given nodes into inlines of the block.
+// Whitespaces at the beginning and the end of the block are removed.
+func (im *importer) convertInlineBlock(block ast.Node, nodes []*node) {
+	im.space = true
+	im.convertInlines(block, nodes)
+	// removes a trailing whitespace.
+	last := block.LastChild()
+	for last != nil && last.Type() == ast.TypeInline && last.Kind() != ast.KindText {
+		last = last.LastChild()
+	}
+	if t, ok := last.(*ast.Text); ok && !t.HardLineBreak() {
+		segment := t.Segment
+		if bytes.HasSuffix(segment.Value(im.source), []byte(" ")) {
+			t.Segment = segment.WithStop(segment.Stop - 1)
+		}
+	}
+}
+
+// appendText appends the given text with collapsing whitespaces.
+func (im *importer) appendText(parent ast.Node, value []byte) {
+	var buf bytes.Buffer
+	for _, c := range value {
+		if util.IsSpace(c) {
+			if !im.space {
+				_ = buf.WriteByte(' ')
+			}
+			im.space = true
+			continue
+		}
+		_ = buf.WriteByte(c)
+		im.space = false
+	}
+	if buf.Len() != 0 {
+		parent.AppendChild(parent, ast.NewRawTextSegment(im.segment(buf.Bytes())))
+	}
+}
+
+// convertInlines converts the given nodes into inlines and appends them to
+// the parent.
+func (im *importer) convertInlines(parent ast.Node, nodes []*node) {
+	for _, n := range nodes {
+		im.convertInline(parent, n)
+	}
+}
+
+func (im *importer) convertInline(parent ast.Node, n *node) {
+	if n.typ == textNode {
+		im.appendText(parent, n.text)
+		return
+	}
+	if n.typ == commentNode {
+		im.appendRawHTML(parent, n.start, n.stop)
+		return
+	}
+	var inline ast.Node
+	switch n.name {
+	case "em", "i", "cite", "var":
+		inline = ast.NewEmphasis(1)
+	case "strong", "b":
+		inline = ast.NewEmphasis(2)
+	case "del", "s", "strike":
+		inline = east.NewStrikethrough()
+	case "code", "tt", "samp":
+		codeSpan := ast.NewCodeSpan()
+		value := bytes.TrimSpace(n.textContent())
+		value = bytes.Join(bytes.Fields(value), []byte(" "))
+		codeSpan.AppendChild(codeSpan, ast.NewRawTextSegment(im.segment(value)))
+		parent.AppendChild(parent, codeSpan)
+		im.space = false
+		return
+	case "a":
+		href, ok := n.attr("href")
+		if !ok {
+			im.convertInlines(parent, n.children)
+			return
+		}
+		link := ast.NewLink()
+		link.Destination = href
+		link.Title, _ = n.attr("title")
+		inline = link
+	case "img":
+		link := ast.NewLink()
+		link.Destination, _ = n.attr("src")
+		link.Title, _ = n.attr("title")
+		image := ast.NewImage(link)
+		if alt, ok := n.attr("alt"); ok && len(alt) != 0 {
+			image.AppendChild(image, ast.NewRawTextSegment(im.segment(alt)))
+		}
+		parent.AppendChild(parent, image)
+		im.space = false
+		return
+	case "br":
+		t := ast.NewTextSegment(text.NewSegment(len(im.source), len(im.source)))
+		t.SetHardLineBreak(true)
+		parent.AppendChild(parent, t)
+		im.space = true
+		return
+	case "input":
+		if typ, _ := n.attr("type"); strings.EqualFold(string(typ), "checkbox") {
+			_, checked := n.attr("checked")
+			parent.AppendChild(parent, east.NewTaskCheckBox(checked))
+			im.space = true
+			return
+		}
+		im.appendRawHTML(parent, n.start, n.stop)
+		return
+	case "span", "font", "abbr", "label":
+		im.convertInlines(parent, n.children)
+		return
+	default:
+		if isBlockElement(n.name) {
+			// blocks in inlines are flattened.
+			im.convertInlines(parent, n.children)
+			return
+		}
+		// unknown elements are kept as raw HTML.
+		im.appendRawHTML(parent, n.start, n.tagStop)
+		im.convertInlines(parent, n.children)
+		if n.endStart != 0 {
+			im.appendRawHTML(parent, n.endStart, n.stop)
+		}
+		return
+	}
+	im.convertInlines(inline, n.children)
+	parent.AppendChild(parent, inline)
+}
+
+// appendRawHTML appends the given range of the HTML as a raw HTML.
+func (im *importer) appendRawHTML(parent ast.Node, start, stop int) {
+	if start < 0 || start >= stop {
+		return
+	}
+	raw := ast.NewRawHTML()
+	raw.Segments.Append(im.raw(start, stop))
+	parent.AppendChild(parent, raw)
+	im.space = false
+}
@@ -0,0 +1,142 @@
+package html_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/extension"
+	importer "github.com/yuin/goldmark/importer/html"
+	"github.com/yuin/goldmark/renderer/html"
+)
+
+func TestImport(t *testing.T) {
+	markdown := goldmark.New(
+		goldmark.WithExtensions(extension.GFM, extension.DefinitionList),
+		goldmark.WithRendererOptions(html.WithUnsafe()),
+	)
+	cases := []struct {
+		description string
+		html        string
+		expected    string
+	}{
+		{
+			description: "Headings and inlines",
+			html: `<!DOCTYPE html><html><head><title>T</title></head><body>
+<h2 id="top">Hello &amp; <em>world</em></h2>
+<p>Some <b>bold</b>   and <a href="/a?x=1&amp;y=2" title="T">a
+link</a>, <code>x &lt; y</code><br>
+next <img src="/i.png" alt="pic"> <sup>2</sup>
+<p>second</body></html>`,
+			expected: `<h2 id="top">Hello &amp; <em>world</em></h2>
+<p>Some <strong>bold</strong> and <a href="/a?x=1&amp;y=2" title="T">a link</a>, <code>x &lt; y</code><br>
+next <img src="/i.png" alt="pic"> <sup>2</sup></p>
+<p>second</p>
+`,
+		},
+		{
+			description: "Lists",
+			html: `<ul><li>one<li><input type="checkbox" checked> two
+<ol start="3"><li>three</ol></ul>
+<ol><li><p>loose</p></ol>
+<dl><dt>Term<dd>Description</dl>`,
+			expected: `<ul>
+<li>one</li>
+<li><input checked="" disabled="" type="checkbox"> two
+<ol start="3">
+<li>three</li>
+</ol>
+</li>
+</ul>
+<ol>
+<li>
+<p>loose</p>
+</li>
+</ol>
+<dl>
+<dt>Term</dt>
+<dd>Description</dd>
+</dl>
+`,
+		},
+		{
+			description: "Blocks",
+			html: `<blockquote>quoted</blockquote>
+<pre><code class="language-go">fmt.Println("&lt;x&gt;")
+</code></pre>
+<hr>
+<form>
+<input type="text">
+</form>
+<p><del>gone</del></p>`,
+			expected: `<blockquote>
+<p>quoted</p>
+</blockquote>
+<pre><code class="language-go">fmt.Println(&quot;&lt;x&gt;&quot;)
+</code></pre>
+<hr>
+<form>
+<input type="text">
+</form>
+<p><del>gone</del></p>
+`,
+		},
+		{
+			description: "Tables",
+			html: `<table><caption>Fruits</caption>
+<thead><tr><th align="left">Name<th style="text-align: right">Qty</thead>
+<tbody><tr><td>apple<td>1<tr><td colspan="2">none</tbody></table>`,
+			expected: `<table>
+<caption>Fruits</caption>
+<thead>
+<tr>
+<th style="text-align:left">Name</th>
+<th style="text-align:right">Qty</th>
+</tr>
+</thead>
+<tbody>
+<tr>
+<td>apple</td>
+<td>1</td>
+</tr>
+<tr>
+<td colspan="2">none</td>
+</tr>
+</tbody>
+</table>
+`,
+		},
+		{
+			description: "Raw texts",
+			html:        `<p>a</p><SCRIPT>if (a</b) {}</Script><p>b</p>`,
+			expected: `<p>a</p>
+<SCRIPT>if (a</b) {}</Script>
+<p>b</p>
+`,
+		},
+	}
+	for _, c := range cases {
+		doc, source := importer.Import([]byte(c.html))
+		var buf bytes.Buffer
+		if err := markdown.Renderer().Render(&buf, source, doc); err != nil {
+			t.Fatal(err)
+		}
+		if buf.String() != c.expected {
+			t.Errorf("%s: expected\n%s\nbut got\n%s", c.description, c.expected, buf.String())
+		}
+	}
+}
+
+func TestImportManyRawTextsPerformance(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping performance test in short mode")
+	}
+	source := []byte(strings.Repeat("<script>x</script>", 50000))
+	started := time.Now()
+	_, _ = importer.Import(source)
+	if time.Since(started) > 5*time.Second {
+		t.Error("Importing many raw text elements took too long")
+	}
+}
@@ -0,0 +1,370 @@
+package html
+
+import (
+	"bytes"
+	"strings"
+
+	"github.com/yuin/goldmark/util"
+)
+
+type nodeType int
+
+const (
+	textNode nodeType = iota
+	elementNode
+	commentNode
+)
+
+type attribute struct {
+	name  string
+	value []byte
+}
+
+// A node struct represents a node of the HTML tree.
+type node struct {
+	typ      nodeType
+	name     string
+	attrs    []attribute
+	children []*node
+	parent   *node
+
+	// text is a decoded text of text nodes.
+	text []byte
+
+	// start and stop are the range of the node in the HTML.
+	start, stop int
+
+	// tagStop is the end of the start tag.
+	tagStop int
+
+	// endStart is the start of the end tag, or 0 if the element is
+	// closed without an end tag.
+	endStart int
+}
+
+func (n *node) attr(name string) ([]byte, bool) {
+	for _, a := range n.attrs {
+		if a.name == name {
+			return a.value, true
+		}
+	}
+	return nil, false
+}
+
+// textContent returns texts of the node and its descendants.
+func (n *node) textContent() []byte {
+	if n.typ == textNode {
+		return n.text
+	}
+	var buf bytes.Buffer
+	for _, c := range n.children {
+		if c.typ != commentNode {
+			_, _ = buf.Write(c.textContent())
+		}
+	}
+	return buf.Bytes()
+}
+
+// voidElements are elements that have no contents.
+var voidElements = map[string]bool{
+	"area": true, "base": true, "br": true, "col": true, "embed": true,
+	"hr": true, "img": true, "input": true, "link": true, "meta": true,
+	"source": true, "track": true, "wbr": true,
+}
+
+// rawTextElements are elements whose contents are not parsed.
+var rawTextElements = map[string]bool{
+	"script": true, "style": true, "textarea": true, "title": true,
+}
+
+// An implicitClose struct is a rule for elements that close open elements
+// without end tags like '<li>'.
+type implicitClose struct {
+	// closes are names of the elements that are closed.
+	closes []string
+
+	// scopes are names of the elements that stop searching elements to close.
+	scopes []string
+}
+
+var implicitCloses = map[string]implicitClose{
+	"li":    {[]string{"li"}, []string{"ul", "ol"}},
+	"dt":    {[]string{"dt", "dd"}, []string{"dl"}},
+	"dd":    {[]string{"dt", "dd"}, []string{"dl"}},
+	"tr":    {[]string{"tr", "td", "th"}, []string{"table", "thead", "tbody", "tfoot"}},
+	"td":    {[]string{"td", "th"}, []string{"tr", "table"}},
+	"th":    {[]string{"td", "th"}, []string{"tr", "table"}},
+	"thead": {[]string{"thead", "tbody", "tfoot", "tr", "td", "th"}, []string{"table"}},
+	"tbody": {[]string{"thead", "tbody", "tfoot", "tr", "td", "th"}, []string{"table"}},
+	"tfoot": {[]string{"thead", "tbody", "tfoot", "tr", "td", "th"}, []string{"table"}},
+}
+
+// isBlockElement returns true if the given element is a block element.
+func isBlockElement(name string) bool {
+	switch name {
+	case "address", "article", "aside", "blockquote", "body", "caption", "center",
+		"dd", "details", "dialog", "dir", "div", "dl", "dt", "fieldset", "figcaption",
+		"figure", "footer", "form", "frameset", "h1", "h2", "h3", "h4", "h5", "h6",
+		"head", "header", "hr", "html", "iframe", "legend", "li", "main", "menu",
+		"nav", "noframes", "noscript", "ol", "p", "pre", "section", "script", "style",
+		"summary", "table", "tbody", "td", "textarea", "tfoot", "th", "thead", "title",
+		"tr", "ul":
+		return true
+	}
+	return false
+}
+
+// A parser struct is a lenient HTML parser that builds a tree of nodes.
+type parser struct {
+	src   []byte
+	pos   int
+	stack []*node
+}
+
+func parse(src []byte) *node {
+	root := &node{typ: elementNode, stop: len(src)}
+	p := &parser{src: src, stack: []*node{root}}
+	for p.pos < len(p.src) {
+		p.next()
+	}
+	for len(p.stack) > 1 {
+		p.pop(len(p.src))
+	}
+	return root
+}
+
+func (p *parser) current() *node {
+	return p.stack[len(p.stack)-1]
+}
+
+func (p *parser) append(n *node) {
+	parent := p.current()
+	n.parent = parent
+	parent.children = append(parent.children, n)
+}
+
+func (p *parser) pop(stop int) {
+	n := p.current()
+	n.stop = stop
+	p.stack = p.stack[:len(p.stack)-1]
+}
+
+// popTo pops elements until the element at the given index of the stack.
+func (p *parser) popTo(index, stop int) {
+	for len(p.stack) > index {
+		p.pop(stop)
+	}
+}
+
+// find returns an index of the nearest open element that has one of the
+// given names. Searching stops at elements that have one of the scopes.
+func (p *parser) find(names, scopes []string) int {
+	for i := len(p.stack) - 1; i > 0; i-- {
+		name := p.stack[i].name
+		if contains(names, name) {
+			return i
+		}
+		if contains(scopes, name) {
+			break
+		}
+	}
+	return -1
+}
+
+func contains(names []string, name string) bool {
+	for _, n := range names {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *parser) next() {
+	start := p.pos
+	rest := p.src[p.pos:]
+	switch {
+	case bytes.HasPrefix(rest, []byte("<!--")):
+		end := bytes.Index(rest[4:], []byte("-->"))
+		if end < 0 {
+			p.pos = len(p.src)
+		} else {
+			p.pos += 4 + end + 3
+		}
+		p.append(&node{typ: commentNode, start: start, stop: p.pos})
+	case bytes.HasPrefix(rest, []byte("<!")) || bytes.HasPrefix(rest, []byte("<?")):
+		// doctypes and processing instructions are ignored.
+		end := bytes.IndexByte(rest, '>')
+		if end < 0 {
+			p.pos = len(p.src)
+		} else {
+			p.pos += end + 1
+		}
+	case len(rest) > 2 && rest[0] == '<' && rest[1] == '/' && util.IsAlphaNumeric(rest[2]):
+		p.pos += 2
+		name := p.readName()
+		end := bytes.IndexByte(p.src[p.pos:], '>')
+		if end < 0 {
+			p.pos = len(p.src)
+		} else {
+			p.pos += end + 1
+		}
+		p.endTag(name, start)
+	case len(rest) > 1 && rest[0] == '<' && util.IsAlphaNumeric(rest[1]):
+		p.pos++
+		p.startTag(start)
+	default:
+		end := bytes.IndexByte(rest[1:], '<')
+		if end < 0 {
+			p.pos = len(p.src)
+		} else {
+			p.pos += end + 1
+		}
+		p.appendText(p.src[start:p.pos], start)
+	}
+}
+
+func (p *parser) appendText(value []byte, start int) {
+	value = util.ResolveNumericReferences(util.ResolveEntityNames(value))
+	parent := p.current()
+	if l := len(parent.children); l != 0 && parent.children[l-1].typ == textNode {
+		last := parent.children[l-1]
+		last.text = append(last.text, value...)
+		last.stop = p.pos
+		return
+	}
+	p.append(&node{typ: textNode, text: append([]byte{}, value...), start: start, stop: p.pos})
+}
+
+func isNameChar(c byte) bool {
+	return c != '>' && c != '/' && c != '=' && !util.IsSpace(c)
+}
+
+func (p *parser) readName() string {
+	start := p.pos
+	for p.pos < len(p.src) && isNameChar(p.src[p.pos]) {
+		p.pos++
+	}
+	return strings.ToLower(string(p.src[start:p.pos]))
+}
+
+func (p *parser) skipSpaces() {
+	for p.pos < len(p.src) && util.IsSpace(p.src[p.pos]) {
+		p.pos++
+	}
+}
+
+func (p *parser) readAttributeValue() []byte {
+	if p.pos >= len(p.src) {
+		return nil
+	}
+	var value []byte
+	if q := p.src[p.pos]; q == '"' || q == '\'' {
+		end := bytes.IndexByte(p.src[p.pos+1:], q)
+		if end < 0 {
+			end = len(p.src) - p.pos - 1
+		}
+		value = p.src[p.pos+1 : p.pos+1+end]
+		p.pos = min(p.pos+end+2, len(p.src))
+	} else {
+		start := p.pos
+		for p.pos < len(p.src) && p.src[p.pos] != '>' && !util.IsSpace(p.src[p.pos]) {
+			p.pos++
+		}
+		value = p.src[start:p.pos]
+	}
+	return util.ResolveNumericReferences(util.ResolveEntityNames(value))
+}
+
+func (p *parser) startTag(start int) {
+	n := &node{typ: elementNode, name: p.readName(), start: start}
+	selfClosing := false
+	for p.pos < len(p.src) {
+		p.skipSpaces()
+		if p.pos >= len(p.src) {
+			break
+		}
+		if p.src[p.pos] == '>' {
+			p.pos++
+			break
+		}
+		if p.src[p.pos] == '/' {
+			selfClosing = true
+			p.pos++
+			continue
+		}
+		name := p.readName()
+		if name == "" {
+			p.pos++
+			continue
+		}
+		selfClosing = false
+		p.skipSpaces()
+		var value []byte
+		if p.pos < len(p.src) && p.src[p.pos] == '=' {
+			p.pos++
+			p.skipSpaces()
+			value = p.readAttributeValue()
+		}
+		n.attrs = append(n.attrs, attribute{name: name, value: value})
+	}
+	n.tagStop = p.pos
+	n.stop = p.pos
+
+	// some elements close open elements.
+	if rule, ok := implicitCloses[n.name]; ok {
+		for i := p.find(rule.closes, rule.scopes); i > 0; i = p.find(rule.closes, rule.scopes) {
+			p.popTo(i, start)
+		}
+	}
+	if isBlockElement(n.name) && p.current().name == "p" {
+		p.pop(start)
+	}
+	p.append(n)
+	if voidElements[n.name] || selfClosing {
+		return
+	}
+	if rawTextElements[n.name] {
+		end := indexEndTag(p.src[p.pos:], n.name)
+		if end < 0 {
+			end = len(p.src) - p.pos
+		}
+		if end != 0 {
+			n.children = append(n.children, &node{
+				typ:    textNode,
+				text:   p.src[p.pos : p.pos+end],
+				parent: n,
+				start:  p.pos,
+				stop:   p.pos + end,
+			})
+		}
+		p.pos += end
+	}
+	p.stack = append(p.stack, n)
+}
+
+// indexEndTag returns the index of the first end tag of the given element
+// in the given source, or -1. Tag names are case-insensitive.
+func indexEndTag(src []byte, name string) int {
+	for i := 0; ; {
+		j := bytes.Index(src[i:], []byte("</"))
+		if j < 0 {
+			return -1
+		}
+		i += j
+		if end := i + 2 + len(name); end <= len(src) && bytes.EqualFold(src[i+2:end], []byte(name)) {
+			return i
+		}
+		i += 2
+	}
+}
+
+func (p *parser) endTag(name string, start int) {
+	i := p.find([]string{name}, nil)
+	if i < 0 {
+		return
+	}
+	p.popTo(i+1, start)
+	p.current().endStart = start
+	p.pop(p.pos)
+}
@@ -4,6 +4,7 @@ package goldmark
 import (
 	"io"
 
+	"github.com/yuin/goldmark/ast"
 	"github.com/yuin/goldmark/parser"
 	"github.com/yuin/goldmark/renderer"
 	"github.com/yuin/goldmark/renderer/html"
@@ -32,8 +33,24 @@ func Convert(source []byte, w io.Writer, opts ...parser.ParseOption) error {
 	return defaultMarkdown.Convert(source, w, opts...)
 }
 
+// ConvertStream interprets a UTF-8 Markdown source read from a reader r
+// with the given Markdown object and writes rendered contents to a writer w.
+// Top level blocks are rendered as soon as they are closed, so the whole
+// source is not held in memory. Huge blocks are held as a whole, and link
+// reference definitions and heading IDs are kept until the end of the
+// stream. See parser.ParseStream for details and limitations.
+func ConvertStream(m Markdown, r io.Reader, w io.Writer, opts ...parser.StreamOption) error {
+	return parser.ParseStream(m.Parser(), r, func(doc ast.Node, source []byte) error {
+		return m.Renderer().Render(w, source, doc)
+	}, opts...)
+}
+
 // A Markdown interface offers functions to convert Markdown text to
 // a desired format.
+//
+// Convert of Markdown objects created by New is safe for concurrent use
+// once the objects are configured. See ConvertBatch for converting many
+// sources in parallel.
 type Markdown interface {
 	// Convert interprets a UTF-8 bytes source in Markdown and write rendered
 	// contents to a writer w.
@@ -21,6 +21,9 @@ func (s *autoLinkParser) Trigger() []byte {
 	return []byte{'<'}
 }
 
+// Concurrent implements ConcurrentInlineParser.Concurrent.
+func (s *autoLinkParser) Concurrent() {}
+
 func (s *autoLinkParser) Parse(parent ast.Node, block text.Reader, pc Context) ast.Node {
 	line, segment := block.PeekLine()
 	stop := util.FindEmailIndex(line[1:])
@@ -20,6 +20,9 @@ func (s *codeSpanParser) Trigger() []byte {
 	return []byte{'`'}
 }
 
+// Concurrent implements ConcurrentInlineParser.Concurrent.
+func (s *codeSpanParser) Concurrent() {}
+
 func (s *codeSpanParser) Parse(parent ast.Node, block text.Reader, pc Context) ast.Node {
 	line, startSegment := block.PeekLine()
 	opener := 0
@@ -27,12 +30,13 @@ func (s *codeSpanParser) Parse(parent ast.Node, block text.Reader, pc Context) a
 	}
 	block.Advance(opener)
 	l, pos := block.Position()
-	node := ast.NewCodeSpan()
+	arena := NodeArenaOf(pc)
+	node := arena.NewCodeSpan()
 	for {
 		line, segment := block.PeekLine()
 		if line == nil {
 			block.SetPosition(l, pos)
-			return ast.NewTextSegment(startSegment.WithStop(startSegment.Start + opener))
+			return arena.NewTextSegment(startSegment.WithStop(startSegment.Start + opener))
 		}
 		for i := 0; i < len(line); i++ {
 			c := line[i]
@@ -44,14 +48,14 @@ func (s *codeSpanParser) Parse(parent ast.Node, block text.Reader, pc Context) a
 				if closure == opener && (i >= len(line) || line[i] != '`') {
 					segment = segment.WithStop(segment.Start + i - closure)
 					if !segment.IsEmpty() {
-						node.AppendChild(node, ast.NewRawTextSegment(segment))
+						node.AppendChild(node, arena.NewRawTextSegment(segment))
 					}
 					block.Advance(i)
 					goto end
 				}
 			}
 		}
-		node.AppendChild(node, ast.NewRawTextSegment(segment))
+		node.AppendChild(node, arena.NewRawTextSegment(segment))
 		block.AdvanceLine()
 	}
 end:
@@ -36,6 +36,9 @@ func (s *emphasisParser) Trigger() []byte {
 	return []byte{'*', '_'}
 }
 
+// Concurrent implements ConcurrentInlineParser.Concurrent.
+func (s *emphasisParser) Concurrent() {}
+
 func (s *emphasisParser) Parse(parent ast.Node, block text.Reader, pc Context) ast.Node {
 	before := block.PrecendingCharacter()
 	line, segment := block.PeekLine()
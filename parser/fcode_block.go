@@ -58,11 +58,11 @@ func (b *fencedCodeBlockParser) Open(parent ast.Node, reader text.Reader, pc Con
 			if fenceChar == '`' && bytes.IndexByte(value, '`') > -1 {
 				return nil, NoChildren
 			} else if infoStart != infoStop {
-				info = ast.NewTextSegment(text.NewSegment(infoStart, infoStop))
+				info = NodeArenaOf(pc).NewTextSegment(text.NewSegment(infoStart, infoStop))
 			}
 		}
 	}
-	node := ast.NewFencedCodeBlock(info)
+	node := NodeArenaOf(pc).NewFencedCodeBlock(info)
 	pc.Set(fencedCodeBlockInfoKey, &fenceData{fenceChar, findent, oFenceLength, node})
 	return node, NoChildren
 
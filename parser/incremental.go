@@ -0,0 +1,301 @@
+package parser
+
+import (
+	"bytes"
+	"errors"
+
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/text"
+)
+
+// A LocalASTTransformer interface is implemented by ASTTransformers that
+// transform blocks without referring to other blocks of the document.
+// Reparse re-parses documents incrementally only if all ASTTransformers of
+// the parser implement this interface.
+type LocalASTTransformer interface {
+	ASTTransformer
+
+	// Local is a marker method.
+	Local()
+}
+
+// An Edit struct represents a replacement of a range of a source.
+type Edit struct {
+	// Start and Stop are the range of the replaced bytes in the old source.
+	Start, Stop int
+
+	// Text is a text that replaces the range.
+	Text []byte
+}
+
+// ErrInvalidEdit is returned by Reparse when the range of the edit is not
+// in the source.
+var ErrInvalidEdit = errors.New("parser: edit range is out of the source")
+
+// A chunk struct is a range of a source that has top level blocks.
+type chunk struct {
+	start, stop int
+	nodes       []ast.Node
+}
+
+// Reparse applies the given edit to the source and re-parses only top level
+// blocks affected by the edit. doc must be a document parsed from the
+// source by p, and source must not be modified after parsing.
+//
+// Reparse updates doc in place: affected top level blocks are replaced
+// with new blocks, and segments of the following blocks are shifted to
+// refer to the new source. Reparse returns the updated document, the new
+// source and the top level blocks that are newly parsed. Reparse returns
+// ErrInvalidEdit when the range of the edit is out of the source.
+//
+// Reparse parses the whole new source and returns a new document when
+//
+//   - link reference definitions are added or removed.
+//   - the edit affects blocks after the re-parsed blocks, for example, when
+//     a fenced code block is opened.
+//   - IDs of edited headings are changed and headings follow them.
+//   - p has ASTTransformers that do not implement LocalASTTransformer.
+//
+// opts are used only when the whole new source is parsed.
+func Reparse(p Parser, doc ast.Node, source []byte, edit Edit,
+	opts ...ParseOption) (ast.Node, []byte, []ast.Node, error) {
+	if edit.Start < 0 || edit.Start > edit.Stop || edit.Stop > len(source) {
+		return nil, nil, nil, ErrInvalidEdit
+	}
+	newSource := make([]byte, 0, len(source)-(edit.Stop-edit.Start)+len(edit.Text))
+	newSource = append(newSource, source[:edit.Start]...)
+	newSource = append(newSource, edit.Text...)
+	newSource = append(newSource, source[edit.Stop:]...)
+	delta := len(newSource) - len(source)
+
+	parseAll := func() (ast.Node, []byte, []ast.Node, error) {
+		root := p.Parse(text.NewReader(newSource), opts...)
+		var changed []ast.Node
+		for c := root.FirstChild(); c != nil; c = c.NextSibling() {
+			changed = append(changed, c)
+		}
+		return root, newSource, changed, nil
+	}
+
+	if pp, ok := p.(*parser); !ok || !pp.hasLocalASTTransformers() {
+		return parseAll()
+	}
+	chunks, ok := splitChunks(doc, source)
+	if !ok {
+		return parseAll()
+	}
+
+	// blocks before and after the edited blocks are re-parsed too because
+	// the edit may join or split them.
+	first, last := 0, 0
+	for i, c := range chunks {
+		if c.start <= edit.Start {
+			first = i
+		}
+		if c.start <= edit.Stop {
+			last = i
+		}
+	}
+	from, to := max(first-1, 0), min(last+1, len(chunks)-1)
+	before, edited, after := nodesOf(chunks[:from]), nodesOf(chunks[from:to+1]), nodesOf(chunks[to+1:])
+	if hasLinkReferenceDefinitions(edited) {
+		return parseAll()
+	}
+
+	pc := NewContext()
+	collectReferences(before, pc)
+	collectReferences(after, pc)
+	for _, id := range headingIDs(before) {
+		pc.IDs().Put(id)
+	}
+	start, stop := chunks[from].start, chunks[to].stop+delta
+	reader := text.NewReader(newSource[:stop])
+	reader.SetPosition(-1, text.NewSegment(start, start))
+	reader.AdvanceLine()
+	region := p.Parse(reader, WithContext(pc))
+
+	var changed []ast.Node
+	for c := region.FirstChild(); c != nil; c = c.NextSibling() {
+		changed = append(changed, c)
+	}
+	if hasLinkReferenceDefinitions(changed) {
+		return parseAll()
+	}
+	// IDs of the following headings may depend on IDs of the edited headings.
+	if !equalIDs(headingIDs(edited), headingIDs(changed)) && len(headingIDs(after)) != 0 {
+		return parseAll()
+	}
+	if to != len(chunks)-1 {
+		// blocks after the re-parsed blocks are not affected by the edit
+		// only if the last of the old blocks still starts a block.
+		newChunks, ok := splitChunks(region, newSource)
+		if !ok || newChunks[len(newChunks)-1].start != chunks[to].start+delta {
+			return parseAll()
+		}
+	}
+
+	var next ast.Node
+	if len(after) != 0 {
+		next = after[0]
+	}
+	for _, n := range edited {
+		doc.RemoveChild(doc, n)
+	}
+	for _, n := range changed {
+		if next != nil {
+			doc.InsertBefore(doc, next, n)
+		} else {
+			doc.AppendChild(doc, n)
+		}
+	}
+	for _, n := range after {
+		ast.ShiftSegments(n, delta)
+	}
+	return doc, newSource, changed, nil
+}
+
+func (p *parser) hasLocalASTTransformers() bool {
+	p.init()
+	for _, at := range p.astTransformers {
+		if _, ok := at.(LocalASTTransformer); !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// splitChunks splits top level blocks of the document into chunks at lines
+// that the blocks start at. Blocks that are split from paragraphs, like
+// blocks after link reference definitions and tables that start at the
+// same line as the previous blocks, belong to the previous chunks. Blocks
+// that do not have positions belong to the previous chunks too.
+// splitChunks returns false if positions of the blocks are not in order.
+func splitChunks(doc ast.Node, source []byte) ([]*chunk, bool) {
+	var chunks []*chunk
+	line := -1
+	for c := doc.FirstChild(); c != nil; c = c.NextSibling() {
+		pos := blockPos(c)
+		lineStart := -1
+		if pos > -1 {
+			lineStart = bytes.LastIndexByte(source[:pos], '\n') + 1
+		}
+		if len(chunks) == 0 {
+			chunks = append(chunks, &chunk{start: 0})
+		} else if lineStart > -1 {
+			if lineStart < line {
+				return nil, false
+			}
+			if lineStart > line && c.PreviousSibling().Kind() != ast.KindLinkReferenceDefinition {
+				current := chunks[len(chunks)-1]
+				current.stop = lineStart
+				chunks = append(chunks, &chunk{start: lineStart})
+			}
+		}
+		if lineStart > -1 {
+			line = lineStart
+		}
+		current := chunks[len(chunks)-1]
+		current.nodes = append(current.nodes, c)
+	}
+	if len(chunks) == 0 {
+		chunks = append(chunks, &chunk{start: 0})
+	}
+	chunks[len(chunks)-1].stop = len(source)
+	return chunks, true
+}
+
+// blockPos returns a position of the given block. If the block does not
+// have a position, blockPos returns a position of the first descendant.
+func blockPos(n ast.Node) int {
+	pos := n.Pos()
+	if pos > -1 {
+		return pos
+	}
+	_ = ast.Walk(n, func(c ast.Node, entering bool) (ast.WalkStatus, error) {
+		if entering && c.Pos() > -1 {
+			pos = c.Pos()
+			return ast.WalkStop, nil
+		}
+		return ast.WalkContinue, nil
+	})
+	return pos
+}
+
+func hasLinkReferenceDefinitions(nodes []ast.Node) bool {
+	found := false
+	for _, n := range nodes {
+		_ = ast.Walk(n, func(c ast.Node, entering bool) (ast.WalkStatus, error) {
+			if c.Kind() == ast.KindLinkReferenceDefinition {
+				found = true
+				return ast.WalkStop, nil
+			}
+			if c.Type() == ast.TypeInline {
+				return ast.WalkSkipChildren, nil
+			}
+			return ast.WalkContinue, nil
+		})
+	}
+	return found
+}
+
+// collectReferences adds link references of the given blocks to the context.
+func collectReferences(nodes []ast.Node, pc Context) {
+	for _, n := range nodes {
+		_ = ast.Walk(n, func(c ast.Node, entering bool) (ast.WalkStatus, error) {
+			if !entering {
+				return ast.WalkContinue, nil
+			}
+			if ref, ok := c.(*ast.LinkReferenceDefinition); ok {
+				pc.AddReference(newASTReference(ref))
+			}
+			if c.Type() == ast.TypeInline {
+				return ast.WalkSkipChildren, nil
+			}
+			return ast.WalkContinue, nil
+		})
+	}
+}
+
+// headingIDs returns IDs of headings in the given blocks.
+func headingIDs(nodes []ast.Node) [][]byte {
+	var ids [][]byte
+	for _, n := range nodes {
+		_ = ast.Walk(n, func(c ast.Node, entering bool) (ast.WalkStatus, error) {
+			if !entering {
+				return ast.WalkContinue, nil
+			}
+			if heading, ok := c.(*ast.Heading); ok {
+				if id, ok := heading.AttributeString("id"); ok {
+					if value, ok := id.([]byte); ok {
+						ids = append(ids, value)
+					}
+				}
+			}
+			if c.Type() == ast.TypeInline {
+				return ast.WalkSkipChildren, nil
+			}
+			return ast.WalkContinue, nil
+		})
+	}
+	return ids
+}
+
+func nodesOf(chunks []*chunk) []ast.Node {
+	var nodes []ast.Node
+	for _, c := range chunks {
+		nodes = append(nodes, c.nodes...)
+	}
+	return nodes
+}
+
+func equalIDs(ids1, ids2 [][]byte) bool {
+	if len(ids1) != len(ids2) {
+		return false
+	}
+	for i := range ids1 {
+		if !bytes.Equal(ids1[i], ids2[i]) {
+			return false
+		}
+	}
+	return true
+}
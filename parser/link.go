@@ -119,6 +119,9 @@ func (s *linkParser) Trigger() []byte {
 	return []byte{'!', '[', ']'}
 }
 
+// Concurrent implements ConcurrentInlineParser.Concurrent.
+func (s *linkParser) Concurrent() {}
+
 var linkBottom = NewContextKey()
 
 func (s *linkParser) Parse(parent ast.Node, block text.Reader, pc Context) ast.Node {
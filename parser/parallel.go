@@ -0,0 +1,123 @@
+package parser
+
+import (
+	"sync"
+	"sync/atomic"
+
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/text"
+)
+
+// minParallelInlineSourceSize is a minimum size of sources that inline
+// contents are parsed in parallel.
+const minParallelInlineSourceSize = 64 * 1024
+
+// parallelInlineBatchSize is the number of blocks that a goroutine takes at
+// once.
+const parallelInlineBatchSize = 32
+
+// parseInlinesInParallel parses inline contents of blocks of the given
+// document by goroutines. Blocks that may trigger InlineParsers that do not
+// implement ConcurrentInlineParser are parsed by the current goroutine in the
+// document order. parseInlinesInParallel returns false if the document
+// should be parsed sequentially.
+func (p *parser) parseInlinesInParallel(root ast.Node, source []byte, pc Context) bool {
+	if p.inlineParallelism < 2 || len(source) < minParallelInlineSourceSize ||
+		p.serialCloseBlocker || p.serialTriggers[' '] {
+		return false
+	}
+	ppc, ok := pc.(*parseContext)
+	if !ok {
+		return false
+	}
+	var concurrent, serial []ast.Node
+	collected := map[ast.Node]struct{}{}
+	p.walkBlock(root, func(node ast.Node) {
+		collected[node] = struct{}{}
+		if p.isSerialBlock(node, source) {
+			serial = append(serial, node)
+		} else {
+			concurrent = append(concurrent, node)
+		}
+	})
+
+	// contexts are forked before the current goroutine modifies pc.
+	children := make([]*parseContext, p.inlineParallelism)
+	for i := range children {
+		children[i] = ppc.fork()
+	}
+	var next atomic.Int64
+	var wg sync.WaitGroup
+	for _, child := range children[1:] {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			p.parseConcurrentBlocks(concurrent, &next, source, child)
+		}()
+	}
+	blockReader := text.NewBlockReader(source, nil)
+	for _, node := range serial {
+		p.parseBlock(blockReader, node, pc)
+	}
+	p.parseConcurrentBlocks(concurrent, &next, source, children[0])
+	wg.Wait()
+
+	// InlineParsers that do not implement ConcurrentInlineParser may insert
+	// blocks like inline footnotes. Such blocks are parsed in the document
+	// order as well as the sequential parsing.
+	if len(serial) != 0 {
+		p.walkBlock(root, func(node ast.Node) {
+			if _, ok := collected[node]; !ok && node.Type() != ast.TypeInline {
+				p.parseBlock(blockReader, node, pc)
+			}
+		})
+	}
+	return true
+}
+
+func (p *parser) parseConcurrentBlocks(nodes []ast.Node, next *atomic.Int64, source []byte, pc Context) {
+	blockReader := text.NewBlockReader(source, nil)
+	for {
+		start := int(next.Add(parallelInlineBatchSize)) - parallelInlineBatchSize
+		if start >= len(nodes) {
+			return
+		}
+		for _, node := range nodes[start:min(start+parallelInlineBatchSize, len(nodes))] {
+			p.parseBlock(blockReader, node, pc)
+		}
+	}
+}
+
+// isSerialBlock returns true if the given block has characters that trigger
+// InlineParsers that do not implement ConcurrentInlineParser.
+func (p *parser) isSerialBlock(node ast.Node, source []byte) bool {
+	if node.IsRaw() {
+		return false
+	}
+	lines := node.Lines()
+	for i := 0; i < lines.Len(); i++ {
+		segment := lines.At(i)
+		for _, c := range segment.Value(source) {
+			if p.serialTriggers[c] {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// fork returns a new context that shares references and IDs with this
+// context. Values of this context are shallow-copied.
+func (p *parseContext) fork() *parseContext {
+	c := &parseContext{
+		store:       append([]any(nil), p.store...),
+		ids:         p.ids,
+		refs:        p.refs,
+		blockOffset: -1,
+		blockIndent: -1,
+	}
+	if NodeArenaOf(p) != nil {
+		c.store[nodeArenaKey] = ast.NewNodeArena()
+	}
+	return c
+}
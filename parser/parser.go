@@ -3,6 +3,7 @@ package parser
 
 import (
 	"fmt"
+	"runtime"
 	"strings"
 	"sync"
 
@@ -141,6 +142,20 @@ func (s *ids) Put(value []byte) {
 	s.values[util.BytesToReadOnlyString(value)] = true
 }
 
+// nodeArenaKey is a key of the ast.NodeArena of the context.
+var nodeArenaKey = NewContextKey()
+
+// NodeArenaOf returns an ast.NodeArena of the given context, or nil if
+// WithNodeArena is not specified. Methods of a nil ast.NodeArena allocate
+// nodes individually, so parsers can always allocate nodes by the returned
+// arena.
+func NodeArenaOf(pc Context) *ast.NodeArena {
+	if v, ok := pc.Get(nodeArenaKey).(*ast.NodeArena); ok {
+		return v
+	}
+	return nil
+}
+
 // ContextKey is a key that is used to set arbitrary values to the context.
 type ContextKey int
 
@@ -228,6 +243,15 @@ type Context interface {
 	IsInLinkLabel() bool
 }
 
+// A ContextResetter interface is implemented by Contexts that can be reused
+// for parsing other documents. Contexts created by NewContext implement
+// ContextResetter.
+type ContextResetter interface {
+	// Reset resets this context to be reused for parsing another document.
+	// IDs given by WithIDs are not reset.
+	Reset()
+}
+
 // A ContextConfig struct is a data structure that holds configuration of the Context.
 type ContextConfig struct {
 	IDs IDs
@@ -275,6 +299,21 @@ func NewContext(options ...ContextOption) Context {
 	}
 }
 
+// Reset implements ContextResetter.Reset.
+func (p *parseContext) Reset() {
+	clear(p.store)
+	clear(p.refs)
+	if ids, ok := p.ids.(*ids); ok {
+		clear(ids.values)
+	}
+	p.blockOffset = -1
+	p.blockIndent = -1
+	p.delimiters = nil
+	p.lastDelimiter = nil
+	clear(p.openedBlocks)
+	p.openedBlocks = p.openedBlocks[:0]
+}
+
 func (p *parseContext) Get(key ContextKey) any {
 	return p.store[key]
 }
@@ -456,6 +495,9 @@ type Config struct {
 	ParagraphTransformers util.PrioritizedSlice /*<ParagraphTransformer>*/
 	ASTTransformers       util.PrioritizedSlice /*<ASTTransformer>*/
 	EscapedSpace          bool
+	NodeArena             bool
+	ContextPool           bool
+	InlineParallelism     int
 }
 
 // NewConfig returns a new Config.
@@ -493,6 +535,10 @@ func WithAttribute() Option {
 }
 
 // A Parser interface parses Markdown text into AST nodes.
+//
+// Parsers created by NewParser are initialized by the first call of Parse.
+// Parse is safe for concurrent use, but AddOptions must not be called
+// after the first call of Parse.
 type Parser interface {
 	// Parse parses the given Markdown text into AST nodes.
 	Parse(reader text.Reader, opts ...ParseOption) ast.Node
@@ -576,6 +622,18 @@ type CloseBlocker interface {
 	CloseBlock(parent ast.Node, block text.Reader, pc Context)
 }
 
+// A ConcurrentInlineParser interface is implemented by InlineParsers that
+// can parse blocks concurrently. Parse and CloseBlock of these parsers must
+// not refer to other blocks, and must not modify values of the Context other
+// than values that are set by themselves while parsing the same block.
+// See WithParallelInlineParsing.
+type ConcurrentInlineParser interface {
+	InlineParser
+
+	// Concurrent is a marker method.
+	Concurrent()
+}
+
 // A ParagraphTransformer transforms parsed Paragraph nodes.
 // For example, link references are searched in parsed Paragraphs.
 type ParagraphTransformer interface {
@@ -659,9 +717,17 @@ type parser struct {
 	freeBlockParsers      []BlockParser
 	inlineParsers         [256][]InlineParser
 	closeBlockers         []CloseBlocker
+	serialTriggers        [256]bool
+	serialCloseBlocker    bool
 	paragraphTransformers []ParagraphTransformer
 	astTransformers       []ASTTransformer
 	escapedSpace          bool
+	nodeArena             bool
+	contextPool           *sync.Pool
+	inlineParallelism     int
+	fingerprintConfig     *Config
+	fingerprint           []byte
+	fingerprintSync       sync.Once
 	config                *Config
 	initSync              sync.Once
 }
@@ -734,6 +800,59 @@ func WithEscapedSpace() Option {
 	return &withEscapedSpace{}
 }
 
+type withNodeArena struct {
+}
+
+func (o *withNodeArena) SetParserOption(c *Config) {
+	c.NodeArena = true
+}
+
+// WithNodeArena is a functional option that allocates frequently used nodes
+// in slabs by an ast.NodeArena to reduce allocations.
+// Nodes are not garbage collected until all nodes in the same slab become
+// unreachable, so this option should be used for documents that are
+// discarded as a whole.
+func WithNodeArena() Option {
+	return &withNodeArena{}
+}
+
+type withContextPool struct {
+}
+
+func (o *withContextPool) SetParserOption(c *Config) {
+	c.ContextPool = true
+}
+
+// WithContextPool is a functional option that reuses Contexts that are
+// created by Parse when no Context is given by WithContext.
+// Contexts are reset by Context.Reset after parsing, so ASTTransformers and
+// nodes must not refer to the Contexts after parsing.
+func WithContextPool() Option {
+	return &withContextPool{}
+}
+
+type withParallelInlineParsing struct {
+	value int
+}
+
+func (o *withParallelInlineParsing) SetParserOption(c *Config) {
+	c.InlineParallelism = o.value
+}
+
+// WithParallelInlineParsing is a functional option that parses inline
+// contents of blocks by n goroutines when a source is large.
+// If n is less than 1, runtime.GOMAXPROCS(0) is used.
+//
+// Blocks that may trigger InlineParsers that do not implement
+// ConcurrentInlineParser are parsed sequentially in the document order, so
+// results are same as results of sequential parsing.
+func WithParallelInlineParsing(n int) Option {
+	if n < 1 {
+		n = runtime.GOMAXPROCS(0)
+	}
+	return &withParallelInlineParsing{n}
+}
+
 type withOption struct {
 	name  OptionName
 	value any
@@ -764,6 +883,17 @@ func NewParser(options ...Option) Parser {
 	return p
 }
 
+// Fingerprint implements util.Fingerprinter.Fingerprint.
+// Fingerprint initializes this parser, so options can not be added after
+// calling Fingerprint. The fingerprint is computed at the first call.
+func (p *parser) Fingerprint() []byte {
+	p.init()
+	p.fingerprintSync.Do(func() {
+		p.fingerprint = util.Fingerprint(p.fingerprintConfig)
+	})
+	return p.fingerprint
+}
+
 func (p *parser) AddOptions(opts ...Option) {
 	for _, opt := range opts {
 		opt.SetParserOption(p.config)
@@ -806,10 +936,17 @@ func (p *parser) addInlineParser(v util.PrioritizedValue, options map[OptionName
 			so.SetOption(oname, ovalue)
 		}
 	}
+	_, concurrent := ip.(ConcurrentInlineParser)
 	if cb, ok := ip.(CloseBlocker); ok {
 		p.closeBlockers = append(p.closeBlockers, cb)
+		if !concurrent {
+			p.serialCloseBlocker = true
+		}
 	}
 	for _, tc := range tcs {
+		if !concurrent {
+			p.serialTriggers[tc] = true
+		}
 		if p.inlineParsers[tc] == nil {
 			p.inlineParsers[tc] = []InlineParser{}
 		}
@@ -848,6 +985,9 @@ func (p *parser) addASTTransformer(v util.PrioritizedValue, options map[OptionNa
 // A ParseConfig struct is a data structure that holds configuration of the Parser.Parse.
 type ParseConfig struct {
 	Context Context
+
+	// SkipASTTransformers is true if ASTTransformers are not applied.
+	SkipASTTransformers bool
 }
 
 // A ParseOption is a functional option type for the Parser.Parse.
@@ -861,7 +1001,21 @@ func WithContext(context Context) ParseOption {
 	}
 }
 
-func (p *parser) Parse(reader text.Reader, opts ...ParseOption) ast.Node {
+// WithoutASTTransformers is a functional option that makes Parser.Parse
+// parse only blocks and inlines.
+//
+// This is useful for ASTTransformers that parse parts of a document, like
+// cells of grid tables, with the context of the document given by
+// WithContext: ASTTransformers are applied to the parts together with the
+// rest of the document.
+func WithoutASTTransformers() ParseOption {
+	return func(c *ParseConfig) {
+		c.SkipASTTransformers = true
+	}
+}
+
+// init adds the configured parsers and transformers. init is called only once.
+func (p *parser) init() {
 	p.initSync.Do(func() {
 		p.config.BlockParsers.Sort()
 		for _, v := range p.config.BlockParsers {
@@ -886,25 +1040,58 @@ func (p *parser) Parse(reader text.Reader, opts ...ParseOption) ast.Node {
 			p.addASTTransformer(v, p.config.Options)
 		}
 		p.escapedSpace = p.config.EscapedSpace
+		p.nodeArena = p.config.NodeArena
+		p.inlineParallelism = p.config.InlineParallelism
+		if p.config.ContextPool {
+			p.contextPool = &sync.Pool{
+				New: func() any {
+					return NewContext()
+				},
+			}
+		}
+		p.fingerprintConfig = p.config
 		p.config = nil
 	})
+}
+
+func (p *parser) Parse(reader text.Reader, opts ...ParseOption) ast.Node {
+	p.init()
 	c := &ParseConfig{}
 	for _, opt := range opts {
 		opt(c)
 	}
 	if c.Context == nil {
-		c.Context = NewContext()
+		if p.contextPool != nil {
+			pc := p.contextPool.Get().(Context)
+			defer func() {
+				if r, ok := pc.(ContextResetter); ok {
+					r.Reset()
+					p.contextPool.Put(pc)
+				}
+			}()
+			c.Context = pc
+		} else {
+			c.Context = NewContext()
+		}
 	}
 	pc := c.Context
+	if p.nodeArena && pc.Get(nodeArenaKey) == nil {
+		pc.Set(nodeArenaKey, ast.NewNodeArena())
+		defer pc.Set(nodeArenaKey, nil)
+	}
 	root := ast.NewDocument()
 	p.parseBlocks(root, reader, pc)
 
-	blockReader := text.NewBlockReader(reader.Source(), nil)
-	p.walkBlock(root, func(node ast.Node) {
-		p.parseBlock(blockReader, node, pc)
-	})
-	for _, at := range p.astTransformers {
-		at.Transform(root, reader, pc)
+	if !p.parseInlinesInParallel(root, reader.Source(), pc) {
+		blockReader := text.NewBlockReader(reader.Source(), nil)
+		p.walkBlock(root, func(node ast.Node) {
+			p.parseBlock(blockReader, node, pc)
+		})
+	}
+	if !c.SkipASTTransformers {
+		for _, at := range p.astTransformers {
+			at.Transform(root, reader, pc)
+		}
 	}
 
 	// root.Dump(reader.Source(), 0)
@@ -1162,6 +1349,7 @@ func (p *parser) parseBlock(block text.BlockReader, parent ast.Node, pc Context)
 	}
 	escaped := false
 	source := block.Source()
+	arena := NodeArenaOf(pc)
 	block.Reset(parent.Lines())
 	for {
 	retry:
@@ -1217,7 +1405,7 @@ func (p *parser) parseBlock(block text.BlockReader, parent ast.Node, pc Context)
 					savedLine, savedPosition := block.Position()
 					if i != 0 {
 						_, currentPosition := block.Position()
-						ast.MergeOrAppendTextSegment(parent, startPosition.Between(currentPosition))
+						arena.MergeOrAppendTextSegment(parent, startPosition.Between(currentPosition))
 						_, startPosition = block.Position()
 					}
 					var inlineNode ast.Node
@@ -1262,9 +1450,9 @@ func (p *parser) parseBlock(block text.BlockReader, parent ast.Node, pc Context)
 		diff := startPosition.Between(currentPosition)
 		var text *ast.Text
 		if lineBreakFlags&(lineBreakHard|lineBreakVisible) == lineBreakHard|lineBreakVisible {
-			text = ast.NewTextSegment(diff)
+			text = arena.NewTextSegment(diff)
 		} else {
-			text = ast.NewTextSegment(diff.TrimRightSpace(source))
+			text = arena.NewTextSegment(diff.TrimRightSpace(source))
 		}
 		text.SetSoftLineBreak(lineBreakFlags&lineBreakSoft != 0)
 		text.SetHardLineBreak(lineBreakFlags&lineBreakHard != 0)
@@ -24,6 +24,9 @@ func (s *rawHTMLParser) Trigger() []byte {
 	return []byte{'<'}
 }
 
+// Concurrent implements ConcurrentInlineParser.Concurrent.
+func (s *rawHTMLParser) Concurrent() {}
+
 func (s *rawHTMLParser) Parse(parent ast.Node, block text.Reader, pc Context) ast.Node {
 	line, _ := block.PeekLine()
 	if len(line) > 1 && util.IsAlphaNumeric(line[1]) {
@@ -0,0 +1,204 @@
+package parser
+
+import (
+	"bytes"
+	"errors"
+	"io"
+
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/text"
+)
+
+// minStreamBufferSize is a minimum size of sources that are parsed at once
+// in ParseStream.
+const minStreamBufferSize = 64 * 1024
+
+// ErrNotStreamable is returned by ParseStream when the parser has
+// ASTTransformers that do not implement LocalASTTransformer.
+var ErrNotStreamable = errors.New("parser: ASTTransformers must implement LocalASTTransformer to parse streams")
+
+// ErrNotSeekable is returned by ParseStream when WithReferencePrescan is
+// specified and the reader does not implement io.Seeker.
+var ErrNotSeekable = errors.New("parser: reader must implement io.Seeker to prescan link references")
+
+// ErrBlockTooLarge is returned by ParseStream when the source of the blocks
+// that are not closed yet exceeds the size given by WithMaxBlockSize.
+var ErrBlockTooLarge = errors.New("parser: unclosed blocks exceed the maximum block size")
+
+// A StreamConfig struct is a data structure that holds configuration of
+// ParseStream.
+type StreamConfig struct {
+	// ReferencePrescan is true if link reference definitions are searched
+	// before parsing.
+	ReferencePrescan bool
+
+	// MaxBlockSize is the maximum size of the source of the blocks that are
+	// not closed yet. 0 means no limit.
+	MaxBlockSize int
+}
+
+// A StreamOption is a functional option type for ParseStream.
+type StreamOption func(*StreamConfig)
+
+// WithMaxBlockSize is a functional option that limits the size of the
+// source of the blocks that are not closed yet, like a fenced code block
+// that is never closed. ParseStream returns ErrBlockTooLarge when the
+// blocks exceed the limit.
+func WithMaxBlockSize(n int) StreamOption {
+	return func(c *StreamConfig) {
+		c.MaxBlockSize = n
+	}
+}
+
+// WithReferencePrescan is a functional option that makes ParseStream read
+// the source twice: link reference definitions are collected in the first
+// pass, so links can refer to definitions that appear later in the source.
+// The reader must implement io.Seeker.
+func WithReferencePrescan() StreamOption {
+	return func(c *StreamConfig) {
+		c.ReferencePrescan = true
+	}
+}
+
+// ParseStream parses a source read from the given reader and calls the
+// callback with documents that have top level blocks as soon as the blocks
+// are closed. Sources of the documents are passed to the callback and are
+// valid only until the callback returns, so only the source of the blocks
+// that are not closed yet is held in memory.
+//
+// Memory used by ParseStream is not bounded by the size of the blocks:
+//
+//   - The source of a block grows until the block is closed, so one huge
+//     block, like an unclosed fenced code block, is held in memory as a
+//     whole. WithMaxBlockSize limits the size.
+//   - Link reference definitions and heading IDs are kept until the end of
+//     the stream, because links and IDs of the following blocks depend on
+//     them. They grow with the number of definitions and headings.
+//
+// Unlike Parse, links can refer to only link reference definitions that
+// appear earlier in the source unless WithReferencePrescan is specified.
+// ParseStream returns ErrNotStreamable if p has ASTTransformers that do not
+// implement LocalASTTransformer, like the footnote extension.
+func ParseStream(p Parser, r io.Reader, callback func(doc ast.Node, source []byte) error,
+	opts ...StreamOption) error {
+	c := &StreamConfig{}
+	for _, opt := range opts {
+		opt(c)
+	}
+	if pp, ok := p.(*parser); ok && !pp.hasLocalASTTransformers() {
+		return ErrNotStreamable
+	}
+	var refs []Reference
+	if c.ReferencePrescan {
+		seeker, ok := r.(io.Seeker)
+		if !ok {
+			return ErrNotSeekable
+		}
+		s := &streamParser{parser: p, maxBlockSize: c.MaxBlockSize}
+		if err := s.parse(r, func(ast.Node, []byte) error { return nil }); err != nil {
+			return err
+		}
+		if _, err := seeker.Seek(0, io.SeekStart); err != nil {
+			return err
+		}
+		refs = s.refs
+	}
+	s := &streamParser{parser: p, refs: refs, maxBlockSize: c.MaxBlockSize}
+	return s.parse(r, callback)
+}
+
+// A streamParser struct holds states that are shared between blocks.
+type streamParser struct {
+	parser       Parser
+	maxBlockSize int
+
+	// refs are link references defined in the closed blocks.
+	refs []Reference
+
+	// ids are IDs of headings in the closed blocks.
+	ids [][]byte
+}
+
+func (s *streamParser) parse(r io.Reader, callback func(ast.Node, []byte) error) error {
+	var buf []byte
+	tmp := make([]byte, 32*1024)
+	threshold := minStreamBufferSize
+	for eof := false; !eof; {
+		n, err := r.Read(tmp)
+		buf = append(buf, tmp[:n]...)
+		if err == io.EOF {
+			eof = true
+		} else if err != nil {
+			return err
+		}
+		if !eof && len(buf) < threshold {
+			continue
+		}
+		end := len(buf)
+		if !eof {
+			end = bytes.LastIndexByte(buf, '\n') + 1
+		}
+		doc, stop := s.parseClosedBlocks(buf[:end], eof)
+		if doc != nil && doc.HasChildren() {
+			s.collect(doc)
+			if err := callback(doc, buf[:end]); err != nil {
+				return err
+			}
+		}
+		buf = append(buf[:0], buf[stop:]...)
+		if s.maxBlockSize > 0 && len(buf) > s.maxBlockSize {
+			return ErrBlockTooLarge
+		}
+		// thresholds grow with held sources so that large blocks are not
+		// parsed too many times.
+		threshold = max(minStreamBufferSize, len(buf)*2)
+	}
+	return nil
+}
+
+// parseClosedBlocks parses the given source and returns a document that
+// has closed top level blocks and a position that unclosed blocks start
+// at. The last block is not closed unless eof is true.
+func (s *streamParser) parseClosedBlocks(source []byte, eof bool) (ast.Node, int) {
+	pc := NewContext()
+	for _, ref := range s.refs {
+		pc.AddReference(ref)
+	}
+	for _, id := range s.ids {
+		pc.IDs().Put(id)
+	}
+	doc := s.parser.Parse(text.NewReader(source), WithContext(pc))
+	if eof {
+		return doc, len(source)
+	}
+	chunks, ok := splitChunks(doc, source)
+	if !ok || len(chunks) < 2 {
+		return nil, 0
+	}
+	closed := ast.NewDocument()
+	for _, n := range nodesOf(chunks[:len(chunks)-1]) {
+		closed.AppendChild(closed, n)
+	}
+	return closed, chunks[len(chunks)-1].start
+}
+
+// collect collects link references and heading IDs of the given document.
+// Values are copied because sources of closed blocks are discarded.
+func (s *streamParser) collect(doc ast.Node) {
+	_ = ast.Walk(doc, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		if !entering {
+			return ast.WalkContinue, nil
+		}
+		if ref, ok := n.(*ast.LinkReferenceDefinition); ok {
+			s.refs = append(s.refs, NewReference(
+				bytes.Clone(ref.Label), bytes.Clone(ref.Destination), bytes.Clone(ref.Title)))
+		}
+		if n.Type() == ast.TypeInline {
+			return ast.WalkSkipChildren, nil
+		}
+		return ast.WalkContinue, nil
+	})
+	for _, id := range headingIDs([]ast.Node{doc}) {
+		s.ids = append(s.ids, bytes.Clone(id))
+	}
+}
@@ -0,0 +1,625 @@
+// Package asciidoc implements renderer that outputs AsciiDoc.
+package asciidoc
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/yuin/goldmark/ast"
+	east "github.com/yuin/goldmark/extension/ast"
+	"github.com/yuin/goldmark/renderer"
+	"github.com/yuin/goldmark/renderer/internal/renderutil"
+	"github.com/yuin/goldmark/util"
+)
+
+// A Renderer struct is an implementation of renderer.NodeRenderer that renders
+// nodes as AsciiDoc.
+type Renderer struct {
+	funcs map[ast.NodeKind]renderer.NodeRendererFunc
+}
+
+// NewRenderer returns a new Renderer.
+func NewRenderer() renderer.NodeRenderer {
+	r := &Renderer{}
+	r.funcs = map[ast.NodeKind]renderer.NodeRendererFunc{
+		// blocks
+
+		ast.KindDocument:                renderContents,
+		ast.KindHeading:                 r.renderHeading,
+		ast.KindBlockquote:              r.renderBlockquote,
+		ast.KindCodeBlock:               r.renderCodeBlock,
+		ast.KindFencedCodeBlock:         r.renderCodeBlock,
+		ast.KindHTMLBlock:               r.renderHTMLBlock,
+		ast.KindList:                    r.renderList,
+		ast.KindListItem:                r.renderListItem,
+		ast.KindParagraph:               r.renderParagraph,
+		ast.KindTextBlock:               r.renderParagraph,
+		ast.KindThematicBreak:           r.renderThematicBreak,
+		ast.KindLinkReferenceDefinition: renderNothing,
+
+		// inlines
+
+		ast.KindAutoLink: r.renderAutoLink,
+		ast.KindCodeSpan: r.renderCodeSpan,
+		ast.KindEmphasis: r.renderEmphasis,
+		ast.KindImage:    r.renderImage,
+		ast.KindLink:     r.renderLink,
+		ast.KindRawHTML:  r.renderRawHTML,
+		ast.KindText:     r.renderText,
+		ast.KindString:   r.renderString,
+
+		// extensions
+
+		east.KindTable:                  r.renderTable,
+		east.KindStrikethrough:          r.renderStrikethrough,
+		east.KindTaskCheckBox:           r.renderTaskCheckBox,
+		east.KindDefinitionList:         r.renderList,
+		east.KindDefinitionTerm:         r.renderDefinitionTerm,
+		east.KindDefinitionDescription:  r.renderDefinitionDescription,
+		east.KindFootnoteLink:           r.renderFootnoteLink,
+		east.KindFootnoteList:           renderNothing,
+		east.KindAbbreviation:           renderContents,
+		east.KindAbbreviationDefinition: renderNothing,
+		east.KindFigure:                 r.renderFigure,
+	}
+	return r
+}
+
+// RegisterFuncs implements NodeRenderer.RegisterFuncs .
+func (r *Renderer) RegisterFuncs(reg renderer.NodeRendererFuncRegisterer) {
+	for kind, f := range r.funcs {
+		reg.Register(kind, f)
+	}
+}
+
+func renderNothing(_ util.BufWriter, _ []byte, _ ast.Node, _ bool) (ast.WalkStatus, error) {
+	return ast.WalkSkipChildren, nil
+}
+
+func renderContents(_ util.BufWriter, _ []byte, _ ast.Node, _ bool) (ast.WalkStatus, error) {
+	return ast.WalkContinue, nil
+}
+
+// isListContents returns true if the given node is a child of nodes that
+// are rendered like list items.
+func isListContents(n ast.Node) bool {
+	p := n.Parent()
+	return p != nil && (p.Kind() == ast.KindListItem || p.Kind() == east.KindDefinitionDescription)
+}
+
+// writeBlockSeparator writes a blank line before the given block. Adjacent
+// lists are separated by a comment line so that they are not merged.
+func (r *Renderer) writeBlockSeparator(w util.BufWriter, n ast.Node) {
+	if isListContents(n) {
+		// list contents are joined by writeListContents.
+		return
+	}
+	prev := renderutil.PreviousBlock(n, ast.KindLinkReferenceDefinition, east.KindFootnoteList)
+	if prev != nil {
+		_ = w.WriteByte('\n')
+		// adjacent lists are merged.
+		if (prev.Kind() == ast.KindList && n.Kind() == ast.KindList) ||
+			(prev.Kind() == east.KindDefinitionList && n.Kind() == east.KindDefinitionList) {
+			_, _ = w.WriteString("//-\n\n")
+		}
+	}
+}
+
+// writeListContents writes children of the given list item like node.
+// Blocks except the first block and lists are attached by list continuations.
+func (r *Renderer) writeListContents(w util.BufWriter, source []byte, n ast.Node) error {
+	for c := n.FirstChild(); c != nil; c = c.NextSibling() {
+		contents, err := renderutil.RenderToBytes(source, r.funcs, c)
+		if err != nil {
+			return err
+		}
+		switch {
+		case c.PreviousSibling() == nil && (c.Kind() == ast.KindParagraph || c.Kind() == ast.KindTextBlock):
+		case c.PreviousSibling() == nil:
+			_, _ = w.WriteString("{empty}\n+\n")
+		case c.Kind() == ast.KindList:
+			_ = w.WriteByte('\n')
+		default:
+			_, _ = w.WriteString("\n+\n")
+		}
+		_, _ = w.Write(contents)
+	}
+	_ = w.WriteByte('\n')
+	return nil
+}
+
+// writeID writes a block anchor if the given node has an id attribute.
+func writeID(w util.BufWriter, n ast.Node) {
+	if id, ok := n.AttributeString("id"); ok {
+		if value, ok := id.([]byte); ok {
+			_, _ = fmt.Fprintf(w, "[#%s]\n", value)
+		}
+	}
+}
+
+func (r *Renderer) renderHeading(
+	w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	n := node.(*ast.Heading)
+	if entering {
+		r.writeBlockSeparator(w, n)
+		writeID(w, n)
+		_, _ = w.WriteString(strings.Repeat("=", n.Level))
+		_ = w.WriteByte(' ')
+	} else {
+		_ = w.WriteByte('\n')
+	}
+	return ast.WalkContinue, nil
+}
+
+// delimiter returns a delimiter of the given delimited block.
+// Nested blocks have longer delimiters than their ancestors.
+func delimiter(n ast.Node, c byte) string {
+	depth := 0
+	for p := n.Parent(); p != nil; p = p.Parent() {
+		if p.Kind() == n.Kind() {
+			depth++
+		}
+	}
+	return strings.Repeat(string(c), 4+depth)
+}
+
+func (r *Renderer) renderBlockquote(
+	w util.BufWriter, source []byte, n ast.Node, entering bool) (ast.WalkStatus, error) {
+	if entering {
+		r.writeBlockSeparator(w, n)
+	}
+	_, _ = w.WriteString(delimiter(n, '_'))
+	_ = w.WriteByte('\n')
+	return ast.WalkContinue, nil
+}
+
+// blockLines returns lines of the given block.
+func blockLines(n ast.Node, source []byte) [][]byte {
+	var lines [][]byte
+	l := n.Lines().Len()
+	for i := range l {
+		line := n.Lines().At(i)
+		lines = append(lines, line.Value(source))
+	}
+	if html, ok := n.(*ast.HTMLBlock); ok && html.HasClosure() {
+		lines = append(lines, html.ClosureLine.Value(source))
+	}
+	return lines
+}
+
+// writeLines writes the given lines in a delimited block that
+// is delimited by the given character.
+func writeLines(w util.BufWriter, lines [][]byte, c byte) {
+	// delimiters must be longer than lines that consist of the delimiter character.
+	length := 4
+	for _, line := range lines {
+		value := util.TrimRightSpace(line)
+		if len(value) >= length && len(bytes.Trim(value, string(c))) == 0 {
+			length = len(value) + 1
+		}
+	}
+	delimiter := strings.Repeat(string(c), length)
+	_, _ = w.WriteString(delimiter)
+	_ = w.WriteByte('\n')
+	for _, line := range lines {
+		_, _ = w.Write(line)
+	}
+	_, _ = w.WriteString(delimiter)
+	_ = w.WriteByte('\n')
+}
+
+func (r *Renderer) renderCodeBlock(
+	w util.BufWriter, source []byte, n ast.Node, entering bool) (ast.WalkStatus, error) {
+	if !entering {
+		return ast.WalkContinue, nil
+	}
+	r.writeBlockSeparator(w, n)
+	if fcb, ok := n.(*ast.FencedCodeBlock); ok {
+		if language := fcb.Language(source); language != nil {
+			_, _ = fmt.Fprintf(w, "[source,%s]\n", language)
+		}
+	}
+	writeLines(w, blockLines(n, source), '-')
+	return ast.WalkSkipChildren, nil
+}
+
+func (r *Renderer) renderHTMLBlock(
+	w util.BufWriter, source []byte, n ast.Node, entering bool) (ast.WalkStatus, error) {
+	if entering {
+		r.writeBlockSeparator(w, n)
+		writeLines(w, blockLines(n, source), '+')
+	}
+	return ast.WalkSkipChildren, nil
+}
+
+func (r *Renderer) renderList(w util.BufWriter, source []byte, n ast.Node, entering bool) (ast.WalkStatus, error) {
+	if !entering {
+		return ast.WalkContinue, nil
+	}
+	r.writeBlockSeparator(w, n)
+	if list, ok := n.(*ast.List); ok && list.IsOrdered() && list.Start != 1 {
+		_, _ = fmt.Fprintf(w, "[start=%d]\n", list.Start)
+	}
+	return ast.WalkContinue, nil
+}
+
+// listMarker returns a marker of the given list item like '**'.
+// Markers of nested lists are longer than markers of their ancestors.
+func listMarker(n ast.Node) string {
+	list := n.Parent().(*ast.List)
+	c := "*"
+	if list.IsOrdered() {
+		c = "."
+	}
+	depth := 0
+	for p := ast.Node(list); p != nil; p = p.Parent() {
+		if l, ok := p.(*ast.List); ok && l.IsOrdered() == list.IsOrdered() {
+			depth++
+		}
+	}
+	return strings.Repeat(c, depth)
+}
+
+func (r *Renderer) renderListItem(
+	w util.BufWriter, source []byte, n ast.Node, entering bool) (ast.WalkStatus, error) {
+	if !entering {
+		return ast.WalkContinue, nil
+	}
+	_, _ = w.WriteString(listMarker(n))
+	_ = w.WriteByte(' ')
+	if err := r.writeListContents(w, source, n); err != nil {
+		return ast.WalkStop, err
+	}
+	return ast.WalkSkipChildren, nil
+}
+
+func (r *Renderer) renderParagraph(
+	w util.BufWriter, source []byte, n ast.Node, entering bool) (ast.WalkStatus, error) {
+	if entering {
+		r.writeBlockSeparator(w, n)
+	} else {
+		_ = w.WriteByte('\n')
+	}
+	return ast.WalkContinue, nil
+}
+
+func (r *Renderer) renderThematicBreak(
+	w util.BufWriter, source []byte, n ast.Node, entering bool) (ast.WalkStatus, error) {
+	if entering {
+		r.writeBlockSeparator(w, n)
+		_, _ = w.WriteString("'''\n")
+	}
+	return ast.WalkContinue, nil
+}
+
+func (r *Renderer) renderAutoLink(
+	w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	if entering {
+		// URLs and email addresses are linked automatically.
+		_, _ = w.Write(node.(*ast.AutoLink).Label(source))
+	}
+	return ast.WalkContinue, nil
+}
+
+func (r *Renderer) renderCodeSpan(w util.BufWriter, source []byte, n ast.Node, entering bool) (ast.WalkStatus, error) {
+	if !entering {
+		return ast.WalkContinue, nil
+	}
+	// '+' prevents substitutions in the code.
+	_, _ = w.WriteString("`+")
+	for c := n.FirstChild(); c != nil; c = c.NextSibling() {
+		segment := c.(*ast.Text).Segment
+		value := segment.Value(source)
+		if bytes.HasSuffix(value, []byte("\n")) {
+			_, _ = w.Write(value[:len(value)-1])
+			_ = w.WriteByte(' ')
+		} else {
+			_, _ = w.Write(value)
+		}
+	}
+	_, _ = w.WriteString("+`")
+	return ast.WalkSkipChildren, nil
+}
+
+func (r *Renderer) renderEmphasis(
+	w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	// unconstrained pairs can be used in words.
+	if node.(*ast.Emphasis).Level == 2 {
+		_, _ = w.WriteString("**")
+	} else {
+		_, _ = w.WriteString("__")
+	}
+	return ast.WalkContinue, nil
+}
+
+// writeTarget writes the given target of links and images.
+func writeTarget(w util.BufWriter, url []byte) {
+	for _, c := range url {
+		switch c {
+		case ' ', '[', ']':
+			_, _ = fmt.Fprintf(w, "%%%02X", c)
+		default:
+			_ = w.WriteByte(c)
+		}
+	}
+}
+
+// writeAttributeValue writes the given value as a quoted attribute value.
+func writeAttributeValue(w util.BufWriter, value []byte) {
+	_ = w.WriteByte('"')
+	_, _ = w.Write(bytes.ReplaceAll(value, []byte(`"`), []byte(`\"`)))
+	_ = w.WriteByte('"')
+}
+
+var urlSchemes = [][]byte{
+	[]byte("http://"), []byte("https://"), []byte("ftp://"), []byte("irc://"), []byte("mailto:"),
+}
+
+func (r *Renderer) renderLink(w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	if !entering {
+		return ast.WalkContinue, nil
+	}
+	n := node.(*ast.Link)
+	text, err := renderutil.RenderChildrenToBytes(source, r.funcs, n)
+	if err != nil {
+		return ast.WalkStop, err
+	}
+	text = bytes.ReplaceAll(text, []byte("]"), []byte(`\]`))
+	if len(n.Destination) != 0 && n.Destination[0] == '#' {
+		_, _ = fmt.Fprintf(w, "<<%s,%s>>", n.Destination[1:], text)
+		return ast.WalkSkipChildren, nil
+	}
+	hasScheme := false
+	for _, scheme := range urlSchemes {
+		hasScheme = hasScheme || bytes.HasPrefix(n.Destination, scheme)
+	}
+	if !hasScheme {
+		_, _ = w.WriteString("link:")
+	}
+	writeTarget(w, n.Destination)
+	_ = w.WriteByte('[')
+	_, _ = w.Write(text)
+	_ = w.WriteByte(']')
+	return ast.WalkSkipChildren, nil
+}
+
+// writeImage writes an image macro. If block is true, writeImage writes
+// a block image macro.
+func writeImage(w util.BufWriter, source []byte, n *ast.Image, block bool) {
+	_, _ = w.WriteString("image:")
+	if block {
+		_ = w.WriteByte(':')
+	}
+	writeTarget(w, n.Destination)
+	_ = w.WriteByte('[')
+	writeAttributeValue(w, plainText(n, source))
+	if len(n.Title) != 0 {
+		_, _ = w.WriteString(",title=")
+		writeAttributeValue(w, n.Title)
+	}
+	_ = w.WriteByte(']')
+}
+
+func (r *Renderer) renderImage(w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	if entering {
+		writeImage(w, source, node.(*ast.Image), false)
+	}
+	return ast.WalkSkipChildren, nil
+}
+
+func (r *Renderer) renderRawHTML(
+	w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	if !entering {
+		return ast.WalkContinue, nil
+	}
+	n := node.(*ast.RawHTML)
+	_, _ = w.WriteString("+++")
+	l := n.Segments.Len()
+	for i := range l {
+		segment := n.Segments.At(i)
+		_, _ = w.Write(segment.Value(source))
+	}
+	_, _ = w.WriteString("+++")
+	return ast.WalkSkipChildren, nil
+}
+
+// isLineStart returns true if the given inline node is rendered at the
+// beginning of a line. Contents of list items follow list markers.
+func isLineStart(n ast.Node) bool {
+	return renderutil.IsLineStart(n, true, nil, func(b ast.Node) bool {
+		return !isListContents(b)
+	})
+}
+
+// textValue returns a value of the given node if the node is a text.
+func textValue(n ast.Node, source []byte) []byte {
+	switch typed := n.(type) {
+	case *ast.Text:
+		segment := typed.Segment
+		return segment.Value(source)
+	case *ast.String:
+		return typed.Value
+	}
+	return nil
+}
+
+// neighbors returns the last character of the previous text and the first
+// character of the next text of the given node. Characters that are not
+// adjacent to the node are returned as 0.
+func neighbors(n ast.Node, source []byte) (byte, byte) {
+	var before, after byte
+	if prev := n.PreviousSibling(); prev != nil {
+		t, ok := prev.(*ast.Text)
+		if value := textValue(prev, source); len(value) != 0 && (!ok || !(t.SoftLineBreak() || t.HardLineBreak())) {
+			before = value[len(value)-1]
+		}
+	}
+	if t, ok := n.(*ast.Text); ok && (t.SoftLineBreak() || t.HardLineBreak()) {
+		return before, after
+	}
+	if next := n.NextSibling(); next != nil {
+		if value := textValue(next, source); len(value) != 0 {
+			after = value[0]
+		}
+	}
+	return before, after
+}
+
+func (r *Renderer) renderText(w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	if !entering {
+		return ast.WalkContinue, nil
+	}
+	n := node.(*ast.Text)
+	segment := n.Segment
+	value := segment.Value(source)
+	if !n.IsRaw() {
+		value = renderutil.ResolveText(value)
+	}
+	before, after := neighbors(n, source)
+	writeEscaped(w, value, before, after, isLineStart(n))
+	if n.HardLineBreak() {
+		_, _ = w.WriteString(" +\n")
+	} else if n.SoftLineBreak() {
+		_ = w.WriteByte('\n')
+	}
+	return ast.WalkContinue, nil
+}
+
+func (r *Renderer) renderString(
+	w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	if !entering {
+		return ast.WalkContinue, nil
+	}
+	n := node.(*ast.String)
+	value := n.Value
+	if !n.IsCode() && !n.IsRaw() {
+		value = renderutil.ResolveText(value)
+	}
+	before, after := neighbors(n, source)
+	writeEscaped(w, value, before, after, isLineStart(n))
+	return ast.WalkContinue, nil
+}
+
+// plainText returns a text of the given node without any markups.
+func plainText(n ast.Node, source []byte) []byte {
+	var buf bytes.Buffer
+	_ = ast.Walk(n, func(c ast.Node, entering bool) (ast.WalkStatus, error) {
+		if !entering {
+			return ast.WalkContinue, nil
+		}
+		switch typed := c.(type) {
+		case *ast.Text:
+			segment := typed.Segment
+			_, _ = buf.Write(renderutil.ResolveText(segment.Value(source)))
+			if typed.SoftLineBreak() || typed.HardLineBreak() {
+				_ = buf.WriteByte(' ')
+			}
+		case *ast.String:
+			_, _ = buf.Write(typed.Value)
+		}
+		return ast.WalkContinue, nil
+	})
+	return buf.Bytes()
+}
+
+// replacements are attribute references that are used to escape
+// characters that have special meanings.
+var replacements = [256][]byte{
+	'*': []byte("{asterisk}"),
+	'`': []byte("{backtick}"),
+	'^': []byte("{caret}"),
+	'~': []byte("{tilde}"),
+	'+': []byte("{plus}"),
+}
+
+// lineStartChars are characters that start blocks like lists
+// at the beginning of lines.
+var lineStartChars = [256]bool{
+	'.': true, '=': true, '-': true, '[': true, '/': true, ':': true, '<': true, '|': true, '>': true,
+}
+
+func isWordChar(c byte) bool {
+	return util.IsAlphaNumeric(c) || c >= 0x80
+}
+
+func isSpaceOrNone(c byte) bool {
+	return c == 0 || util.IsSpace(c)
+}
+
+// isOrderedListMarker returns true if the given text starts with a marker of
+// ordered lists like '1998.'. after is a character after the text.
+func isOrderedListMarker(value []byte, after byte) bool {
+	i := 0
+	for i < len(value) && util.IsNumeric(value[i]) {
+		i++
+	}
+	if i == 0 || i == len(value) || value[i] != '.' {
+		return false
+	}
+	if i+1 < len(value) {
+		after = value[i+1]
+	}
+	return isSpaceOrNone(after)
+}
+
+// writeEscaped writes the given text with escaping characters that have
+// special meanings in AsciiDoc. before and after are characters around
+// the text. lineStart is true if the text starts at the beginning of a line.
+func writeEscaped(w util.BufWriter, value []byte, before, after byte, lineStart bool) {
+	if len(value) != 0 && lineStart && (lineStartChars[value[0]] || isOrderedListMarker(value, after)) {
+		_, _ = w.WriteString("{empty}")
+	}
+	for i := 0; i < len(value); i++ {
+		c := value[i]
+		switch c {
+		case '*', '`', '+', '_', '#':
+			prev, next := before, after
+			if i != 0 {
+				prev = value[i-1]
+			}
+			if i != len(value)-1 {
+				next = value[i+1]
+			}
+			// constrained pairs do not start in words.
+			if isWordChar(prev) && isWordChar(next) {
+				break
+			}
+			if v := replacements[c]; v != nil {
+				_, _ = w.Write(v)
+			} else {
+				// backslashes are not removed if characters do not form markups.
+				_, _ = w.WriteString("pass:[")
+				_ = w.WriteByte(c)
+				_ = w.WriteByte(']')
+			}
+			continue
+		case '^', '~':
+			_, _ = w.Write(replacements[c])
+			continue
+		case '-':
+			// '--' between spaces or words is replaced with an em dash.
+			if i+1 >= len(value) || value[i+1] != '-' {
+				break
+			}
+			prev, next := before, after
+			if i != 0 {
+				prev = value[i-1]
+			}
+			if i+2 < len(value) {
+				next = value[i+2]
+			}
+			if (isSpaceOrNone(prev) && isSpaceOrNone(next)) || (isWordChar(prev) && isWordChar(next)) {
+				_, _ = w.WriteString("\\--")
+				i++
+				continue
+			}
+		case '{':
+			// attribute references.
+			if i != len(value)-1 && isWordChar(value[i+1]) {
+				_ = w.WriteByte('\\')
+			}
+		}
+		_ = w.WriteByte(c)
+	}
+}
@@ -0,0 +1,132 @@
+package asciidoc_test
+
+import (
+	"testing"
+
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/extension"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/renderer"
+	"github.com/yuin/goldmark/renderer/asciidoc"
+	"github.com/yuin/goldmark/testutil"
+	"github.com/yuin/goldmark/util"
+)
+
+func TestRenderer(t *testing.T) {
+	markdown := goldmark.New(
+		goldmark.WithRenderer(renderer.NewRenderer(
+			renderer.WithNodeRenderers(util.Prioritized(asciidoc.NewRenderer(), 100)),
+		)),
+		goldmark.WithExtensions(
+			extension.GFM,
+			extension.DefinitionList,
+			extension.Footnote,
+		),
+		goldmark.WithParserOptions(parser.WithAutoHeadingID()),
+	)
+	testutil.DoTestCase(
+		markdown,
+		testutil.MarkdownTestCase{
+			No:          1,
+			Description: "Core nodes and escaping",
+			Markdown: `# Release *1.2.0*
+
+Hello **bold _it_** ` + "`a_b`" + ` [site](http://example.com/a) [docs](docs/x.html) [top](#release-120) ![img](/i.png "T") <http://example.com>
+snake_case _under_score a*b x^2 {attr}  
+. dot
+
+- a
+  1. x
+  2. y
+- b
+
+  second paragraph
+
+> quote
+
+` + "```go\nfmt.Println(\"x\")\n```" + `
+
+***
+`,
+			Expected: `[#release-120]
+= Release __1.2.0__
+
+Hello **bold __it__** ` + "`+a_b+`" + ` http://example.com/a[site] link:docs/x.html[docs] <<release-120,top>> image:/i.png["img",title="T"] http://example.com
+snake_case pass:[_]under_score a*b x{caret}2 \{attr} +
+{empty}. dot
+
+* a
+. x
+. y
+* b
++
+second paragraph
+
+____
+quote
+____
+
+[source,go]
+----
+fmt.Println("x")
+----
+
+'''
+`,
+		},
+		t,
+	)
+
+	testutil.DoTestCase(
+		markdown,
+		testutil.MarkdownTestCase{
+			No:          2,
+			Description: "Extension nodes",
+			Markdown: `- [x] done
+- [ ] ~~todo~~
+
+| Name | Qty |
+|:-|-:|
+| apple | 1 |
+| kiwi | 12[^1] |
+
+Term
+: description
+
+[^1]: the note.
+`,
+			Expected: `* [x] done
+* [ ] [.line-through]##todo##
+
+[cols="<,>",options="header"]
+|===
+|Name |Qty
+
+|apple |1
+|kiwi |12footnote:fn1[the note.]
+|===
+
+Term::
+description
+`,
+		},
+		t,
+	)
+
+	testutil.DoTestCase(
+		markdown,
+		testutil.MarkdownTestCase{
+			No:          3,
+			Description: "Ordered list markers and replacements",
+			Markdown: `1998\. year
+
+a -- b a--b a --b
+`,
+			Expected: `{empty}1998. year
+
+a \-- b a\--b a --b
+`,
+		},
+		t,
+	)
+}
@@ -0,0 +1,234 @@
+package asciidoc
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/yuin/goldmark/ast"
+	east "github.com/yuin/goldmark/extension/ast"
+	"github.com/yuin/goldmark/renderer/internal/renderutil"
+	"github.com/yuin/goldmark/util"
+)
+
+// tableColumnSpec returns a 'cols' attribute of the table.
+func tableColumnSpec(table *east.Table) string {
+	specs := make([]string, 0, len(table.Alignments))
+	for _, alignment := range table.Alignments {
+		switch alignment {
+		case east.AlignRight:
+			specs = append(specs, ">")
+		case east.AlignCenter:
+			specs = append(specs, "^")
+		default:
+			specs = append(specs, "<")
+		}
+	}
+	return strings.Join(specs, ",")
+}
+
+// hasBlockCells returns true if cells of the table contain blocks.
+func hasBlockCells(table ast.Node) bool {
+	for row := table.FirstChild(); row != nil; row = row.NextSibling() {
+		for cell := row.FirstChild(); cell != nil; cell = cell.NextSibling() {
+			if cell.FirstChild() != nil && cell.FirstChild().Type() == ast.TypeBlock {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (r *Renderer) renderTable(
+	w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	if !entering {
+		return ast.WalkContinue, nil
+	}
+	n := node.(*east.Table)
+	r.writeBlockSeparator(w, n)
+	writeID(w, n)
+	first := n.FirstChild()
+	if first != nil && first.Kind() == east.KindCaption {
+		caption, err := renderutil.RenderChildrenToBytes(source, r.funcs, first)
+		if err != nil {
+			return ast.WalkStop, err
+		}
+		_ = w.WriteByte('.')
+		_, _ = w.Write(caption)
+		_ = w.WriteByte('\n')
+		first = first.NextSibling()
+	}
+	_, _ = fmt.Fprintf(w, "[cols=\"%s\"", tableColumnSpec(n))
+	if first != nil && first.Kind() == east.KindTableHeader {
+		_, _ = w.WriteString(`,options="header"`)
+	}
+	_, _ = w.WriteString("]\n|===\n")
+	blocks := hasBlockCells(n)
+	for row := first; row != nil; row = row.NextSibling() {
+		for c := row.FirstChild(); c != nil; c = c.NextSibling() {
+			cell := c.(*east.TableCell)
+			if c.PreviousSibling() != nil {
+				_ = w.WriteByte(' ')
+			}
+			if cell.ColSpan > 1 {
+				_, _ = fmt.Fprintf(w, "%d", cell.ColSpan)
+			}
+			if cell.RowSpan > 1 {
+				_, _ = fmt.Fprintf(w, ".%d", cell.RowSpan)
+			}
+			if cell.ColSpan > 1 || cell.RowSpan > 1 {
+				_ = w.WriteByte('+')
+			}
+			// cells that contain blocks have the AsciiDoc style.
+			if blocks && row.Kind() != east.KindTableHeader {
+				_ = w.WriteByte('a')
+			}
+			_ = w.WriteByte('|')
+			contents, err := renderutil.RenderChildrenToBytes(source, r.funcs, cell)
+			if err != nil {
+				return ast.WalkStop, err
+			}
+			_, _ = w.Write(bytes.ReplaceAll(contents, []byte("|"), []byte(`\|`)))
+		}
+		_ = w.WriteByte('\n')
+		if row.Kind() == east.KindTableHeader {
+			_ = w.WriteByte('\n')
+		}
+	}
+	_, _ = w.WriteString("|===\n")
+	return ast.WalkSkipChildren, nil
+}
+
+func (r *Renderer) renderStrikethrough(
+	w util.BufWriter, source []byte, n ast.Node, entering bool) (ast.WalkStatus, error) {
+	if entering {
+		_, _ = w.WriteString("[.line-through]##")
+	} else {
+		_, _ = w.WriteString("##")
+	}
+	return ast.WalkContinue, nil
+}
+
+// renderTaskCheckBox renders check boxes. Check boxes at the beginning of
+// list items are rendered as checklists.
+func (r *Renderer) renderTaskCheckBox(
+	w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	if !entering {
+		return ast.WalkContinue, nil
+	}
+	if node.(*east.TaskCheckBox).IsChecked {
+		_, _ = w.WriteString("[x] ")
+	} else {
+		_, _ = w.WriteString("[ ] ")
+	}
+	return ast.WalkContinue, nil
+}
+
+func (r *Renderer) renderDefinitionTerm(
+	w util.BufWriter, source []byte, n ast.Node, entering bool) (ast.WalkStatus, error) {
+	if entering {
+		if prev := n.PreviousSibling(); prev != nil && prev.Kind() == east.KindDefinitionDescription {
+			_ = w.WriteByte('\n')
+		}
+	} else {
+		_, _ = w.WriteString("::\n")
+	}
+	return ast.WalkContinue, nil
+}
+
+func (r *Renderer) renderDefinitionDescription(
+	w util.BufWriter, source []byte, n ast.Node, entering bool) (ast.WalkStatus, error) {
+	if !entering {
+		return ast.WalkContinue, nil
+	}
+	if prev := n.PreviousSibling(); prev != nil && prev.Kind() == east.KindDefinitionDescription {
+		_, _ = w.WriteString("+\n")
+	}
+	if err := r.writeListContents(w, source, n); err != nil {
+		return ast.WalkStop, err
+	}
+	return ast.WalkSkipChildren, nil
+}
+
+// findFootnote returns a footnote that has the given index.
+func findFootnote(n ast.Node, index int) *east.Footnote {
+	root := n
+	for root.Parent() != nil {
+		root = root.Parent()
+	}
+	var footnote *east.Footnote
+	_ = ast.Walk(root, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		if f, ok := n.(*east.Footnote); ok && entering {
+			if f.Index == index {
+				footnote = f
+				return ast.WalkStop, nil
+			}
+			return ast.WalkSkipChildren, nil
+		}
+		return ast.WalkContinue, nil
+	})
+	return footnote
+}
+
+// renderFootnoteLink renders footnotes at the place of the first reference.
+// Other references refer to the footnote by its id.
+func (r *Renderer) renderFootnoteLink(
+	w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	if !entering {
+		return ast.WalkContinue, nil
+	}
+	n := node.(*east.FootnoteLink)
+	if n.RefIndex > 0 {
+		_, _ = fmt.Fprintf(w, "footnote:fn%d[]", n.Index)
+		return ast.WalkContinue, nil
+	}
+	footnote := findFootnote(n, n.Index)
+	if footnote == nil {
+		return ast.WalkContinue, nil
+	}
+	var nodes []ast.Node
+	for c := footnote.FirstChild(); c != nil; c = c.NextSibling() {
+		for gc := c.FirstChild(); gc != nil; gc = gc.NextSibling() {
+			if gc.Kind() != east.KindFootnoteBacklink {
+				nodes = append(nodes, gc)
+			}
+		}
+	}
+	contents, err := renderutil.RenderToBytes(source, r.funcs, nodes...)
+	if err != nil {
+		return ast.WalkStop, err
+	}
+	_, _ = fmt.Fprintf(w, "footnote:fn%d[", n.Index)
+	_, _ = w.Write(bytes.ReplaceAll(bytes.TrimSpace(contents), []byte("]"), []byte(`\]`)))
+	_ = w.WriteByte(']')
+	return ast.WalkContinue, nil
+}
+
+// renderFigure renders figures as block images that have titles.
+func (r *Renderer) renderFigure(
+	w util.BufWriter, source []byte, n ast.Node, entering bool) (ast.WalkStatus, error) {
+	if !entering {
+		return ast.WalkContinue, nil
+	}
+	r.writeBlockSeparator(w, n)
+	var image *ast.Image
+	for c := n.FirstChild(); c != nil; c = c.NextSibling() {
+		switch typed := c.(type) {
+		case *ast.Image:
+			image = typed
+		case *east.FigureCaption:
+			caption, err := renderutil.RenderChildrenToBytes(source, r.funcs, typed)
+			if err != nil {
+				return ast.WalkStop, err
+			}
+			_ = w.WriteByte('.')
+			_, _ = w.Write(caption)
+			_ = w.WriteByte('\n')
+		}
+	}
+	if image != nil {
+		writeImage(w, source, image, true)
+		_ = w.WriteByte('\n')
+	}
+	return ast.WalkSkipChildren, nil
+}
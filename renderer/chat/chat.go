@@ -0,0 +1,640 @@
+// Package chat implements renderers that output dialects of Markdown used
+// by chat platforms like Slack, Discord and Telegram.
+package chat
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/yuin/goldmark/ast"
+	east "github.com/yuin/goldmark/extension/ast"
+	"github.com/yuin/goldmark/renderer"
+	"github.com/yuin/goldmark/renderer/internal/renderutil"
+	"github.com/yuin/goldmark/util"
+)
+
+// A Dialect is a dialect of Markdown used by a chat platform.
+type Dialect int
+
+const (
+	// Slack is a dialect called 'mrkdwn' used by Slack.
+	Slack Dialect = iota
+
+	// Discord is a dialect of Markdown used by Discord.
+	Discord
+
+	// Telegram is a dialect called 'MarkdownV2' used by Telegram Bot API.
+	Telegram
+)
+
+var dialectNames = []string{
+	"Slack",
+	"Discord",
+	"Telegram",
+}
+
+func (d Dialect) String() string {
+	return dialectNames[d]
+}
+
+// MaxLength returns the maximum number of characters of a message
+// that the platform accepts.
+func (d Dialect) MaxLength() int {
+	switch d {
+	case Discord:
+		return 2000
+	case Telegram:
+		return 4096
+	}
+	// Slack truncates messages that are longer than 40,000 characters,
+	// but recommends that messages are shorter than 4,000 characters.
+	return 4000
+}
+
+// A Config struct has configurations for the chat renderers.
+type Config struct {
+	Dialect Dialect
+}
+
+// NewConfig returns a new Config with defaults.
+func NewConfig() Config {
+	return Config{
+		Dialect: Slack,
+	}
+}
+
+// SetOption implements renderer.NodeRenderer.SetOption.
+func (c *Config) SetOption(name renderer.OptionName, value any) {
+	switch name {
+	case optDialect:
+		c.Dialect = value.(Dialect)
+	}
+}
+
+// An Option interface sets options for chat renderers.
+type Option interface {
+	SetChatOption(*Config)
+}
+
+// Dialect is an option name used in WithDialect.
+const optDialect renderer.OptionName = "ChatDialect"
+
+type withDialect struct {
+	value Dialect
+}
+
+func (o *withDialect) SetConfig(c *renderer.Config) {
+	c.Options[optDialect] = o.value
+}
+
+func (o *withDialect) SetChatOption(c *Config) {
+	c.Dialect = o.value
+}
+
+// WithDialect is a functional option that sets a dialect of the output.
+func WithDialect(d Dialect) interface {
+	renderer.Option
+	Option
+} {
+	return &withDialect{d}
+}
+
+// A Renderer struct is an implementation of renderer.NodeRenderer that renders
+// nodes as a dialect of Markdown used by chat platforms.
+// Constructs that the dialect does not support are degraded:
+// headings are rendered as bold texts, tables as code blocks
+// and images as links.
+type Renderer struct {
+	Config
+	funcs map[ast.NodeKind]renderer.NodeRendererFunc
+}
+
+// NewRenderer returns a new Renderer with given options.
+func NewRenderer(opts ...Option) renderer.NodeRenderer {
+	r := &Renderer{
+		Config: NewConfig(),
+	}
+
+	for _, opt := range opts {
+		opt.SetChatOption(&r.Config)
+	}
+	r.funcs = map[ast.NodeKind]renderer.NodeRendererFunc{
+		// blocks
+
+		ast.KindDocument:                renderContents,
+		ast.KindHeading:                 r.renderHeading,
+		ast.KindBlockquote:              r.renderBlockquote,
+		ast.KindCodeBlock:               r.renderCodeBlock,
+		ast.KindFencedCodeBlock:         r.renderCodeBlock,
+		ast.KindHTMLBlock:               renderNothing,
+		ast.KindList:                    r.renderList,
+		ast.KindListItem:                r.renderListItem,
+		ast.KindParagraph:               r.renderParagraph,
+		ast.KindTextBlock:               r.renderTextBlock,
+		ast.KindThematicBreak:           r.renderThematicBreak,
+		ast.KindLinkReferenceDefinition: renderNothing,
+
+		// inlines
+
+		ast.KindAutoLink: r.renderAutoLink,
+		ast.KindCodeSpan: r.renderCodeSpan,
+		ast.KindEmphasis: r.renderEmphasis,
+		ast.KindImage:    r.renderLink,
+		ast.KindLink:     r.renderLink,
+		ast.KindRawHTML:  renderNothing,
+		ast.KindText:     r.renderText,
+		ast.KindString:   r.renderString,
+
+		// extensions
+
+		east.KindTable:                  r.renderTable,
+		east.KindStrikethrough:          r.renderStrikethrough,
+		east.KindTaskCheckBox:           r.renderTaskCheckBox,
+		east.KindDefinitionList:         r.renderDefinitionList,
+		east.KindDefinitionTerm:         r.renderDefinitionTerm,
+		east.KindDefinitionDescription:  renderContents,
+		east.KindFootnoteLink:           r.renderFootnoteLink,
+		east.KindFootnoteBacklink:       renderNothing,
+		east.KindFootnote:               r.renderFootnote,
+		east.KindFootnoteList:           r.renderFootnoteList,
+		east.KindAbbreviation:           renderContents,
+		east.KindAbbreviationDefinition: renderNothing,
+		east.KindFigure:                 r.renderFigure,
+		east.KindFigureCaption:          r.renderFigureCaption,
+	}
+	return r
+}
+
+// RegisterFuncs implements NodeRenderer.RegisterFuncs .
+func (r *Renderer) RegisterFuncs(reg renderer.NodeRendererFuncRegisterer) {
+	for kind, f := range r.funcs {
+		reg.Register(kind, f)
+	}
+}
+
+func renderNothing(_ util.BufWriter, _ []byte, _ ast.Node, _ bool) (ast.WalkStatus, error) {
+	return ast.WalkSkipChildren, nil
+}
+
+func renderContents(_ util.BufWriter, _ []byte, _ ast.Node, _ bool) (ast.WalkStatus, error) {
+	return ast.WalkContinue, nil
+}
+
+// writePrefixed writes the given lines with prefixes.
+// The first line is prefixed by first, and other lines are prefixed by rest.
+func writePrefixed(w util.BufWriter, lines []byte, first, rest string) {
+	for i, line := range bytes.Split(lines, []byte("\n")) {
+		prefix := rest
+		if i == 0 {
+			prefix = first
+		}
+		if len(line) == 0 {
+			prefix = strings.TrimRight(prefix, " ")
+		}
+		_, _ = w.WriteString(prefix)
+		_, _ = w.Write(line)
+		_ = w.WriteByte('\n')
+	}
+}
+
+// writeBlockSeparator writes a blank line before the given block unless the
+// block follows a text of a tight list item.
+func (r *Renderer) writeBlockSeparator(w util.BufWriter, n ast.Node) {
+	prev := renderutil.PreviousBlock(n, ast.KindHTMLBlock, ast.KindLinkReferenceDefinition)
+	if prev != nil && prev.Kind() != ast.KindTextBlock {
+		_ = w.WriteByte('\n')
+	}
+}
+
+// A style is a style of inline texts.
+type style int
+
+const (
+	styleItalic style = iota
+	styleBold
+	styleStrikethrough
+)
+
+// marker returns a marker of the given style.
+func (r *Renderer) marker(s style) string {
+	switch r.Dialect {
+	case Discord:
+		return [...]string{"*", "**", "~~"}[s]
+	default:
+		return [...]string{"_", "*", "~"}[s]
+	}
+}
+
+// hasStyle returns true if the given node or its ancestors have the given style.
+func (r *Renderer) hasStyle(n ast.Node, s style) bool {
+	for ; n != nil; n = n.Parent() {
+		switch typed := n.(type) {
+		case *ast.Emphasis:
+			if (typed.Level == 2 && s == styleBold) || (typed.Level != 2 && s == styleItalic) {
+				return true
+			}
+		case *east.Strikethrough:
+			if s == styleStrikethrough {
+				return true
+			}
+		case *ast.Heading:
+			if s == styleBold && (r.Dialect != Discord || typed.Level > 3) {
+				return true
+			}
+		case *east.DefinitionTerm:
+			if s == styleBold {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// writeStyle writes a marker of the given style if ancestors of
+// the given node do not have the style.
+func (r *Renderer) writeStyle(w util.BufWriter, n ast.Node, s style) {
+	if n.Parent() == nil || !r.hasStyle(n.Parent(), s) {
+		_, _ = w.WriteString(r.marker(s))
+	}
+}
+
+func (r *Renderer) renderHeading(
+	w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	n := node.(*ast.Heading)
+	if r.Dialect == Discord && n.Level <= 3 {
+		if entering {
+			r.writeBlockSeparator(w, n)
+			_, _ = w.WriteString(strings.Repeat("#", n.Level))
+			_ = w.WriteByte(' ')
+		} else {
+			_ = w.WriteByte('\n')
+		}
+		return ast.WalkContinue, nil
+	}
+	// headings are not supported.
+	if entering {
+		r.writeBlockSeparator(w, n)
+		_, _ = w.WriteString(r.marker(styleBold))
+	} else {
+		_, _ = w.WriteString(r.marker(styleBold))
+		_ = w.WriteByte('\n')
+	}
+	return ast.WalkContinue, nil
+}
+
+func (r *Renderer) renderBlockquote(
+	w util.BufWriter, source []byte, n ast.Node, entering bool) (ast.WalkStatus, error) {
+	if !entering {
+		return ast.WalkContinue, nil
+	}
+	r.writeBlockSeparator(w, n)
+	contents, err := renderutil.RenderChildrenToBytes(source, r.funcs, n)
+	if err != nil {
+		return ast.WalkStop, err
+	}
+	prefix := "> "
+	if r.Dialect == Telegram {
+		prefix = ">"
+	}
+	writePrefixed(w, contents, prefix, prefix)
+	return ast.WalkSkipChildren, nil
+}
+
+// writeCodeBlock writes the given value as a code block.
+func (r *Renderer) writeCodeBlock(w util.BufWriter, language, value []byte) {
+	_, _ = w.WriteString("```")
+	// Slack renders languages as a part of the code.
+	if r.Dialect != Slack {
+		_, _ = w.Write(language)
+	}
+	_ = w.WriteByte('\n')
+	r.writeCode(w, value)
+	if len(value) != 0 && value[len(value)-1] != '\n' {
+		_ = w.WriteByte('\n')
+	}
+	_, _ = w.WriteString("```\n")
+}
+
+func (r *Renderer) renderCodeBlock(
+	w util.BufWriter, source []byte, n ast.Node, entering bool) (ast.WalkStatus, error) {
+	if !entering {
+		return ast.WalkContinue, nil
+	}
+	r.writeBlockSeparator(w, n)
+	var language []byte
+	if fcb, ok := n.(*ast.FencedCodeBlock); ok {
+		language = fcb.Language(source)
+	}
+	var buf bytes.Buffer
+	l := n.Lines().Len()
+	for i := range l {
+		line := n.Lines().At(i)
+		_, _ = buf.Write(line.Value(source))
+	}
+	r.writeCodeBlock(w, language, buf.Bytes())
+	return ast.WalkSkipChildren, nil
+}
+
+func (r *Renderer) renderList(w util.BufWriter, source []byte, n ast.Node, entering bool) (ast.WalkStatus, error) {
+	if entering {
+		r.writeBlockSeparator(w, n)
+	}
+	return ast.WalkContinue, nil
+}
+
+// listMarker returns a marker of the given list item.
+func (r *Renderer) listMarker(n ast.Node) string {
+	list := n.Parent().(*ast.List)
+	if !list.IsOrdered() {
+		if r.Dialect == Discord {
+			return "- "
+		}
+		// Slack and Telegram do not support lists.
+		return "• "
+	}
+	number := list.Start
+	for c := n.PreviousSibling(); c != nil; c = c.PreviousSibling() {
+		number++
+	}
+	if r.Dialect == Telegram {
+		return fmt.Sprintf("%d\\. ", number)
+	}
+	return fmt.Sprintf("%d. ", number)
+}
+
+// writeItem writes the given contents of an item like list items.
+// Lines except the first line are indented by the width of the marker.
+func writeItem(w util.BufWriter, marker string, contents []byte) {
+	width := len([]rune(strings.ReplaceAll(marker, `\`, "")))
+	writePrefixed(w, contents, marker, strings.Repeat(" ", width))
+}
+
+func (r *Renderer) renderListItem(
+	w util.BufWriter, source []byte, n ast.Node, entering bool) (ast.WalkStatus, error) {
+	if !entering {
+		return ast.WalkContinue, nil
+	}
+	if n.PreviousSibling() != nil && !n.Parent().(*ast.List).IsTight {
+		_ = w.WriteByte('\n')
+	}
+	contents, err := renderutil.RenderChildrenToBytes(source, r.funcs, n)
+	if err != nil {
+		return ast.WalkStop, err
+	}
+	writeItem(w, r.listMarker(n), contents)
+	return ast.WalkSkipChildren, nil
+}
+
+func (r *Renderer) renderParagraph(
+	w util.BufWriter, source []byte, n ast.Node, entering bool) (ast.WalkStatus, error) {
+	if entering {
+		r.writeBlockSeparator(w, n)
+	} else {
+		_ = w.WriteByte('\n')
+	}
+	return ast.WalkContinue, nil
+}
+
+func (r *Renderer) renderTextBlock(
+	w util.BufWriter, source []byte, n ast.Node, entering bool) (ast.WalkStatus, error) {
+	if !entering {
+		_ = w.WriteByte('\n')
+	}
+	return ast.WalkContinue, nil
+}
+
+func (r *Renderer) renderThematicBreak(
+	w util.BufWriter, source []byte, n ast.Node, entering bool) (ast.WalkStatus, error) {
+	if entering {
+		r.writeBlockSeparator(w, n)
+		_, _ = w.WriteString("──────────\n")
+	}
+	return ast.WalkContinue, nil
+}
+
+func (r *Renderer) renderAutoLink(
+	w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	if !entering {
+		return ast.WalkContinue, nil
+	}
+	n := node.(*ast.AutoLink)
+	url := n.URL(source)
+	label := n.Label(source)
+	switch {
+	case r.Dialect == Slack:
+		_ = w.WriteByte('<')
+		r.writeEscaped(w, url, false)
+		if n.AutoLinkType == ast.AutoLinkEmail {
+			_ = w.WriteByte('|')
+			r.writeEscaped(w, label, false)
+		}
+		_ = w.WriteByte('>')
+	case r.Dialect == Discord && n.AutoLinkType == ast.AutoLinkURL:
+		// escaped URLs are not recognized as links.
+		_, _ = w.Write(url)
+	default:
+		r.writeEscaped(w, label, false)
+	}
+	return ast.WalkContinue, nil
+}
+
+func (r *Renderer) renderCodeSpan(w util.BufWriter, source []byte, n ast.Node, entering bool) (ast.WalkStatus, error) {
+	if !entering {
+		return ast.WalkContinue, nil
+	}
+	var buf bytes.Buffer
+	for c := n.FirstChild(); c != nil; c = c.NextSibling() {
+		segment := c.(*ast.Text).Segment
+		value := segment.Value(source)
+		if bytes.HasSuffix(value, []byte("\n")) {
+			_, _ = buf.Write(value[:len(value)-1])
+			_ = buf.WriteByte(' ')
+		} else {
+			_, _ = buf.Write(value)
+		}
+	}
+	open, closing := "`", "`"
+	if r.Dialect == Discord && bytes.IndexByte(buf.Bytes(), '`') > -1 {
+		open, closing = "`` ", " ``"
+	}
+	_, _ = w.WriteString(open)
+	r.writeCode(w, buf.Bytes())
+	_, _ = w.WriteString(closing)
+	return ast.WalkSkipChildren, nil
+}
+
+func (r *Renderer) renderEmphasis(
+	w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	n := node.(*ast.Emphasis)
+	s := styleItalic
+	if n.Level == 2 {
+		s = styleBold
+	}
+	r.writeStyle(w, n, s)
+	return ast.WalkContinue, nil
+}
+
+// renderLink renders links and images. Images are rendered as links.
+func (r *Renderer) renderLink(w util.BufWriter, source []byte, n ast.Node, entering bool) (ast.WalkStatus, error) {
+	var destination []byte
+	switch typed := n.(type) {
+	case *ast.Link:
+		destination = typed.Destination
+	case *ast.Image:
+		destination = typed.Destination
+	}
+	// links to sections of the document do not have meanings.
+	if len(destination) == 0 || destination[0] == '#' {
+		return ast.WalkContinue, nil
+	}
+	if !n.HasChildren() {
+		if entering {
+			if r.Dialect == Slack {
+				_ = w.WriteByte('<')
+				r.writeEscaped(w, destination, false)
+				_ = w.WriteByte('>')
+			} else {
+				r.writeEscaped(w, destination, false)
+			}
+		}
+		return ast.WalkContinue, nil
+	}
+	if r.Dialect == Slack {
+		if entering {
+			_ = w.WriteByte('<')
+			r.writeEscaped(w, destination, false)
+			_ = w.WriteByte('|')
+		} else {
+			_ = w.WriteByte('>')
+		}
+		return ast.WalkContinue, nil
+	}
+	if entering {
+		_ = w.WriteByte('[')
+	} else {
+		_, _ = w.WriteString("](")
+		for _, c := range destination {
+			switch {
+			case c == ')' && r.Dialect == Discord:
+				_, _ = w.WriteString("%29")
+			case (c == ')' || c == '\\') && r.Dialect == Telegram:
+				_ = w.WriteByte('\\')
+				_ = w.WriteByte(c)
+			default:
+				_ = w.WriteByte(c)
+			}
+		}
+		_ = w.WriteByte(')')
+	}
+	return ast.WalkContinue, nil
+}
+
+// isLineStart returns true if the given inline node is rendered at the
+// beginning of a line. Soft line breaks are rendered as spaces.
+func isLineStart(n ast.Node) bool {
+	return renderutil.IsLineStart(n, false, func(p ast.Node) bool {
+		return p.Kind() == ast.KindLink || p.Kind() == ast.KindImage
+	}, nil)
+}
+
+func (r *Renderer) renderText(w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	if !entering {
+		return ast.WalkContinue, nil
+	}
+	n := node.(*ast.Text)
+	segment := n.Segment
+	value := segment.Value(source)
+	if !n.IsRaw() {
+		value = renderutil.ResolveText(value)
+	}
+	r.writeEscaped(w, value, isLineStart(n))
+	if n.HardLineBreak() {
+		_ = w.WriteByte('\n')
+	} else if n.SoftLineBreak() {
+		// chat platforms render newlines as they are.
+		_ = w.WriteByte(' ')
+	}
+	return ast.WalkContinue, nil
+}
+
+func (r *Renderer) renderString(
+	w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	if !entering {
+		return ast.WalkContinue, nil
+	}
+	n := node.(*ast.String)
+	value := n.Value
+	if !n.IsCode() && !n.IsRaw() {
+		value = renderutil.ResolveText(value)
+	}
+	r.writeEscaped(w, value, isLineStart(n))
+	return ast.WalkContinue, nil
+}
+
+var slackEscapes = [256][]byte{
+	'&': []byte("&amp;"),
+	'<': []byte("&lt;"),
+	'>': []byte("&gt;"),
+}
+
+// discordEscapes are characters that have special meanings anywhere.
+// Discord unescapes any backslash escaped punctuation.
+var discordEscapes = [256]bool{
+	'\\': true, '*': true, '_': true, '~': true, '`': true, '|': true, '[': true, ']': true, '<': true,
+}
+
+// discordLineStartEscapes are characters that have special meanings
+// at the beginning of lines.
+var discordLineStartEscapes = [256]bool{
+	'#': true, '>': true, '-': true, '+': true,
+}
+
+// telegramEscapes are characters that must be escaped in MarkdownV2.
+var telegramEscapes = [256]bool{
+	'\\': true, '_': true, '*': true, '[': true, ']': true, '(': true, ')': true, '~': true, '`': true,
+	'>': true, '#': true, '+': true, '-': true, '=': true, '|': true, '{': true, '}': true, '.': true, '!': true,
+}
+
+// writeEscaped writes the given text with escaping characters that have
+// special meanings in the dialect. lineStart is true if the text starts at
+// the beginning of a line.
+func (r *Renderer) writeEscaped(w util.BufWriter, value []byte, lineStart bool) {
+	for i, c := range value {
+		switch r.Dialect {
+		case Slack:
+			if v := slackEscapes[c]; v != nil {
+				_, _ = w.Write(v)
+				continue
+			}
+		case Discord:
+			if discordEscapes[c] || (discordLineStartEscapes[c] && ((i == 0 && lineStart) ||
+				(i != 0 && value[i-1] == '\n'))) {
+				_ = w.WriteByte('\\')
+			}
+		case Telegram:
+			if telegramEscapes[c] {
+				_ = w.WriteByte('\\')
+			}
+		}
+		_ = w.WriteByte(c)
+	}
+}
+
+// writeCode writes the given contents of codes.
+func (r *Renderer) writeCode(w util.BufWriter, value []byte) {
+	for _, c := range value {
+		switch r.Dialect {
+		case Slack:
+			if v := slackEscapes[c]; v != nil {
+				_, _ = w.Write(v)
+				continue
+			}
+		case Telegram:
+			if c == '`' || c == '\\' {
+				_ = w.WriteByte('\\')
+			}
+		}
+		_ = w.WriteByte(c)
+	}
+}
@@ -0,0 +1,206 @@
+package chat_test
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/extension"
+	"github.com/yuin/goldmark/renderer"
+	"github.com/yuin/goldmark/renderer/chat"
+	"github.com/yuin/goldmark/testutil"
+	"github.com/yuin/goldmark/text"
+	"github.com/yuin/goldmark/util"
+)
+
+func newMarkdown(opts ...chat.Option) goldmark.Markdown {
+	return goldmark.New(
+		goldmark.WithRenderer(renderer.NewRenderer(
+			renderer.WithNodeRenderers(util.Prioritized(chat.NewRenderer(opts...), 100)),
+		)),
+		goldmark.WithExtensions(
+			extension.GFM,
+			extension.Footnote,
+		),
+	)
+}
+
+const source = `# Release *1.2.0*
+
+Hello **bold _it_** ~~gone~~ ` + "`a_b`" + ` & [site](http://example.com/a_(b)) ![img](/i.png).
+next line  
+1+1=2!
+
+- a
+- [x] b
+  1. x
+  2. y
+
+> quote
+
+| Name | Qty |
+|:-|-:|
+| apple | 1 |
+| kiwi fruit | 12 |
+`
+
+func TestRenderer(t *testing.T) {
+	testutil.DoTestCase(
+		newMarkdown(chat.WithDialect(chat.Slack)),
+		testutil.MarkdownTestCase{
+			No:          1,
+			Description: "Slack",
+			Markdown:    source,
+			Expected: "*Release _1.2.0_*\n\n" +
+				"Hello *bold _it_* ~gone~ `a_b` &amp; <http://example.com/a_(b)|site> </i.png|img>. next line\n" +
+				"1+1=2!\n\n" +
+				"• a\n" +
+				"• ☑ b\n" +
+				"  1. x\n" +
+				"  2. y\n\n" +
+				"> quote\n\n" +
+				"```\n" +
+				"Name       | Qty\n" +
+				"-----------|----\n" +
+				"apple      |   1\n" +
+				"kiwi fruit |  12\n" +
+				"```\n",
+		},
+		t,
+	)
+
+	testutil.DoTestCase(
+		newMarkdown(chat.WithDialect(chat.Discord)),
+		testutil.MarkdownTestCase{
+			No:          2,
+			Description: "Discord",
+			Markdown:    source,
+			Expected: "# Release *1.2.0*\n\n" +
+				"Hello **bold *it*** ~~gone~~ `a_b` & [site](http://example.com/a_(b%29) [img](/i.png). next line\n" +
+				"1+1=2!\n\n" +
+				"- a\n" +
+				"- ☑ b\n" +
+				"  1. x\n" +
+				"  2. y\n\n" +
+				"> quote\n\n" +
+				"```\n" +
+				"Name       | Qty\n" +
+				"-----------|----\n" +
+				"apple      |   1\n" +
+				"kiwi fruit |  12\n" +
+				"```\n",
+		},
+		t,
+	)
+
+	testutil.DoTestCase(
+		newMarkdown(chat.WithDialect(chat.Telegram)),
+		testutil.MarkdownTestCase{
+			No:          3,
+			Description: "Telegram",
+			Markdown:    source,
+			Expected: "*Release _1\\.2\\.0_*\n\n" +
+				"Hello *bold _it_* ~gone~ `a_b` & [site](http://example.com/a_(b\\)) [img](/i.png)\\. next line\n" +
+				"1\\+1\\=2\\!\n\n" +
+				"• a\n" +
+				"• ☑ b\n" +
+				"  1\\. x\n" +
+				"  2\\. y\n\n" +
+				">quote\n\n" +
+				"```\n" +
+				"Name       | Qty\n" +
+				"-----------|----\n" +
+				"apple      |   1\n" +
+				"kiwi fruit |  12\n" +
+				"```\n",
+		},
+		t,
+	)
+
+	testutil.DoTestCase(
+		newMarkdown(chat.WithDialect(chat.Discord)),
+		testutil.MarkdownTestCase{
+			No:          4,
+			Description: "Discord escaping",
+			Markdown:    "\\# not *heading*, a\\_b \\<@everyone\\> 1\\*2 `` a`b ``\n",
+			Expected:    "\\# not *heading*, a\\_b \\<@everyone> 1\\*2 `` a`b ``\n",
+		},
+		t,
+	)
+}
+
+func TestMessages(t *testing.T) {
+	markdown := newMarkdown(chat.WithDialect(chat.Slack))
+	source := []byte("first paragraph\n\nsecond paragraph\n\n```\n" +
+		strings.Repeat("0123456789\n", 4) + "```\n")
+	doc := markdown.Parser().Parse(text.NewReader(source))
+	messages, err := chat.Messages(markdown.Renderer(), source, doc, chat.Slack, 40)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []string{
+		"first paragraph\n\nsecond paragraph",
+		"```\n0123456789\n0123456789\n0123456789\n```",
+		"```\n0123456789\n```",
+	}
+	if !reflect.DeepEqual(messages, expected) {
+		t.Errorf("expected %q, but got %q", expected, messages)
+	}
+}
+
+func TestMessagesSplitLongLines(t *testing.T) {
+	markdown := newMarkdown(chat.WithDialect(chat.Telegram))
+	source := []byte(strings.Repeat("Version 1.2. *bold text, `code` and _italic words_* ", 20) + "end\\\n")
+	doc := markdown.Parser().Parse(text.NewReader(source))
+	for _, maxLength := range []int{20, 33, 50, 97} {
+		messages, err := chat.Messages(markdown.Renderer(), source, doc, chat.Telegram, maxLength)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, message := range messages {
+			if l := utf8.RuneCountInString(message); l > maxLength {
+				t.Errorf("%q has %d characters, but maxLength is %d", message, l, maxLength)
+			}
+			if err := validateTelegram(message); err != nil {
+				t.Errorf("%q is invalid: %v", message, err)
+			}
+		}
+	}
+}
+
+// validateTelegram returns an error if the given message is not valid
+// MarkdownV2 on its own.
+func validateTelegram(message string) error {
+	var styles []byte
+	for i := 0; i < len(message); i++ {
+		c := message[i]
+		switch {
+		case c == '\\':
+			if i == len(message)-1 {
+				return fmt.Errorf("a backslash at the end")
+			}
+			i++
+		case c == '`':
+			j := strings.IndexByte(message[i+1:], '`')
+			if j < 0 {
+				return fmt.Errorf("an unclosed code span at %d", i)
+			}
+			i += j + 1
+		case c == '*' || c == '_' || c == '~':
+			if n := len(styles); n > 0 && styles[n-1] == c {
+				styles = styles[:n-1]
+			} else {
+				styles = append(styles, c)
+			}
+		case strings.IndexByte("[]()>#+-=|{}.!", c) > -1:
+			return fmt.Errorf("an unescaped %q at %d", c, i)
+		}
+	}
+	if len(styles) != 0 {
+		return fmt.Errorf("unclosed styles %q", styles)
+	}
+	return nil
+}
@@ -0,0 +1,251 @@
+package chat
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/yuin/goldmark/ast"
+	east "github.com/yuin/goldmark/extension/ast"
+	"github.com/yuin/goldmark/renderer/internal/renderutil"
+	"github.com/yuin/goldmark/util"
+)
+
+// plainText returns a text of the given node without any markups.
+func plainText(n ast.Node, source []byte) []byte {
+	var buf bytes.Buffer
+	_ = ast.Walk(n, func(c ast.Node, entering bool) (ast.WalkStatus, error) {
+		if !entering {
+			return ast.WalkContinue, nil
+		}
+		s := ast.SourceOf(c, source)
+		switch typed := c.(type) {
+		case *ast.Text:
+			segment := typed.Segment
+			value := segment.Value(s)
+			if typed.IsRaw() || (c.Parent() != nil && c.Parent().Kind() == ast.KindCodeSpan) {
+				_, _ = buf.Write(value)
+			} else {
+				_, _ = buf.Write(renderutil.ResolveText(value))
+			}
+			if typed.SoftLineBreak() || typed.HardLineBreak() {
+				_ = buf.WriteByte(' ')
+			}
+		case *ast.String:
+			if typed.IsCode() || typed.IsRaw() {
+				_, _ = buf.Write(typed.Value)
+			} else {
+				_, _ = buf.Write(renderutil.ResolveText(typed.Value))
+			}
+		case *ast.AutoLink:
+			_, _ = buf.Write(typed.Label(s))
+		case *east.TaskCheckBox:
+			_, _ = buf.WriteString(taskCheckBox(typed))
+		case *east.FootnoteLink:
+			_, _ = fmt.Fprintf(&buf, "[%d]", typed.Index)
+		case *ast.RawHTML, *east.FootnoteBacklink:
+			return ast.WalkSkipChildren, nil
+		default:
+			if c.Type() == ast.TypeBlock && c != n && c.PreviousSibling() != nil {
+				_ = buf.WriteByte(' ')
+			}
+		}
+		return ast.WalkContinue, nil
+	})
+	return bytes.TrimSpace(buf.Bytes())
+}
+
+// tableGrid returns plain texts of the cells in the table.
+// Columns that are spanned by cells are empty.
+func tableGrid(table *east.Table, source []byte) [][]string {
+	var grid [][]string
+	remaining := make([]int, len(table.Alignments))
+	for r := table.FirstChild(); r != nil; r = r.NextSibling() {
+		if r.Kind() != east.KindTableRow && r.Kind() != east.KindTableHeader {
+			continue
+		}
+		row := make([]string, len(remaining))
+		col := 0
+		for c := r.FirstChild(); c != nil; c = c.NextSibling() {
+			for col < len(remaining) && remaining[col] > 0 {
+				col++
+			}
+			if col >= len(remaining) {
+				break
+			}
+			cell := c.(*east.TableCell)
+			row[col] = string(plainText(cell, source))
+			for k := col; k < col+cell.ColSpan && k < len(remaining); k++ {
+				remaining[k] = cell.RowSpan
+			}
+			col += cell.ColSpan
+		}
+		for i := range remaining {
+			if remaining[i] > 0 {
+				remaining[i]--
+			}
+		}
+		grid = append(grid, row)
+	}
+	return grid
+}
+
+// pad returns the given value padded to the given width.
+func pad(value string, width int, alignment east.Alignment) string {
+	n := width - utf8.RuneCountInString(value)
+	switch alignment {
+	case east.AlignRight:
+		return strings.Repeat(" ", n) + value
+	case east.AlignCenter:
+		return strings.Repeat(" ", n/2) + value + strings.Repeat(" ", n-n/2)
+	}
+	return value + strings.Repeat(" ", n)
+}
+
+// renderTable renders tables as code blocks because chat platforms
+// do not support tables.
+func (r *Renderer) renderTable(
+	w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	if !entering {
+		return ast.WalkContinue, nil
+	}
+	n := node.(*east.Table)
+	r.writeBlockSeparator(w, n)
+	if caption := n.FirstChild(); caption != nil && caption.Kind() == east.KindCaption {
+		contents, err := renderutil.RenderChildrenToBytes(source, r.funcs, caption)
+		if err != nil {
+			return ast.WalkStop, err
+		}
+		_, _ = w.Write(contents)
+		_ = w.WriteByte('\n')
+	}
+	grid := tableGrid(n, source)
+	widths := make([]int, len(n.Alignments))
+	for _, row := range grid {
+		for i, cell := range row {
+			widths[i] = max(widths[i], utf8.RuneCountInString(cell))
+		}
+	}
+	var buf bytes.Buffer
+	for i, row := range grid {
+		for j, cell := range row {
+			if j != 0 {
+				_, _ = buf.WriteString(" | ")
+			}
+			_, _ = buf.WriteString(pad(cell, widths[j], n.Alignments[j]))
+		}
+		buf.Truncate(len(bytes.TrimRight(buf.Bytes(), " ")))
+		_ = buf.WriteByte('\n')
+		if i == 0 && n.FirstChild() != nil {
+			for j, width := range widths {
+				if j != 0 {
+					_, _ = buf.WriteString("-|-")
+				}
+				_, _ = buf.WriteString(strings.Repeat("-", width))
+			}
+			_ = buf.WriteByte('\n')
+		}
+	}
+	r.writeCodeBlock(w, nil, buf.Bytes())
+	return ast.WalkSkipChildren, nil
+}
+
+func (r *Renderer) renderStrikethrough(
+	w util.BufWriter, source []byte, n ast.Node, entering bool) (ast.WalkStatus, error) {
+	r.writeStyle(w, n, styleStrikethrough)
+	return ast.WalkContinue, nil
+}
+
+func taskCheckBox(n *east.TaskCheckBox) string {
+	if n.IsChecked {
+		return "☑ "
+	}
+	return "☐ "
+}
+
+func (r *Renderer) renderTaskCheckBox(
+	w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	if entering {
+		_, _ = w.WriteString(taskCheckBox(node.(*east.TaskCheckBox)))
+	}
+	return ast.WalkContinue, nil
+}
+
+func (r *Renderer) renderDefinitionList(
+	w util.BufWriter, source []byte, n ast.Node, entering bool) (ast.WalkStatus, error) {
+	if entering {
+		r.writeBlockSeparator(w, n)
+	}
+	return ast.WalkContinue, nil
+}
+
+// renderDefinitionTerm renders terms as bold texts.
+func (r *Renderer) renderDefinitionTerm(
+	w util.BufWriter, source []byte, n ast.Node, entering bool) (ast.WalkStatus, error) {
+	if entering {
+		r.writeBlockSeparator(w, n)
+		_, _ = w.WriteString(r.marker(styleBold))
+	} else {
+		_, _ = w.WriteString(r.marker(styleBold))
+		_ = w.WriteByte('\n')
+	}
+	return ast.WalkContinue, nil
+}
+
+func (r *Renderer) writeFootnoteMarker(w util.BufWriter, index int) {
+	r.writeEscaped(w, []byte(fmt.Sprintf("[%d]", index)), false)
+}
+
+func (r *Renderer) renderFootnoteLink(
+	w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	if entering {
+		r.writeFootnoteMarker(w, node.(*east.FootnoteLink).Index)
+	}
+	return ast.WalkContinue, nil
+}
+
+func (r *Renderer) renderFootnoteList(
+	w util.BufWriter, source []byte, n ast.Node, entering bool) (ast.WalkStatus, error) {
+	if entering {
+		r.writeBlockSeparator(w, n)
+	}
+	return ast.WalkContinue, nil
+}
+
+func (r *Renderer) renderFootnote(
+	w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	if !entering {
+		return ast.WalkContinue, nil
+	}
+	n := node.(*east.Footnote)
+	contents, err := renderutil.RenderChildrenToBytes(source, r.funcs, n)
+	if err != nil {
+		return ast.WalkStop, err
+	}
+	var marker bytes.Buffer
+	mw := bufio.NewWriter(&marker)
+	r.writeFootnoteMarker(mw, n.Index)
+	_ = mw.Flush()
+	writeItem(w, marker.String()+" ", contents)
+	return ast.WalkSkipChildren, nil
+}
+
+func (r *Renderer) renderFigure(
+	w util.BufWriter, source []byte, n ast.Node, entering bool) (ast.WalkStatus, error) {
+	if entering {
+		r.writeBlockSeparator(w, n)
+	} else {
+		_ = w.WriteByte('\n')
+	}
+	return ast.WalkContinue, nil
+}
+
+func (r *Renderer) renderFigureCaption(
+	w util.BufWriter, source []byte, n ast.Node, entering bool) (ast.WalkStatus, error) {
+	if entering {
+		_ = w.WriteByte('\n')
+	}
+	return ast.WalkContinue, nil
+}
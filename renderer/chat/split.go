@@ -0,0 +1,325 @@
+package chat
+
+import (
+	"bytes"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/renderer"
+	"github.com/yuin/goldmark/util"
+)
+
+// Messages renders the given document with the given renderer and splits
+// the output into messages that have at most maxLength characters, like
+// Dialect.MaxLength. The renderer must render the given dialect.
+//
+// Messages are split at boundaries of the top-level blocks. Blocks longer
+// than maxLength are split at line boundaries, and code blocks are split
+// into multiple code blocks. Lines longer than maxLength are split at spaces
+// outside escapes, code spans and links, and styles that span the split
+// are closed and reopened so that each message is valid on its own.
+func Messages(r renderer.Renderer, source []byte, doc ast.Node, d Dialect, maxLength int) ([]string, error) {
+	var messages []string
+	current := ""
+	for c := doc.FirstChild(); c != nil; c = c.NextSibling() {
+		var buf bytes.Buffer
+		if err := r.Render(&buf, source, c); err != nil {
+			return nil, err
+		}
+		block := strings.Trim(buf.String(), "\n")
+		if len(block) == 0 {
+			continue
+		}
+		for _, piece := range splitBlock(block, d, maxLength) {
+			switch {
+			case len(current) == 0:
+				current = piece
+			case utf8.RuneCountInString(current)+2+utf8.RuneCountInString(piece) <= maxLength:
+				current += "\n\n" + piece
+			default:
+				messages = append(messages, current)
+				current = piece
+			}
+		}
+	}
+	if len(current) != 0 {
+		messages = append(messages, current)
+	}
+	return messages, nil
+}
+
+// splitBlock splits the given rendered block into pieces that have
+// at most maxLength characters.
+func splitBlock(block string, d Dialect, maxLength int) []string {
+	if utf8.RuneCountInString(block) <= maxLength {
+		return []string{block}
+	}
+	lines := strings.Split(block, "\n")
+	if len(lines) > 2 && strings.HasPrefix(lines[0], "```") && lines[len(lines)-1] == "```" {
+		open := lines[0]
+		// a newline after the opening fence and a closing fence.
+		length := maxLength - utf8.RuneCountInString(open) - 4
+		if length > 0 {
+			pieces := splitLines(lines[1:len(lines)-1], d, true, length)
+			for i, piece := range pieces {
+				pieces[i] = open + "\n" + piece + "\n```"
+			}
+			return pieces
+		}
+	}
+	return splitLines(lines, d, false, maxLength)
+}
+
+// splitLines joins the given lines into pieces that have at most
+// maxLength characters. Lines longer than maxLength are split by splitLine.
+// code is true if the lines are contents of a code block.
+func splitLines(lines []string, d Dialect, code bool, maxLength int) []string {
+	var pieces []string
+	current := ""
+	hasCurrent := false
+	for _, line := range lines {
+		if utf8.RuneCountInString(line) > maxLength {
+			if hasCurrent {
+				pieces = append(pieces, current)
+				current, hasCurrent = "", false
+			}
+			linePieces := splitLine(tokenize(line, d, code), d, maxLength)
+			if len(linePieces) == 0 {
+				// the line has only spaces.
+				continue
+			}
+			pieces = append(pieces, linePieces[:len(linePieces)-1]...)
+			line = linePieces[len(linePieces)-1]
+		}
+		switch {
+		case !hasCurrent:
+			current, hasCurrent = line, true
+		case utf8.RuneCountInString(current)+1+utf8.RuneCountInString(line) <= maxLength:
+			current += "\n" + line
+		default:
+			pieces = append(pieces, current)
+			current = line
+		}
+	}
+	if hasCurrent {
+		pieces = append(pieces, current)
+	}
+	return pieces
+}
+
+// A token is a part of a rendered line that can not be split.
+type token struct {
+	value  string
+	length int
+
+	// marker is true if the token opens or closes a style.
+	marker bool
+
+	// space is true if the line can be split at the token.
+	space bool
+}
+
+func newToken(value string) token {
+	return token{value: value, length: utf8.RuneCountInString(value)}
+}
+
+// tokenize splits the given rendered line into tokens. Escapes, entities,
+// code spans and links are single tokens.
+func tokenize(line string, d Dialect, code bool) []token {
+	var tokens []token
+	for i := 0; i < len(line); {
+		n := tokenLength(line[i:], d, code)
+		t := newToken(line[i : i+n])
+		switch {
+		case code:
+		case t.value == " ":
+			t.space = true
+		case d != Slack && isMarker(t.value):
+			t.marker = true
+		case d == Slack && isMarker(t.value):
+			t.marker = isSlackMarker(line, i)
+		}
+		tokens = append(tokens, t)
+		i += n
+	}
+	return tokens
+}
+
+// tokenLength returns the length in bytes of the token at the beginning of
+// the given text.
+func tokenLength(s string, d Dialect, code bool) int {
+	_, n := utf8.DecodeRuneInString(s)
+	switch {
+	case d != Slack && s[0] == '\\' && len(s) > 1 && (!code || d == Telegram):
+		_, l := utf8.DecodeRuneInString(s[1:])
+		return 1 + l
+	case code:
+	case d == Slack && (s[0] == '&' || s[0] == '<'):
+		closing := byte(';')
+		if s[0] == '<' {
+			closing = '>'
+		}
+		if i := strings.IndexByte(s, closing); i > -1 {
+			return i + 1
+		}
+	case s[0] == '`':
+		if d == Discord && strings.HasPrefix(s, "`` ") {
+			if i := strings.Index(s[3:], " ``"); i > -1 {
+				return i + 6
+			}
+		}
+		if i := indexUnescaped(s[1:], '`', d); i > -1 {
+			return i + 2
+		}
+	case d != Slack && s[0] == '[':
+		if i := indexUnescaped(s, ']', d); i > -1 && strings.HasPrefix(s[i+1:], "(") {
+			if j := indexUnescaped(s[i+2:], ')', d); j > -1 {
+				return i + j + 3
+			}
+		}
+	case d == Discord && (strings.HasPrefix(s, "**") || strings.HasPrefix(s, "~~")):
+		return 2
+	}
+	return n
+}
+
+// indexUnescaped returns the index of the first c that is not escaped
+// in the given text, or -1.
+func indexUnescaped(s string, c byte, d Dialect) int {
+	for i := 0; i < len(s); i++ {
+		switch {
+		case s[i] == '\\' && d == Telegram:
+			i++
+		case s[i] == c:
+			return i
+		}
+	}
+	return -1
+}
+
+// isSlackMarker returns true if the character at the given index of the
+// given line is a style marker. Slack does not escape markers, but markers
+// have meanings only at boundaries of words.
+func isSlackMarker(line string, i int) bool {
+	boundary := func(c byte) bool {
+		return c == ' ' || (util.IsPunct(c) && c != line[i])
+	}
+	before := i == 0 || boundary(line[i-1])
+	after := i == len(line)-1 || boundary(line[i+1])
+	return before != after
+}
+
+func isMarker(s string) bool {
+	switch s {
+	case "*", "_", "~", "**", "~~":
+		return true
+	}
+	return false
+}
+
+// splitLine joins the given tokens of a line into pieces that have at most
+// maxLength characters. Pieces are split at spaces if possible, and styles
+// that are open at the end of a piece are closed and reopened in the next
+// piece. Tokens longer than maxLength are not split.
+func splitLine(tokens []token, d Dialect, maxLength int) []string {
+	var pieces []string
+	var open []string
+	for {
+		for len(tokens) > 0 && tokens[0].space {
+			tokens = tokens[1:]
+		}
+		if len(tokens) == 0 {
+			return pieces
+		}
+		prefix := strings.Join(open, "")
+		// a line that starts with '#' and so on is a heading or a list in Discord.
+		if d == Discord && len(prefix) == 0 && discordLineStartEscapes[tokens[0].value[0]] {
+			prefix = "\\"
+		}
+		cut, styles := splitPoint(tokens, open, utf8.RuneCountInString(prefix), maxLength)
+		piece := prefix + joinTokens(tokens[:cut])
+		if cut == len(tokens) {
+			return append(pieces, piece)
+		}
+		piece = strings.TrimRight(piece, " ")
+		for i := len(styles) - 1; i >= 0; i-- {
+			piece += styles[i]
+		}
+		pieces = append(pieces, piece)
+		tokens, open = tokens[cut:], styles
+	}
+}
+
+// splitPoint returns the number of tokens of the next piece and styles that
+// are open at the end of the piece. length is the length of a prefix of
+// the piece. The piece has at least one token.
+func splitPoint(tokens []token, open []string, length, maxLength int) (int, []string) {
+	styles := append([]string{}, open...)
+	var cut, spaceCut int
+	var cutStyles, spaceCutStyles []string
+	content := false
+	for i, t := range tokens {
+		if t.space && content && length+markersLength(styles) <= maxLength {
+			spaceCut, spaceCutStyles = i, append([]string{}, styles...)
+		}
+		length += t.length
+		if length > maxLength {
+			break
+		}
+		closing := false
+		if t.marker {
+			n := len(styles)
+			styles = toggleStyle(styles, t.value)
+			closing = len(styles) < n
+		}
+		content = content || !(t.marker || t.space)
+		if i == len(tokens)-1 {
+			return len(tokens), styles
+		}
+		// pieces do not end with opening markers, and closing markers
+		// are not split from texts.
+		if content && (!t.marker || closing) && !tokens[i+1].marker &&
+			length+markersLength(styles) <= maxLength {
+			cut, cutStyles = i+1, append([]string{}, styles...)
+		}
+	}
+	switch {
+	case spaceCut > 0:
+		return spaceCut, spaceCutStyles
+	case cut > 0:
+		return cut, cutStyles
+	}
+	// the first token is longer than maxLength.
+	if tokens[0].marker {
+		styles = toggleStyle(append([]string{}, open...), tokens[0].value)
+		return 1, styles
+	}
+	return 1, append([]string{}, open...)
+}
+
+// toggleStyle closes the given style if it is open, otherwise opens it.
+func toggleStyle(styles []string, marker string) []string {
+	for i := len(styles) - 1; i >= 0; i-- {
+		if styles[i] == marker {
+			return append(styles[:i], styles[i+1:]...)
+		}
+	}
+	return append(styles, marker)
+}
+
+func markersLength(styles []string) int {
+	length := 0
+	for _, s := range styles {
+		length += len(s)
+	}
+	return length
+}
+
+func joinTokens(tokens []token) string {
+	var b strings.Builder
+	for _, t := range tokens {
+		b.WriteString(t.value)
+	}
+	return b.String()
+}
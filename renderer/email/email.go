@@ -0,0 +1,640 @@
+// Package email implements renderer that outputs HTML for emails.
+//
+// Email clients ignore <style> elements and class attributes, so the
+// renderer writes inline style attributes and uses tables for layouts
+// like blockquotes and code blocks.
+package email
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/extension"
+	east "github.com/yuin/goldmark/extension/ast"
+	"github.com/yuin/goldmark/renderer"
+	"github.com/yuin/goldmark/renderer/html"
+	"github.com/yuin/goldmark/util"
+)
+
+// Styles is a map of inline styles for each node kind.
+type Styles map[ast.NodeKind]string
+
+// DefaultStyles returns default styles.
+//
+// Styles of headings do not have font sizes because font sizes are
+// determined by levels of the headings.
+// Styles of blockquotes, code blocks, thematic breaks and footnote lists
+// are written to the cells of tables that wrap the blocks.
+func DefaultStyles() Styles {
+	code := "background-color:#f6f8fa;border-radius:6px;padding:12px 16px;font-size:13px;line-height:1.45;"
+	return Styles{
+		ast.KindDocument: "font-family:-apple-system,'Segoe UI',Helvetica,Arial,sans-serif;" +
+			"font-size:16px;line-height:1.5;color:#24292f;",
+		ast.KindHeading:         "margin:24px 0 16px 0;font-weight:bold;line-height:1.25;",
+		ast.KindParagraph:       "margin:0 0 16px 0;",
+		ast.KindBlockquote:      "border-left:4px solid #d0d7de;padding:0 16px;color:#57606a;",
+		ast.KindCodeBlock:       code,
+		ast.KindFencedCodeBlock: code,
+		ast.KindCodeSpan: "background-color:#eff1f3;border-radius:6px;padding:2px 4px;" +
+			"font-family:Menlo,Consolas,monospace;font-size:85%;",
+		ast.KindLink:           "color:#0969da;text-decoration:underline;",
+		ast.KindImage:          "max-width:100%;height:auto;border:0;",
+		ast.KindList:           "margin:0 0 16px 0;padding-left:32px;",
+		ast.KindListItem:       "margin:0 0 4px 0;",
+		ast.KindThematicBreak:  "border-top:1px solid #d0d7de;font-size:1px;line-height:1px;",
+		east.KindTable:         "border-collapse:collapse;margin:0 0 16px 0;",
+		east.KindTableHeader:   "font-weight:bold;background-color:#f6f8fa;",
+		east.KindTableCell:     "border:1px solid #d0d7de;padding:6px 13px;",
+		east.KindCaption:       "padding:6px 0;font-weight:bold;",
+		east.KindStrikethrough: "text-decoration:line-through;",
+		east.KindFootnoteLink:  "font-size:75%;line-height:0;vertical-align:super;",
+		east.KindFootnoteList:  "border-top:1px solid #d0d7de;padding-top:16px;font-size:13px;color:#57606a;",
+		east.KindFootnote:      "margin:0 0 4px 0;",
+	}
+}
+
+// headingFontSizes are font sizes of headings for each level.
+var headingFontSizes = [6]string{"28px", "24px", "20px", "16px", "14px", "13px"}
+
+// monospace is a font family of code blocks.
+const monospace = "font-family:Menlo,Consolas,monospace;"
+
+// A Config struct has configurations for the email renderer.
+type Config struct {
+	html.Config
+
+	// Styles are inline styles for each node kind.
+	Styles Styles
+}
+
+// NewConfig returns a new Config with defaults.
+func NewConfig() Config {
+	return Config{
+		Config: html.NewConfig(),
+		Styles: DefaultStyles(),
+	}
+}
+
+// SetOption implements renderer.SetOptioner.
+func (c *Config) SetOption(name renderer.OptionName, value any) {
+	switch name {
+	case optStyles:
+		c.setStyles(value.(Styles))
+	default:
+		c.Config.SetOption(name, value)
+	}
+}
+
+func (c *Config) setStyles(styles Styles) {
+	merged := Styles{}
+	for kind, style := range c.Styles {
+		merged[kind] = style
+	}
+	for kind, style := range styles {
+		merged[kind] = style
+	}
+	c.Styles = merged
+}
+
+// An Option interface sets options for the email renderer.
+type Option interface {
+	SetEmailOption(*Config)
+}
+
+// Styles is an option name used in WithStyles.
+const optStyles renderer.OptionName = "EmailStyles"
+
+type withStyles struct {
+	value Styles
+}
+
+func (o *withStyles) SetConfig(c *renderer.Config) {
+	c.Options[optStyles] = o.value
+}
+
+func (o *withStyles) SetEmailOption(c *Config) {
+	c.setStyles(o.value)
+}
+
+// WithStyles is a functional option that overrides styles for the given
+// node kinds. Empty styles remove styles of the node kinds.
+func WithStyles(styles Styles) interface {
+	renderer.Option
+	Option
+} {
+	return &withStyles{styles}
+}
+
+type withHTMLOptions struct {
+	value []html.Option
+}
+
+func (o *withHTMLOptions) SetEmailOption(c *Config) {
+	for _, v := range o.value {
+		v.(html.Option).SetHTMLOption(&c.Config)
+	}
+}
+
+// WithHTMLOptions is a functional option that wraps goldmark HTMLRenderer options.
+func WithHTMLOptions(opts ...html.Option) Option {
+	return &withHTMLOptions{opts}
+}
+
+// A Renderer struct is an implementation of renderer.NodeRenderer that renders
+// nodes as HTML for emails. Nodes that do not need inline styles like
+// emphases are rendered by the HTML renderer.
+type Renderer struct {
+	Config
+}
+
+// NewRenderer returns a new Renderer.
+func NewRenderer(opts ...Option) renderer.NodeRenderer {
+	r := &Renderer{
+		Config: NewConfig(),
+	}
+	for _, opt := range opts {
+		opt.SetEmailOption(&r.Config)
+	}
+	return r
+}
+
+// RegisterFuncs implements NodeRenderer.RegisterFuncs .
+func (r *Renderer) RegisterFuncs(reg renderer.NodeRendererFuncRegisterer) {
+	// blocks
+
+	reg.Register(ast.KindDocument, r.renderDocument)
+	reg.Register(ast.KindHeading, r.renderHeading)
+	reg.Register(ast.KindBlockquote, r.renderBlockquote)
+	reg.Register(ast.KindCodeBlock, r.renderCodeBlock)
+	reg.Register(ast.KindFencedCodeBlock, r.renderCodeBlock)
+	reg.Register(ast.KindList, r.renderList)
+	reg.Register(ast.KindListItem, r.renderListItem)
+	reg.Register(ast.KindParagraph, r.renderParagraph)
+	reg.Register(ast.KindThematicBreak, r.renderThematicBreak)
+
+	// inlines
+
+	reg.Register(ast.KindCodeSpan, r.renderCodeSpan)
+	reg.Register(ast.KindImage, r.renderImage)
+	reg.Register(ast.KindLink, r.renderLink)
+
+	// extensions
+
+	reg.Register(east.KindTable, r.renderTable)
+	reg.Register(east.KindTableHeader, r.renderTableHeader)
+	reg.Register(east.KindTableRow, r.renderTableRow)
+	reg.Register(east.KindTableCell, r.renderTableCell)
+	reg.Register(east.KindCaption, r.renderCaption)
+	reg.Register(east.KindStrikethrough, r.renderStrikethrough)
+	reg.Register(east.KindTaskCheckBox, r.renderTaskCheckBox)
+	reg.Register(east.KindFootnoteLink, r.renderFootnoteLink)
+	reg.Register(east.KindFootnoteBacklink, r.renderFootnoteBacklink)
+	reg.Register(east.KindFootnoteList, r.renderFootnoteList)
+	reg.Register(east.KindFootnote, r.renderFootnote)
+}
+
+// writeAttributes writes a style attribute and attributes of the node.
+// Class attributes are not written because email clients ignore them, and
+// style attributes of the node are appended to the given style.
+func (r *Renderer) writeAttributes(w util.BufWriter, n ast.Node, filter util.BytesFilter, style string) {
+	if v, ok := n.AttributeString("style"); ok {
+		if value, ok := v.([]byte); ok {
+			style += string(value)
+		}
+	}
+	if len(style) != 0 {
+		_, _ = w.WriteString(` style="`)
+		_, _ = w.Write(util.EscapeHTML([]byte(style)))
+		_ = w.WriteByte('"')
+	}
+	for _, attr := range n.Attributes() {
+		name := string(attr.Name)
+		if name == "style" || name == "class" {
+			continue
+		}
+		if filter != nil && !filter.Contains(attr.Name) && !strings.HasPrefix(name, "data-") {
+			continue
+		}
+		var value []byte
+		switch typed := attr.Value.(type) {
+		case []byte:
+			value = typed
+		case string:
+			value = []byte(typed)
+		}
+		_, _ = fmt.Fprintf(w, ` %s="%s"`, name, util.EscapeHTML(value))
+	}
+}
+
+// writeLayoutTable writes the beginning of a table that wraps a block.
+func (r *Renderer) writeLayoutTable(w util.BufWriter, style string) {
+	_, _ = w.WriteString(`<table role="presentation" width="100%" cellpadding="0" cellspacing="0" border="0"` +
+		` style="margin:0 0 16px 0;"><tr><td`)
+	if len(style) != 0 {
+		_, _ = fmt.Fprintf(w, ` style="%s"`, util.EscapeHTML([]byte(style)))
+	}
+	_, _ = w.WriteString(">")
+}
+
+func (r *Renderer) renderDocument(
+	w util.BufWriter, source []byte, n ast.Node, entering bool) (ast.WalkStatus, error) {
+	if entering {
+		_, _ = w.WriteString(`<table role="presentation" width="100%" cellpadding="0" cellspacing="0" border="0">`)
+		_, _ = w.WriteString("<tr><td")
+		r.writeAttributes(w, n, html.GlobalAttributeFilter, r.Styles[ast.KindDocument])
+		_, _ = w.WriteString(">\n")
+	} else {
+		_, _ = w.WriteString("</td></tr></table>\n")
+	}
+	return ast.WalkContinue, nil
+}
+
+func (r *Renderer) renderHeading(
+	w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	n := node.(*ast.Heading)
+	if entering {
+		style := r.Styles[ast.KindHeading]
+		if !strings.Contains(style, "font-size") {
+			style += "font-size:" + headingFontSizes[n.Level-1] + ";"
+		}
+		_, _ = fmt.Fprintf(w, "<h%d", n.Level)
+		r.writeAttributes(w, n, html.HeadingAttributeFilter, style)
+		_ = w.WriteByte('>')
+	} else {
+		_, _ = fmt.Fprintf(w, "</h%d>\n", n.Level)
+	}
+	return ast.WalkContinue, nil
+}
+
+// renderBlockquote renders blockquotes as tables because margins and
+// borders of blockquotes are not consistent in email clients.
+func (r *Renderer) renderBlockquote(
+	w util.BufWriter, source []byte, n ast.Node, entering bool) (ast.WalkStatus, error) {
+	if entering {
+		r.writeLayoutTable(w, r.Styles[ast.KindBlockquote])
+		_ = w.WriteByte('\n')
+	} else {
+		_, _ = w.WriteString("</td></tr></table>\n")
+	}
+	return ast.WalkContinue, nil
+}
+
+// renderCodeBlock renders code blocks as tables that have preformatted texts.
+func (r *Renderer) renderCodeBlock(
+	w util.BufWriter, source []byte, n ast.Node, entering bool) (ast.WalkStatus, error) {
+	if !entering {
+		return ast.WalkContinue, nil
+	}
+	r.writeLayoutTable(w, r.Styles[n.Kind()])
+	_, _ = w.WriteString(`<pre style="margin:0;white-space:pre-wrap;word-wrap:break-word;` + monospace + `">`)
+	l := n.Lines().Len()
+	for i := range l {
+		line := n.Lines().At(i)
+		r.Writer.RawWrite(w, line.Value(source))
+	}
+	_, _ = w.WriteString("</pre></td></tr></table>\n")
+	return ast.WalkSkipChildren, nil
+}
+
+func (r *Renderer) renderList(w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	n := node.(*ast.List)
+	tag := "ul"
+	if n.IsOrdered() {
+		tag = "ol"
+	}
+	if entering {
+		_ = w.WriteByte('<')
+		_, _ = w.WriteString(tag)
+		if n.IsOrdered() && n.Start != 1 {
+			_, _ = fmt.Fprintf(w, ` start="%d"`, n.Start)
+		}
+		r.writeAttributes(w, n, html.ListAttributeFilter, r.Styles[ast.KindList])
+		_, _ = w.WriteString(">\n")
+	} else {
+		_, _ = fmt.Fprintf(w, "</%s>\n", tag)
+	}
+	return ast.WalkContinue, nil
+}
+
+func (r *Renderer) renderListItem(
+	w util.BufWriter, source []byte, n ast.Node, entering bool) (ast.WalkStatus, error) {
+	if entering {
+		_, _ = w.WriteString("<li")
+		r.writeAttributes(w, n, html.ListItemAttributeFilter, r.Styles[ast.KindListItem])
+		_ = w.WriteByte('>')
+		fc := n.FirstChild()
+		if fc != nil {
+			if _, ok := fc.(*ast.TextBlock); !ok {
+				_ = w.WriteByte('\n')
+			}
+		}
+	} else {
+		_, _ = w.WriteString("</li>\n")
+	}
+	return ast.WalkContinue, nil
+}
+
+func (r *Renderer) renderParagraph(
+	w util.BufWriter, source []byte, n ast.Node, entering bool) (ast.WalkStatus, error) {
+	if entering {
+		_, _ = w.WriteString("<p")
+		r.writeAttributes(w, n, html.ParagraphAttributeFilter, r.Styles[ast.KindParagraph])
+		_ = w.WriteByte('>')
+	} else {
+		_, _ = w.WriteString("</p>\n")
+	}
+	return ast.WalkContinue, nil
+}
+
+// renderThematicBreak renders thematic breaks as tables that have borders
+// because styles of <hr> elements are not consistent in email clients.
+func (r *Renderer) renderThematicBreak(
+	w util.BufWriter, source []byte, n ast.Node, entering bool) (ast.WalkStatus, error) {
+	if entering {
+		r.writeLayoutTable(w, r.Styles[ast.KindThematicBreak])
+		_, _ = w.WriteString("&#160;</td></tr></table>\n")
+	}
+	return ast.WalkSkipChildren, nil
+}
+
+func (r *Renderer) renderCodeSpan(w util.BufWriter, source []byte, n ast.Node, entering bool) (ast.WalkStatus, error) {
+	if !entering {
+		_, _ = w.WriteString("</code>")
+		return ast.WalkContinue, nil
+	}
+	_, _ = w.WriteString("<code")
+	r.writeAttributes(w, n, html.CodeAttributeFilter, r.Styles[ast.KindCodeSpan])
+	_ = w.WriteByte('>')
+	for c := n.FirstChild(); c != nil; c = c.NextSibling() {
+		segment := c.(*ast.Text).Segment
+		value := segment.Value(source)
+		if bytes.HasSuffix(value, []byte("\n")) {
+			r.Writer.RawWrite(w, value[:len(value)-1])
+			r.Writer.RawWrite(w, []byte(" "))
+		} else {
+			r.Writer.RawWrite(w, value)
+		}
+	}
+	return ast.WalkSkipChildren, nil
+}
+
+// writeURL writes the given URL unless the URL is dangerous.
+func (r *Renderer) writeURL(w util.BufWriter, url []byte) {
+	dest := util.URLEscape(url, true)
+	if r.Unsafe || !html.IsDangerousURL(dest) {
+		_, _ = w.Write(util.EscapeHTML(dest))
+	}
+}
+
+func (r *Renderer) renderLink(w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	n := node.(*ast.Link)
+	if !entering {
+		_, _ = w.WriteString("</a>")
+		return ast.WalkContinue, nil
+	}
+	_, _ = w.WriteString(`<a href="`)
+	r.writeURL(w, n.Destination)
+	_ = w.WriteByte('"')
+	if n.Title != nil {
+		_, _ = w.WriteString(` title="`)
+		r.Writer.Write(w, n.Title)
+		_ = w.WriteByte('"')
+	}
+	r.writeAttributes(w, n, html.LinkAttributeFilter, r.Styles[ast.KindLink])
+	_ = w.WriteByte('>')
+	return ast.WalkContinue, nil
+}
+
+// writeTexts writes texts of the given node.
+func (r *Renderer) writeTexts(w util.BufWriter, source []byte, n ast.Node) {
+	for c := n.FirstChild(); c != nil; c = c.NextSibling() {
+		switch typed := c.(type) {
+		case *ast.Text:
+			segment := typed.Segment
+			if typed.IsRaw() {
+				r.Writer.RawWrite(w, segment.Value(source))
+			} else {
+				r.Writer.Write(w, segment.Value(source))
+			}
+		case *ast.String:
+			r.Writer.RawWrite(w, typed.Value)
+		default:
+			r.writeTexts(w, source, c)
+		}
+	}
+}
+
+func (r *Renderer) renderImage(w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	if !entering {
+		return ast.WalkContinue, nil
+	}
+	n := node.(*ast.Image)
+	_, _ = w.WriteString(`<img src="`)
+	r.writeURL(w, n.Destination)
+	_, _ = w.WriteString(`" alt="`)
+	r.writeTexts(w, source, n)
+	_ = w.WriteByte('"')
+	if n.Title != nil {
+		_, _ = w.WriteString(` title="`)
+		r.Writer.Write(w, n.Title)
+		_ = w.WriteByte('"')
+	}
+	_, _ = w.WriteString(` border="0"`)
+	r.writeAttributes(w, n, html.ImageAttributeFilter, r.Styles[ast.KindImage])
+	if r.XHTML {
+		_, _ = w.WriteString(" />")
+	} else {
+		_, _ = w.WriteString(">")
+	}
+	return ast.WalkSkipChildren, nil
+}
+
+func (r *Renderer) renderTable(
+	w util.BufWriter, source []byte, n ast.Node, entering bool) (ast.WalkStatus, error) {
+	if entering {
+		_, _ = w.WriteString(`<table cellpadding="0" cellspacing="0" border="0"`)
+		r.writeAttributes(w, n, extension.TableAttributeFilter, r.Styles[east.KindTable])
+		_, _ = w.WriteString(">\n")
+	} else {
+		_, _ = w.WriteString("</table>\n")
+	}
+	return ast.WalkContinue, nil
+}
+
+func (r *Renderer) renderCaption(
+	w util.BufWriter, source []byte, n ast.Node, entering bool) (ast.WalkStatus, error) {
+	if entering {
+		_, _ = w.WriteString("<caption")
+		r.writeAttributes(w, n, extension.TableCaptionAttributeFilter, r.Styles[east.KindCaption])
+		_ = w.WriteByte('>')
+	} else {
+		_, _ = w.WriteString("</caption>\n")
+	}
+	return ast.WalkContinue, nil
+}
+
+func (r *Renderer) renderTableHeader(
+	w util.BufWriter, source []byte, n ast.Node, entering bool) (ast.WalkStatus, error) {
+	if entering {
+		_, _ = w.WriteString("<thead>\n<tr>\n")
+	} else {
+		_, _ = w.WriteString("</tr>\n</thead>\n")
+		if n.NextSibling() != nil {
+			_, _ = w.WriteString("<tbody>\n")
+		}
+	}
+	return ast.WalkContinue, nil
+}
+
+func (r *Renderer) renderTableRow(
+	w util.BufWriter, source []byte, n ast.Node, entering bool) (ast.WalkStatus, error) {
+	if entering {
+		if prev := n.PreviousSibling(); prev == nil || prev.Kind() == east.KindCaption {
+			_, _ = w.WriteString("<tbody>\n")
+		}
+		_, _ = w.WriteString("<tr")
+		r.writeAttributes(w, n, extension.TableRowAttributeFilter, "")
+		_, _ = w.WriteString(">\n")
+	} else {
+		_, _ = w.WriteString("</tr>\n")
+		if n.Parent().LastChild() == n {
+			_, _ = w.WriteString("</tbody>\n")
+		}
+	}
+	return ast.WalkContinue, nil
+}
+
+// renderTableCell renders table cells. Alignments of the cells are written
+// as both align attributes and styles.
+func (r *Renderer) renderTableCell(
+	w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	n := node.(*east.TableCell)
+	tag := "td"
+	style := r.Styles[east.KindTableCell]
+	if n.Parent().Kind() == east.KindTableHeader {
+		tag = "th"
+		style += r.Styles[east.KindTableHeader]
+	}
+	if !entering {
+		_, _ = fmt.Fprintf(w, "</%s>\n", tag)
+		return ast.WalkContinue, nil
+	}
+	_ = w.WriteByte('<')
+	_, _ = w.WriteString(tag)
+	if n.Alignment != east.AlignNone {
+		_, _ = fmt.Fprintf(w, ` align="%s"`, n.Alignment.String())
+		style += "text-align:" + n.Alignment.String() + ";"
+	}
+	if n.ColSpan > 1 {
+		_, _ = w.WriteString(` colspan="` + strconv.Itoa(n.ColSpan) + `"`)
+	}
+	if n.RowSpan > 1 {
+		_, _ = w.WriteString(` rowspan="` + strconv.Itoa(n.RowSpan) + `"`)
+	}
+	r.writeAttributes(w, n, extension.TableTdCellAttributeFilter, style)
+	_ = w.WriteByte('>')
+	return ast.WalkContinue, nil
+}
+
+func (r *Renderer) renderStrikethrough(
+	w util.BufWriter, source []byte, n ast.Node, entering bool) (ast.WalkStatus, error) {
+	if entering {
+		_, _ = w.WriteString("<span")
+		r.writeAttributes(w, n, html.GlobalAttributeFilter, r.Styles[east.KindStrikethrough])
+		_ = w.WriteByte('>')
+	} else {
+		_, _ = w.WriteString("</span>")
+	}
+	return ast.WalkContinue, nil
+}
+
+// renderTaskCheckBox renders check boxes as glyphs because email clients
+// do not support form controls.
+func (r *Renderer) renderTaskCheckBox(
+	w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	if !entering {
+		return ast.WalkContinue, nil
+	}
+	if node.(*east.TaskCheckBox).IsChecked {
+		_, _ = w.WriteString("&#9745; ")
+	} else {
+		_, _ = w.WriteString("&#9744; ")
+	}
+	return ast.WalkContinue, nil
+}
+
+// renderFootnoteLink renders footnote references as superscript numbers
+// without links because many email clients remove id attributes, which
+// breaks links to fragments.
+func (r *Renderer) renderFootnoteLink(
+	w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	if entering {
+		_, _ = w.WriteString("<sup")
+		r.writeAttributes(w, node, html.GlobalAttributeFilter, r.Styles[east.KindFootnoteLink])
+		_, _ = fmt.Fprintf(w, ">%d</sup>", node.(*east.FootnoteLink).Index)
+	}
+	return ast.WalkContinue, nil
+}
+
+// renderFootnoteBacklink renders nothing for the same reason as
+// renderFootnoteLink.
+func (r *Renderer) renderFootnoteBacklink(
+	w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	return ast.WalkContinue, nil
+}
+
+// writeFootnoteList writes the beginning of a list of footnotes that starts
+// with the given footnote. n is the footnote list, or nil if the footnote is
+// not in a footnote list.
+func (r *Renderer) writeFootnoteList(w util.BufWriter, n ast.Node, first *east.Footnote) {
+	r.writeLayoutTable(w, r.Styles[east.KindFootnoteList])
+	_, _ = w.WriteString("\n<ol")
+	if first != nil && first.Index > 1 {
+		_, _ = fmt.Fprintf(w, ` start="%d"`, first.Index)
+	}
+	if n != nil {
+		r.writeAttributes(w, n, html.GlobalAttributeFilter, r.Styles[ast.KindList])
+	} else if style := r.Styles[ast.KindList]; len(style) != 0 {
+		_, _ = fmt.Fprintf(w, ` style="%s"`, util.EscapeHTML([]byte(style)))
+	}
+	_, _ = w.WriteString(">\n")
+}
+
+// renderFootnoteList renders footnote lists as tables that have borders
+// instead of <hr> elements, like thematic breaks.
+func (r *Renderer) renderFootnoteList(
+	w util.BufWriter, source []byte, n ast.Node, entering bool) (ast.WalkStatus, error) {
+	if entering {
+		first, _ := n.FirstChild().(*east.Footnote)
+		r.writeFootnoteList(w, n, first)
+	} else {
+		_, _ = w.WriteString("</ol>\n</td></tr></table>\n")
+	}
+	return ast.WalkContinue, nil
+}
+
+// renderFootnote renders footnotes as list items. Footnotes that are not in
+// footnote lists, like sidenotes, are rendered as lists that have a single
+// item.
+func (r *Renderer) renderFootnote(
+	w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	n := node.(*east.Footnote)
+	inList := n.Parent() != nil && n.Parent().Kind() == east.KindFootnoteList
+	if entering {
+		if !inList {
+			r.writeFootnoteList(w, nil, n)
+		}
+		_, _ = w.WriteString("<li")
+		r.writeAttributes(w, n, html.ListItemAttributeFilter, r.Styles[east.KindFootnote])
+		_, _ = w.WriteString(">\n")
+	} else {
+		_, _ = w.WriteString("</li>\n")
+		if !inList {
+			_, _ = w.WriteString("</ol>\n</td></tr></table>\n")
+		}
+	}
+	return ast.WalkContinue, nil
+}
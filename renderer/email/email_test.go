@@ -0,0 +1,132 @@
+package email_test
+
+import (
+	"testing"
+
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/extension"
+	"github.com/yuin/goldmark/renderer"
+	"github.com/yuin/goldmark/renderer/email"
+	"github.com/yuin/goldmark/testutil"
+	"github.com/yuin/goldmark/util"
+)
+
+func TestRenderer(t *testing.T) {
+	markdown := goldmark.New(
+		goldmark.WithRendererOptions(
+			renderer.WithNodeRenderers(util.Prioritized(email.NewRenderer(
+				email.WithStyles(email.Styles{
+					ast.KindDocument:  "",
+					ast.KindHeading:   "margin:0;",
+					ast.KindParagraph: "",
+					ast.KindCodeSpan:  "",
+					ast.KindLink:      "",
+					ast.KindList:      "",
+					ast.KindListItem:  "",
+				}),
+			), 100)),
+		),
+		goldmark.WithExtensions(extension.GFM),
+	)
+	testutil.DoTestCase(
+		markdown,
+		testutil.MarkdownTestCase{
+			No:          1,
+			Description: "Core nodes",
+			Markdown: `## Title
+
+Hello *world* ` + "`a<b`" + ` [link](http://example.com/ "t") ![logo](/logo.png)
+
+> quote
+
+` + "```go\nif a < b {}\n```" + `
+
+---
+`,
+			Expected: `<table role="presentation" width="100%" cellpadding="0" cellspacing="0" border="0"><tr><td>
+<h2 style="margin:0;font-size:24px;">Title</h2>
+<p>Hello <em>world</em> <code>a&lt;b</code> <a href="http://example.com/" title="t">link</a> <img src="/logo.png" alt="logo" border="0" style="max-width:100%;height:auto;border:0;"></p>
+<table role="presentation" width="100%" cellpadding="0" cellspacing="0" border="0" style="margin:0 0 16px 0;"><tr><td style="border-left:4px solid #d0d7de;padding:0 16px;color:#57606a;">
+<p>quote</p>
+</td></tr></table>
+<table role="presentation" width="100%" cellpadding="0" cellspacing="0" border="0" style="margin:0 0 16px 0;"><tr><td style="background-color:#f6f8fa;border-radius:6px;padding:12px 16px;font-size:13px;line-height:1.45;"><pre style="margin:0;white-space:pre-wrap;word-wrap:break-word;font-family:Menlo,Consolas,monospace;">if a &lt; b {}
+</pre></td></tr></table>
+<table role="presentation" width="100%" cellpadding="0" cellspacing="0" border="0" style="margin:0 0 16px 0;"><tr><td style="border-top:1px solid #d0d7de;font-size:1px;line-height:1px;">&#160;</td></tr></table>
+</td></tr></table>`,
+		},
+		t,
+	)
+
+	testutil.DoTestCase(
+		markdown,
+		testutil.MarkdownTestCase{
+			No:          2,
+			Description: "GFM extensions",
+			Markdown: `- [x] ~~done~~
+- [ ] todo
+
+| a | b |
+|:-|-:|
+| 1 | 2 |
+`,
+			Expected: `<table role="presentation" width="100%" cellpadding="0" cellspacing="0" border="0"><tr><td>
+<ul>
+<li>&#9745; <span style="text-decoration:line-through;">done</span></li>
+<li>&#9744; todo</li>
+</ul>
+<table cellpadding="0" cellspacing="0" border="0" style="border-collapse:collapse;margin:0 0 16px 0;">
+<thead>
+<tr>
+<th align="left" style="border:1px solid #d0d7de;padding:6px 13px;font-weight:bold;background-color:#f6f8fa;text-align:left;">a</th>
+<th align="right" style="border:1px solid #d0d7de;padding:6px 13px;font-weight:bold;background-color:#f6f8fa;text-align:right;">b</th>
+</tr>
+</thead>
+<tbody>
+<tr>
+<td align="left" style="border:1px solid #d0d7de;padding:6px 13px;text-align:left;">1</td>
+<td align="right" style="border:1px solid #d0d7de;padding:6px 13px;text-align:right;">2</td>
+</tr>
+</tbody>
+</table>
+</td></tr></table>`,
+		},
+		t,
+	)
+}
+
+func TestRendererFootnotes(t *testing.T) {
+	markdown := goldmark.New(
+		goldmark.WithRendererOptions(
+			renderer.WithNodeRenderers(util.Prioritized(email.NewRenderer(
+				email.WithStyles(email.Styles{
+					ast.KindDocument:  "",
+					ast.KindParagraph: "",
+				}),
+			), 100)),
+		),
+		goldmark.WithExtensions(extension.Footnote),
+	)
+	testutil.DoTestCase(
+		markdown,
+		testutil.MarkdownTestCase{
+			No:          1,
+			Description: "Footnotes",
+			Markdown: `a[^1] b[^1]
+
+[^1]: note
+`,
+			Expected: `<table role="presentation" width="100%" cellpadding="0" cellspacing="0" border="0"><tr><td>
+<p>a<sup style="font-size:75%;line-height:0;vertical-align:super;">1</sup> b<sup style="font-size:75%;line-height:0;vertical-align:super;">1</sup></p>
+<table role="presentation" width="100%" cellpadding="0" cellspacing="0" border="0" style="margin:0 0 16px 0;"><tr><td style="border-top:1px solid #d0d7de;padding-top:16px;font-size:13px;color:#57606a;">
+<ol style="margin:0 0 16px 0;padding-left:32px;">
+<li style="margin:0 0 4px 0;">
+<p>note</p>
+</li>
+</ol>
+</td></tr></table>
+</td></tr></table>`,
+		},
+		t,
+	)
+}
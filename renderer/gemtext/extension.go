@@ -0,0 +1,174 @@
+package gemtext
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/yuin/goldmark/ast"
+	east "github.com/yuin/goldmark/extension/ast"
+	"github.com/yuin/goldmark/renderer/internal/renderutil"
+	"github.com/yuin/goldmark/util"
+)
+
+// tableGrid returns plain texts of the cells in the table.
+// Columns that are spanned by cells are empty.
+func tableGrid(table *east.Table, source []byte) [][]string {
+	var grid [][]string
+	remaining := make([]int, len(table.Alignments))
+	for r := table.FirstChild(); r != nil; r = r.NextSibling() {
+		if r.Kind() != east.KindTableRow && r.Kind() != east.KindTableHeader {
+			continue
+		}
+		row := make([]string, len(remaining))
+		col := 0
+		for c := r.FirstChild(); c != nil; c = c.NextSibling() {
+			for col < len(remaining) && remaining[col] > 0 {
+				col++
+			}
+			if col >= len(remaining) {
+				break
+			}
+			cell := c.(*east.TableCell)
+			row[col] = string(plainText(cell, source))
+			for k := col; k < col+cell.ColSpan && k < len(remaining); k++ {
+				remaining[k] = cell.RowSpan
+			}
+			col += cell.ColSpan
+		}
+		for i := range remaining {
+			if remaining[i] > 0 {
+				remaining[i]--
+			}
+		}
+		grid = append(grid, row)
+	}
+	return grid
+}
+
+// pad returns the given value padded to the given width.
+func pad(value string, width int, alignment east.Alignment) string {
+	n := width - utf8.RuneCountInString(value)
+	switch alignment {
+	case east.AlignRight:
+		return strings.Repeat(" ", n) + value
+	case east.AlignCenter:
+		return strings.Repeat(" ", n/2) + value + strings.Repeat(" ", n-n/2)
+	}
+	return value + strings.Repeat(" ", n)
+}
+
+// renderTable renders tables as preformatted texts. Captions of the tables
+// are used as alternative texts.
+func (r *Renderer) renderTable(
+	w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	if !entering {
+		return ast.WalkContinue, nil
+	}
+	n := node.(*east.Table)
+	r.writeBlockSeparator(w, n)
+	var alt []byte
+	header := n.FirstChild()
+	if header != nil && header.Kind() == east.KindCaption {
+		alt = plainText(header, source)
+		header = header.NextSibling()
+	}
+	grid := tableGrid(n, source)
+	widths := make([]int, len(n.Alignments))
+	for _, row := range grid {
+		for i, cell := range row {
+			widths[i] = max(widths[i], utf8.RuneCountInString(cell))
+		}
+	}
+	var lines [][]byte
+	for i, row := range grid {
+		var buf bytes.Buffer
+		for j, cell := range row {
+			if j != 0 {
+				_, _ = buf.WriteString(" | ")
+			}
+			_, _ = buf.WriteString(pad(cell, widths[j], n.Alignments[j]))
+		}
+		lines = append(lines, bytes.TrimRight(buf.Bytes(), " "))
+		if i == 0 && header != nil && header.Kind() == east.KindTableHeader {
+			separators := make([]string, len(widths))
+			for j, width := range widths {
+				separators[j] = strings.Repeat("-", width)
+			}
+			lines = append(lines, []byte(strings.Join(separators, "-|-")))
+		}
+	}
+	writePreformatted(w, alt, lines)
+	r.writeLinks(w, source, n)
+	return ast.WalkSkipChildren, nil
+}
+
+func taskCheckBox(n *east.TaskCheckBox) string {
+	if n.IsChecked {
+		return "[x] "
+	}
+	return "[ ] "
+}
+
+func (r *Renderer) renderTaskCheckBox(
+	w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	if entering {
+		_, _ = w.WriteString(taskCheckBox(node.(*east.TaskCheckBox)))
+	}
+	return ast.WalkContinue, nil
+}
+
+func (r *Renderer) renderFootnoteLink(
+	w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	if entering {
+		_, _ = fmt.Fprintf(w, "[%d]", node.(*east.FootnoteLink).Index)
+	}
+	return ast.WalkContinue, nil
+}
+
+// renderFootnote renders footnotes as paragraphs that start with their numbers.
+func (r *Renderer) renderFootnote(
+	w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	if !entering {
+		return ast.WalkContinue, nil
+	}
+	n := node.(*east.Footnote)
+	r.writeBlockSeparator(w, n)
+	contents, err := renderutil.RenderChildrenToBytes(source, r.funcs, n)
+	if err != nil {
+		return ast.WalkStop, err
+	}
+	_, _ = fmt.Fprintf(w, "[%d] ", n.Index)
+	_, _ = w.Write(bytes.TrimSpace(contents))
+	_ = w.WriteByte('\n')
+	r.writeLinks(w, source, n)
+	return ast.WalkSkipChildren, nil
+}
+
+// renderFigure renders figures as link lines labeled by their captions.
+func (r *Renderer) renderFigure(
+	w util.BufWriter, source []byte, n ast.Node, entering bool) (ast.WalkStatus, error) {
+	if !entering {
+		return ast.WalkContinue, nil
+	}
+	r.writeBlockSeparator(w, n)
+	var image *ast.Image
+	var caption []byte
+	for c := n.FirstChild(); c != nil; c = c.NextSibling() {
+		switch typed := c.(type) {
+		case *ast.Image:
+			image = typed
+		case *east.FigureCaption:
+			caption = plainText(typed, source)
+		}
+	}
+	if image == nil {
+		return ast.WalkSkipChildren, nil
+	}
+	if len(caption) == 0 {
+		caption = plainText(image, source)
+	}
+	writeLinkLine(w, image.Destination, caption)
+	return ast.WalkSkipChildren, nil
+}
@@ -0,0 +1,468 @@
+// Package gemtext implements renderer that outputs gemtext, the text format
+// of the Gemini protocol.
+//
+// Gemtext has no inline markups and links must be on their own lines.
+// Inline markups are flattened into plain texts, and links in each top
+// level block are written as link lines after the block.
+package gemtext
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/yuin/goldmark/ast"
+	east "github.com/yuin/goldmark/extension/ast"
+	"github.com/yuin/goldmark/renderer"
+	"github.com/yuin/goldmark/renderer/internal/renderutil"
+	"github.com/yuin/goldmark/util"
+)
+
+// A Renderer struct is an implementation of renderer.NodeRenderer that renders
+// nodes as gemtext.
+type Renderer struct {
+	funcs map[ast.NodeKind]renderer.NodeRendererFunc
+}
+
+// NewRenderer returns a new Renderer.
+func NewRenderer() renderer.NodeRenderer {
+	r := &Renderer{}
+	r.funcs = map[ast.NodeKind]renderer.NodeRendererFunc{
+		// blocks
+
+		ast.KindDocument:                renderContents,
+		ast.KindHeading:                 r.renderHeading,
+		ast.KindBlockquote:              r.renderBlockquote,
+		ast.KindCodeBlock:               r.renderCodeBlock,
+		ast.KindFencedCodeBlock:         r.renderCodeBlock,
+		ast.KindHTMLBlock:               renderNothing,
+		ast.KindList:                    r.renderList,
+		ast.KindListItem:                r.renderListItem,
+		ast.KindParagraph:               r.renderParagraph,
+		ast.KindTextBlock:               r.renderParagraph,
+		ast.KindThematicBreak:           r.renderThematicBreak,
+		ast.KindLinkReferenceDefinition: renderNothing,
+
+		// inlines
+
+		ast.KindAutoLink: r.renderAutoLink,
+		ast.KindCodeSpan: r.renderCodeSpan,
+		ast.KindEmphasis: renderContents,
+		ast.KindImage:    r.renderImage,
+		ast.KindLink:     renderContents,
+		ast.KindRawHTML:  renderNothing,
+		ast.KindText:     r.renderText,
+		ast.KindString:   r.renderString,
+
+		// extensions
+
+		east.KindTable:                  r.renderTable,
+		east.KindStrikethrough:          renderContents,
+		east.KindTaskCheckBox:           r.renderTaskCheckBox,
+		east.KindDefinitionList:         r.renderList,
+		east.KindDefinitionTerm:         r.renderParagraph,
+		east.KindDefinitionDescription:  r.renderListItem,
+		east.KindFootnoteLink:           r.renderFootnoteLink,
+		east.KindFootnoteBacklink:       renderNothing,
+		east.KindFootnote:               r.renderFootnote,
+		east.KindFootnoteList:           renderContents,
+		east.KindAbbreviation:           renderContents,
+		east.KindAbbreviationDefinition: renderNothing,
+		east.KindFigure:                 r.renderFigure,
+	}
+	return r
+}
+
+// RegisterFuncs implements NodeRenderer.RegisterFuncs .
+func (r *Renderer) RegisterFuncs(reg renderer.NodeRendererFuncRegisterer) {
+	for kind, f := range r.funcs {
+		reg.Register(kind, f)
+	}
+}
+
+func renderNothing(_ util.BufWriter, _ []byte, _ ast.Node, _ bool) (ast.WalkStatus, error) {
+	return ast.WalkSkipChildren, nil
+}
+
+func renderContents(_ util.BufWriter, _ []byte, _ ast.Node, _ bool) (ast.WalkStatus, error) {
+	return ast.WalkContinue, nil
+}
+
+// isListContents returns true if the given block is rendered as a part of
+// a list item.
+func isListContents(n ast.Node) bool {
+	p := n.Parent()
+	return p != nil && (p.Kind() == ast.KindListItem || p.Kind() == east.KindDefinitionDescription)
+}
+
+// isTopLevel returns true if links in the given block are written after
+// the block.
+func isTopLevel(n ast.Node) bool {
+	p := n.Parent()
+	return p == nil || p.Kind() == ast.KindDocument || p.Kind() == east.KindFootnoteList
+}
+
+// writeBlockSeparator writes a blank line before the given block. Footnotes
+// are separated from the block before the footnote list.
+func (r *Renderer) writeBlockSeparator(w util.BufWriter, n ast.Node) {
+	if isListContents(n) {
+		return
+	}
+	prev := renderutil.PreviousBlock(n, ast.KindLinkReferenceDefinition, ast.KindHTMLBlock)
+	if prev == nil && n.Kind() == east.KindFootnote {
+		prev = n.Parent().PreviousSibling()
+	}
+	if prev != nil {
+		_ = w.WriteByte('\n')
+	}
+}
+
+// isLinkLineTarget returns true if the given node is written as a link line.
+func isLinkLineTarget(n ast.Node) bool {
+	switch typed := n.(type) {
+	case *ast.Link:
+		// gemtext does not have anchors.
+		return len(typed.Destination) != 0 && typed.Destination[0] != '#'
+	case *ast.Image, *ast.AutoLink:
+		return true
+	}
+	return false
+}
+
+// writeLinks writes link lines for links in the given block.
+func (r *Renderer) writeLinks(w util.BufWriter, source []byte, n ast.Node) {
+	if !isTopLevel(n) {
+		return
+	}
+	written := map[string]bool{}
+	_ = ast.Walk(n, func(c ast.Node, entering bool) (ast.WalkStatus, error) {
+		if !entering || !isLinkLineTarget(c) {
+			return ast.WalkContinue, nil
+		}
+		s := ast.SourceOf(c, source)
+		var url, label []byte
+		switch typed := c.(type) {
+		case *ast.Link:
+			url, label = typed.Destination, plainText(typed, s)
+		case *ast.Image:
+			url, label = typed.Destination, plainText(typed, s)
+		case *ast.AutoLink:
+			url, label = typed.URL(s), typed.Label(s)
+		}
+		key := string(url) + "\x00" + string(label)
+		if !written[key] {
+			written[key] = true
+			writeLinkLine(w, url, label)
+		}
+		return ast.WalkSkipChildren, nil
+	})
+}
+
+// writeLinkLine writes a link line.
+func writeLinkLine(w util.BufWriter, url, label []byte) {
+	_, _ = w.WriteString("=> ")
+	_, _ = w.Write(url)
+	if len(label) != 0 && !bytes.Equal(label, url) {
+		_ = w.WriteByte(' ')
+		_, _ = w.Write(label)
+	}
+	_ = w.WriteByte('\n')
+}
+
+// lineTypePrefixes are prefixes of lines that have special meanings.
+var lineTypePrefixes = []string{"=>", "#", "* ", ">", "```"}
+
+// writeTextLines writes the given text lines. Gemtext does not have
+// escapes, so a space is written before lines that would be other line types.
+func writeTextLines(w util.BufWriter, lines []byte) {
+	for _, line := range bytes.Split(lines, []byte("\n")) {
+		for _, prefix := range lineTypePrefixes {
+			if bytes.HasPrefix(line, []byte(prefix)) {
+				_ = w.WriteByte(' ')
+				break
+			}
+		}
+		_, _ = w.Write(line)
+		_ = w.WriteByte('\n')
+	}
+}
+
+// writeLine writes the given text with the given prefix as one line.
+func writeLine(w util.BufWriter, prefix string, text []byte) {
+	_, _ = w.WriteString(prefix)
+	_, _ = w.Write(bytes.ReplaceAll(text, []byte("\n"), []byte(" ")))
+	_ = w.WriteByte('\n')
+}
+
+func (r *Renderer) renderHeading(
+	w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	if !entering {
+		return ast.WalkContinue, nil
+	}
+	n := node.(*ast.Heading)
+	r.writeBlockSeparator(w, n)
+	text, err := renderutil.RenderChildrenToBytes(source, r.funcs, n)
+	if err != nil {
+		return ast.WalkStop, err
+	}
+	// gemtext has only 3 levels of headings.
+	writeLine(w, strings.Repeat("#", min(n.Level, 3))+" ", text)
+	r.writeLinks(w, source, n)
+	return ast.WalkSkipChildren, nil
+}
+
+func (r *Renderer) renderBlockquote(
+	w util.BufWriter, source []byte, n ast.Node, entering bool) (ast.WalkStatus, error) {
+	if !entering {
+		return ast.WalkContinue, nil
+	}
+	r.writeBlockSeparator(w, n)
+	contents, err := renderutil.RenderChildrenToBytes(source, r.funcs, n)
+	if err != nil {
+		return ast.WalkStop, err
+	}
+	for _, line := range bytes.Split(contents, []byte("\n")) {
+		_ = w.WriteByte('>')
+		if len(line) != 0 {
+			_ = w.WriteByte(' ')
+			_, _ = w.Write(line)
+		}
+		_ = w.WriteByte('\n')
+	}
+	r.writeLinks(w, source, n)
+	return ast.WalkSkipChildren, nil
+}
+
+// writePreformatted writes the given lines as a preformatted block.
+func writePreformatted(w util.BufWriter, alt []byte, lines [][]byte) {
+	_, _ = w.WriteString("```")
+	_, _ = w.Write(alt)
+	_ = w.WriteByte('\n')
+	for _, line := range lines {
+		// lines that start with '```' end preformatted blocks.
+		if bytes.HasPrefix(line, []byte("```")) {
+			_ = w.WriteByte(' ')
+		}
+		_, _ = w.Write(line)
+		if !bytes.HasSuffix(line, []byte("\n")) {
+			_ = w.WriteByte('\n')
+		}
+	}
+	_, _ = w.WriteString("```\n")
+}
+
+func (r *Renderer) renderCodeBlock(
+	w util.BufWriter, source []byte, n ast.Node, entering bool) (ast.WalkStatus, error) {
+	if !entering {
+		return ast.WalkContinue, nil
+	}
+	r.writeBlockSeparator(w, n)
+	var alt []byte
+	if fcb, ok := n.(*ast.FencedCodeBlock); ok && fcb.Info != nil {
+		segment := fcb.Info.Segment
+		alt = segment.Value(source)
+	}
+	var lines [][]byte
+	for i := range n.Lines().Len() {
+		line := n.Lines().At(i)
+		lines = append(lines, line.Value(source))
+	}
+	writePreformatted(w, alt, lines)
+	return ast.WalkSkipChildren, nil
+}
+
+func (r *Renderer) renderList(w util.BufWriter, source []byte, n ast.Node, entering bool) (ast.WalkStatus, error) {
+	if entering {
+		r.writeBlockSeparator(w, n)
+	} else {
+		r.writeLinks(w, source, n)
+	}
+	return ast.WalkContinue, nil
+}
+
+// listMarker returns a marker of the given list item.
+// Gemtext has only unordered lists, so numbers of ordered lists are
+// written after markers.
+func listMarker(n ast.Node) string {
+	list, ok := n.Parent().(*ast.List)
+	if !ok || !list.IsOrdered() {
+		return "* "
+	}
+	number := list.Start
+	for c := n.PreviousSibling(); c != nil; c = c.PreviousSibling() {
+		number++
+	}
+	return fmt.Sprintf("* %d%c ", number, list.Marker)
+}
+
+// renderListItem renders list items as list lines. Gemtext does not have
+// nested lists, so nested lists are written as list lines after the item.
+func (r *Renderer) renderListItem(
+	w util.BufWriter, source []byte, n ast.Node, entering bool) (ast.WalkStatus, error) {
+	if !entering {
+		return ast.WalkContinue, nil
+	}
+	marker := listMarker(n)
+	for c := n.FirstChild(); c != nil; c = c.NextSibling() {
+		switch c.Kind() {
+		case ast.KindParagraph, ast.KindTextBlock:
+			text, err := renderutil.RenderChildrenToBytes(source, r.funcs, c)
+			if err != nil {
+				return ast.WalkStop, err
+			}
+			writeLine(w, marker, text)
+			marker = "* "
+		default:
+			contents, err := renderutil.RenderToBytes(source, r.funcs, c)
+			if err != nil {
+				return ast.WalkStop, err
+			}
+			if len(contents) != 0 {
+				_, _ = w.Write(contents)
+				_ = w.WriteByte('\n')
+			}
+		}
+	}
+	return ast.WalkSkipChildren, nil
+}
+
+func (r *Renderer) renderParagraph(
+	w util.BufWriter, source []byte, n ast.Node, entering bool) (ast.WalkStatus, error) {
+	if !entering {
+		return ast.WalkContinue, nil
+	}
+	r.writeBlockSeparator(w, n)
+	// paragraphs that consist of an image are written as link lines.
+	if n.ChildCount() == 1 && n.FirstChild().Kind() == ast.KindImage && isTopLevel(n) {
+		r.writeLinks(w, source, n)
+		return ast.WalkSkipChildren, nil
+	}
+	text, err := renderutil.RenderChildrenToBytes(source, r.funcs, n)
+	if err != nil {
+		return ast.WalkStop, err
+	}
+	writeTextLines(w, text)
+	r.writeLinks(w, source, n)
+	return ast.WalkSkipChildren, nil
+}
+
+func (r *Renderer) renderThematicBreak(
+	w util.BufWriter, source []byte, n ast.Node, entering bool) (ast.WalkStatus, error) {
+	if entering {
+		r.writeBlockSeparator(w, n)
+		_, _ = w.WriteString("---\n")
+	}
+	return ast.WalkContinue, nil
+}
+
+func (r *Renderer) renderAutoLink(
+	w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	if entering {
+		_, _ = w.Write(node.(*ast.AutoLink).Label(source))
+	}
+	return ast.WalkContinue, nil
+}
+
+func (r *Renderer) renderCodeSpan(w util.BufWriter, source []byte, n ast.Node, entering bool) (ast.WalkStatus, error) {
+	if !entering {
+		return ast.WalkContinue, nil
+	}
+	for c := n.FirstChild(); c != nil; c = c.NextSibling() {
+		segment := c.(*ast.Text).Segment
+		value := segment.Value(source)
+		if bytes.HasSuffix(value, []byte("\n")) {
+			_, _ = w.Write(value[:len(value)-1])
+			_ = w.WriteByte(' ')
+		} else {
+			_, _ = w.Write(value)
+		}
+	}
+	return ast.WalkSkipChildren, nil
+}
+
+// renderImage renders images as alternative texts.
+func (r *Renderer) renderImage(w util.BufWriter, source []byte, n ast.Node, entering bool) (ast.WalkStatus, error) {
+	if entering {
+		_, _ = w.Write(plainText(n, source))
+	}
+	return ast.WalkSkipChildren, nil
+}
+
+func (r *Renderer) renderText(w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	if !entering {
+		return ast.WalkContinue, nil
+	}
+	n := node.(*ast.Text)
+	segment := n.Segment
+	value := segment.Value(source)
+	if n.IsRaw() {
+		_, _ = w.Write(value)
+	} else {
+		_, _ = w.Write(renderutil.ResolveText(value))
+	}
+	if n.HardLineBreak() {
+		_ = w.WriteByte('\n')
+	} else if n.SoftLineBreak() {
+		// gemtext clients wrap long lines.
+		_ = w.WriteByte(' ')
+	}
+	return ast.WalkContinue, nil
+}
+
+func (r *Renderer) renderString(
+	w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	if !entering {
+		return ast.WalkContinue, nil
+	}
+	n := node.(*ast.String)
+	if n.IsCode() || n.IsRaw() {
+		_, _ = w.Write(n.Value)
+	} else {
+		_, _ = w.Write(renderutil.ResolveText(n.Value))
+	}
+	return ast.WalkContinue, nil
+}
+
+// plainText returns a text of the given node without any markups.
+func plainText(n ast.Node, source []byte) []byte {
+	var buf bytes.Buffer
+	_ = ast.Walk(n, func(c ast.Node, entering bool) (ast.WalkStatus, error) {
+		if !entering {
+			return ast.WalkContinue, nil
+		}
+		s := ast.SourceOf(c, source)
+		switch typed := c.(type) {
+		case *ast.Text:
+			segment := typed.Segment
+			value := segment.Value(s)
+			if typed.IsRaw() || (c.Parent() != nil && c.Parent().Kind() == ast.KindCodeSpan) {
+				_, _ = buf.Write(value)
+			} else {
+				_, _ = buf.Write(renderutil.ResolveText(value))
+			}
+			if typed.SoftLineBreak() || typed.HardLineBreak() {
+				_ = buf.WriteByte(' ')
+			}
+		case *ast.String:
+			if typed.IsCode() || typed.IsRaw() {
+				_, _ = buf.Write(typed.Value)
+			} else {
+				_, _ = buf.Write(renderutil.ResolveText(typed.Value))
+			}
+		case *ast.AutoLink:
+			_, _ = buf.Write(typed.Label(s))
+		case *east.TaskCheckBox:
+			_, _ = buf.WriteString(taskCheckBox(typed))
+		case *east.FootnoteLink:
+			_, _ = fmt.Fprintf(&buf, "[%d]", typed.Index)
+		case *ast.RawHTML, *east.FootnoteBacklink:
+			return ast.WalkSkipChildren, nil
+		default:
+			if c.Type() == ast.TypeBlock && c != n && c.PreviousSibling() != nil {
+				_ = buf.WriteByte(' ')
+			}
+		}
+		return ast.WalkContinue, nil
+	})
+	return bytes.TrimSpace(buf.Bytes())
+}
@@ -0,0 +1,118 @@
+package gemtext_test
+
+import (
+	"testing"
+
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/extension"
+	"github.com/yuin/goldmark/renderer"
+	"github.com/yuin/goldmark/renderer/gemtext"
+	"github.com/yuin/goldmark/testutil"
+	"github.com/yuin/goldmark/util"
+)
+
+func TestRenderer(t *testing.T) {
+	markdown := goldmark.New(
+		goldmark.WithRenderer(renderer.NewRenderer(
+			renderer.WithNodeRenderers(util.Prioritized(gemtext.NewRenderer(), 100)),
+		)),
+		goldmark.WithExtensions(
+			extension.GFM,
+			extension.DefinitionList,
+			extension.Footnote,
+		),
+	)
+	testutil.DoTestCase(
+		markdown,
+		testutil.MarkdownTestCase{
+			No:          1,
+			Description: "Core nodes and link lines",
+			Markdown: `# Release *1.2.0*
+
+#### Deep heading
+
+Hello **bold _it_** ` + "`code`" + ` [site](http://example.com/a) [top](#release-120) ![logo](/logo.png) <http://example.com>  
+=> not a link
+
+![standalone](/i.png)
+
+- a [x](http://x.example)
+  1. one
+  2. two
+- b
+
+> quote
+> > nested
+
+` + "```go title\nfmt.Println(\"x\")\n```" + `
+
+***
+`,
+			Expected: `# Release 1.2.0
+
+### Deep heading
+
+Hello bold it code site top logo http://example.com
+ => not a link
+=> http://example.com/a site
+=> /logo.png logo
+=> http://example.com
+
+=> /i.png standalone
+
+* a x
+* 1. one
+* 2. two
+* b
+=> http://x.example x
+
+> quote
+>
+> > nested
+
+` + "```go title\nfmt.Println(\"x\")\n```" + `
+
+---
+`,
+		},
+		t,
+	)
+
+	testutil.DoTestCase(
+		markdown,
+		testutil.MarkdownTestCase{
+			No:          2,
+			Description: "Extension nodes",
+			Markdown: `- [x] done
+- [ ] ~~todo~~
+
+| Name | Qty |
+|:-|-:|
+| apple | 1 |
+| kiwi | 12[^1] |
+
+Term
+: description
+
+[^1]: the [note](http://n.example).
+`,
+			Expected: `* [x] done
+* [ ] todo
+
+` + "```" + `
+Name  |   Qty
+------|------
+apple |     1
+kiwi  | 12[1]
+` + "```" + `
+
+Term
+* description
+
+[1] the note.
+=> http://n.example note
+`,
+		},
+		t,
+	)
+}
@@ -0,0 +1,150 @@
+// Package renderutil provides functions shared by the renderers that render
+// nodes as formats other than HTML.
+package renderutil
+
+import (
+	"bufio"
+	"bytes"
+
+	"github.com/yuin/goldmark/ast"
+	east "github.com/yuin/goldmark/extension/ast"
+	"github.com/yuin/goldmark/renderer"
+	"github.com/yuin/goldmark/util"
+)
+
+// ResolveText resolves references and unescapes backslash escaped characters.
+func ResolveText(source []byte) []byte {
+	source = util.UnescapePunctuations(source)
+	source = util.ResolveNumericReferences(source)
+	return util.ResolveEntityNames(source)
+}
+
+// Render renders the given nodes with the given functions.
+// Nodes that have no functions are not rendered, but their children are.
+func Render(w util.BufWriter, source []byte,
+	funcs map[ast.NodeKind]renderer.NodeRendererFunc, nodes ...ast.Node) error {
+	for _, c := range nodes {
+		err := ast.Walk(c, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+			f := funcs[n.Kind()]
+			if f == nil {
+				return ast.WalkContinue, nil
+			}
+			return f(w, ast.SourceOf(n, source), n, entering)
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// RenderToBytes renders the given nodes into a byte slice.
+// Leading and trailing newlines are trimmed.
+func RenderToBytes(source []byte,
+	funcs map[ast.NodeKind]renderer.NodeRendererFunc, nodes ...ast.Node) ([]byte, error) {
+	var buf bytes.Buffer
+	w := bufio.NewWriter(&buf)
+	if err := Render(w, source, funcs, nodes...); err != nil {
+		return nil, err
+	}
+	if err := w.Flush(); err != nil {
+		return nil, err
+	}
+	return bytes.Trim(buf.Bytes(), "\n"), nil
+}
+
+// RenderChildrenToBytes renders children of the given node into a byte slice.
+// Leading and trailing newlines are trimmed.
+func RenderChildrenToBytes(source []byte,
+	funcs map[ast.NodeKind]renderer.NodeRendererFunc, n ast.Node) ([]byte, error) {
+	var nodes []ast.Node
+	for c := n.FirstChild(); c != nil; c = c.NextSibling() {
+		nodes = append(nodes, c)
+	}
+	return RenderToBytes(source, funcs, nodes...)
+}
+
+// PreviousBlock returns the previous sibling of the given block.
+// Siblings of the given kinds are skipped because they are not rendered.
+func PreviousBlock(n ast.Node, skip ...ast.NodeKind) ast.Node {
+	prev := n.PreviousSibling()
+	for prev != nil && hasKind(prev, skip) {
+		prev = prev.PreviousSibling()
+	}
+	return prev
+}
+
+func hasKind(n ast.Node, kinds []ast.NodeKind) bool {
+	for _, kind := range kinds {
+		if n.Kind() == kind {
+			return true
+		}
+	}
+	return false
+}
+
+// IsLineStart returns true if the given inline node is rendered at the
+// beginning of a line.
+//
+// A node that has a previous sibling is at the beginning of a line if the
+// sibling ends with a hard line break, or a soft line break when
+// softLineBreak is true. Otherwise, the node is at the beginning of a line
+// if its parent is: inline parents must be transparent, that is, they write
+// nothing before their children, and block parents must satisfy block.
+// nil functions accept any node.
+func IsLineStart(n ast.Node, softLineBreak bool, transparent, block func(ast.Node) bool) bool {
+	for {
+		if prev := n.PreviousSibling(); prev != nil {
+			t, ok := prev.(*ast.Text)
+			return ok && (t.HardLineBreak() || (softLineBreak && t.SoftLineBreak()))
+		}
+		n = n.Parent()
+		if n == nil {
+			return true
+		}
+		if n.Type() == ast.TypeBlock {
+			return block == nil || block(n)
+		}
+		if transparent != nil && !transparent(n) {
+			return false
+		}
+	}
+}
+
+// TableRowLayout returns columns of the cells in the given row and columns
+// that are occupied by cells that span rows above the given row.
+func TableRowLayout(row ast.Node) (map[ast.Node]int, []bool) {
+	table := row.Parent().(*east.Table)
+	remaining := make([]int, len(table.Alignments))
+	for r := table.FirstChild(); r != nil; r = r.NextSibling() {
+		if r.Kind() != east.KindTableRow && r.Kind() != east.KindTableHeader {
+			continue
+		}
+		occupied := make([]bool, len(remaining))
+		for i, v := range remaining {
+			occupied[i] = v > 0
+		}
+		columns := map[ast.Node]int{}
+		col := 0
+		for c := r.FirstChild(); c != nil; c = c.NextSibling() {
+			for col < len(occupied) && occupied[col] {
+				col++
+			}
+			columns[c] = col
+			cell := c.(*east.TableCell)
+			for k := col; k < col+cell.ColSpan && k < len(remaining); k++ {
+				remaining[k] = cell.RowSpan
+			}
+			col += cell.ColSpan
+		}
+		if r == row {
+			return columns, occupied
+		}
+		for i := range remaining {
+			if remaining[i] > 0 {
+				remaining[i]--
+			}
+		}
+	}
+	return map[ast.Node]int{}, make([]bool, len(remaining))
+}
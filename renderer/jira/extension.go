@@ -0,0 +1,181 @@
+package jira
+
+import (
+	"fmt"
+
+	"github.com/yuin/goldmark/ast"
+	east "github.com/yuin/goldmark/extension/ast"
+	"github.com/yuin/goldmark/util"
+)
+
+func (r *Renderer) renderTable(
+	w util.BufWriter, source []byte, n ast.Node, entering bool) (ast.WalkStatus, error) {
+	if entering && !inTableCell(n) {
+		r.writeBlockSeparator(w, n)
+	}
+	return ast.WalkContinue, nil
+}
+
+// renderCaption renders captions as lines before tables.
+func (r *Renderer) renderCaption(
+	w util.BufWriter, source []byte, n ast.Node, entering bool) (ast.WalkStatus, error) {
+	if inTableCell(n) {
+		if entering {
+			writeCellLineBreak(w, n)
+		}
+		return ast.WalkContinue, nil
+	}
+	if !entering {
+		_ = w.WriteByte('\n')
+	}
+	return ast.WalkContinue, nil
+}
+
+func tableCellSeparator(n ast.Node) string {
+	if n.Kind() == east.KindTableHeader {
+		return "||"
+	}
+	return "|"
+}
+
+func (r *Renderer) renderTableRow(
+	w util.BufWriter, source []byte, n ast.Node, entering bool) (ast.WalkStatus, error) {
+	// tables in table cells are written as lines of rows.
+	if inTableCell(n) {
+		if entering {
+			writeCellLineBreak(w, n)
+		}
+		return ast.WalkContinue, nil
+	}
+	if !entering {
+		_, _ = w.WriteString(tableCellSeparator(n))
+		_ = w.WriteByte('\n')
+	}
+	return ast.WalkContinue, nil
+}
+
+// renderTableCell renders cells. The wiki markup does not support spans,
+// so columns spanned by cells are rendered as empty cells.
+func (r *Renderer) renderTableCell(
+	w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	n := node.(*east.TableCell)
+	if inTableCell(n) {
+		if entering && n.PreviousSibling() != nil {
+			_, _ = w.WriteString(", ")
+		}
+		return ast.WalkContinue, nil
+	}
+	separator := tableCellSeparator(n.Parent())
+	if entering {
+		_, _ = w.WriteString(separator)
+		if !n.HasChildren() {
+			// empty cells must have spaces.
+			_ = w.WriteByte(' ')
+		}
+	} else {
+		for i := 1; i < n.ColSpan; i++ {
+			_, _ = w.WriteString(separator)
+			_ = w.WriteByte(' ')
+		}
+	}
+	return ast.WalkContinue, nil
+}
+
+func (r *Renderer) renderStrikethrough(
+	w util.BufWriter, source []byte, n ast.Node, entering bool) (ast.WalkStatus, error) {
+	_ = w.WriteByte('-')
+	return ast.WalkContinue, nil
+}
+
+// renderTaskCheckBox renders check boxes as icons.
+func (r *Renderer) renderTaskCheckBox(
+	w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	if !entering {
+		return ast.WalkContinue, nil
+	}
+	if node.(*east.TaskCheckBox).IsChecked {
+		_, _ = w.WriteString("(/) ")
+	} else {
+		_, _ = w.WriteString("(x) ")
+	}
+	return ast.WalkContinue, nil
+}
+
+func (r *Renderer) renderDefinitionList(
+	w util.BufWriter, source []byte, n ast.Node, entering bool) (ast.WalkStatus, error) {
+	if entering && !inTableCell(n) {
+		r.writeBlockSeparator(w, n)
+	}
+	return ast.WalkContinue, nil
+}
+
+// renderDefinitionTerm renders terms as bold lines.
+func (r *Renderer) renderDefinitionTerm(
+	w util.BufWriter, source []byte, n ast.Node, entering bool) (ast.WalkStatus, error) {
+	if inTableCell(n) {
+		if entering {
+			writeCellLineBreak(w, n)
+		}
+		_ = w.WriteByte('*')
+		return ast.WalkContinue, nil
+	}
+	if entering {
+		r.writeBlockSeparator(w, n)
+		_ = w.WriteByte('*')
+	} else {
+		_, _ = w.WriteString("*\n")
+	}
+	return ast.WalkContinue, nil
+}
+
+func (r *Renderer) renderFootnoteLink(
+	w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	if entering {
+		_, _ = fmt.Fprintf(w, "^%d^", node.(*east.FootnoteLink).Index)
+	}
+	return ast.WalkContinue, nil
+}
+
+func (r *Renderer) renderFootnoteList(
+	w util.BufWriter, source []byte, n ast.Node, entering bool) (ast.WalkStatus, error) {
+	if entering {
+		r.writeBlockSeparator(w, n)
+		_, _ = w.WriteString("----\n")
+	}
+	return ast.WalkContinue, nil
+}
+
+func (r *Renderer) renderFootnote(
+	w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	if entering {
+		r.writeBlockSeparator(w, node)
+		_, _ = fmt.Fprintf(w, "^%d^ ", node.(*east.Footnote).Index)
+	}
+	return ast.WalkContinue, nil
+}
+
+func (r *Renderer) renderFigure(
+	w util.BufWriter, source []byte, n ast.Node, entering bool) (ast.WalkStatus, error) {
+	if inTableCell(n) {
+		if entering {
+			writeCellLineBreak(w, n)
+		}
+		return ast.WalkContinue, nil
+	}
+	if entering {
+		r.writeBlockSeparator(w, n)
+	} else {
+		_ = w.WriteByte('\n')
+	}
+	return ast.WalkContinue, nil
+}
+
+func (r *Renderer) renderFigureCaption(
+	w util.BufWriter, source []byte, n ast.Node, entering bool) (ast.WalkStatus, error) {
+	if entering && inTableCell(n) {
+		_, _ = w.WriteString(cellLineBreak)
+	} else if entering {
+		_, _ = w.WriteString("\\\\\n")
+	}
+	return ast.WalkContinue, nil
+}
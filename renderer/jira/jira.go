@@ -0,0 +1,496 @@
+// Package jira implements renderer that outputs the wiki markup of Atlassian
+// products like Jira and Confluence.
+package jira
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/yuin/goldmark/ast"
+	east "github.com/yuin/goldmark/extension/ast"
+	"github.com/yuin/goldmark/renderer"
+	"github.com/yuin/goldmark/renderer/internal/renderutil"
+	"github.com/yuin/goldmark/util"
+)
+
+// A Renderer struct is an implementation of renderer.NodeRenderer that renders
+// nodes as the wiki markup.
+type Renderer struct {
+}
+
+// NewRenderer returns a new Renderer.
+func NewRenderer() renderer.NodeRenderer {
+	return &Renderer{}
+}
+
+// RegisterFuncs implements NodeRenderer.RegisterFuncs .
+func (r *Renderer) RegisterFuncs(reg renderer.NodeRendererFuncRegisterer) {
+	// blocks
+
+	reg.Register(ast.KindDocument, renderContents)
+	reg.Register(ast.KindHeading, r.renderHeading)
+	reg.Register(ast.KindBlockquote, r.renderBlockquote)
+	reg.Register(ast.KindCodeBlock, r.renderCodeBlock)
+	reg.Register(ast.KindFencedCodeBlock, r.renderCodeBlock)
+	reg.Register(ast.KindHTMLBlock, renderNothing)
+	reg.Register(ast.KindList, r.renderList)
+	reg.Register(ast.KindListItem, r.renderListItem)
+	reg.Register(ast.KindParagraph, r.renderParagraph)
+	reg.Register(ast.KindTextBlock, r.renderParagraph)
+	reg.Register(ast.KindThematicBreak, r.renderThematicBreak)
+	reg.Register(ast.KindLinkReferenceDefinition, renderNothing)
+
+	// inlines
+
+	reg.Register(ast.KindAutoLink, r.renderAutoLink)
+	reg.Register(ast.KindCodeSpan, r.renderCodeSpan)
+	reg.Register(ast.KindEmphasis, r.renderEmphasis)
+	reg.Register(ast.KindImage, r.renderImage)
+	reg.Register(ast.KindLink, r.renderLink)
+	reg.Register(ast.KindRawHTML, renderNothing)
+	reg.Register(ast.KindText, r.renderText)
+	reg.Register(ast.KindString, r.renderString)
+
+	// extensions
+
+	reg.Register(east.KindTable, r.renderTable)
+	reg.Register(east.KindCaption, r.renderCaption)
+	reg.Register(east.KindTableHeader, r.renderTableRow)
+	reg.Register(east.KindTableRow, r.renderTableRow)
+	reg.Register(east.KindTableCell, r.renderTableCell)
+	reg.Register(east.KindStrikethrough, r.renderStrikethrough)
+	reg.Register(east.KindTaskCheckBox, r.renderTaskCheckBox)
+	reg.Register(east.KindDefinitionList, r.renderDefinitionList)
+	reg.Register(east.KindDefinitionTerm, r.renderDefinitionTerm)
+	reg.Register(east.KindDefinitionDescription, renderContents)
+	reg.Register(east.KindFootnoteLink, r.renderFootnoteLink)
+	reg.Register(east.KindFootnoteBacklink, renderNothing)
+	reg.Register(east.KindFootnote, r.renderFootnote)
+	reg.Register(east.KindFootnoteList, r.renderFootnoteList)
+	reg.Register(east.KindAbbreviation, renderContents)
+	reg.Register(east.KindAbbreviationDefinition, renderNothing)
+	reg.Register(east.KindFigure, r.renderFigure)
+	reg.Register(east.KindFigureCaption, r.renderFigureCaption)
+}
+
+func renderNothing(_ util.BufWriter, _ []byte, _ ast.Node, _ bool) (ast.WalkStatus, error) {
+	return ast.WalkSkipChildren, nil
+}
+
+func renderContents(_ util.BufWriter, _ []byte, _ ast.Node, _ bool) (ast.WalkStatus, error) {
+	return ast.WalkContinue, nil
+}
+
+// isInlineContainer returns true if the given node is a block that
+// can contain only a line of texts like list items.
+func isInlineContainer(n ast.Node) bool {
+	return n != nil && (n.Kind() == ast.KindListItem || n.Kind() == east.KindTableCell)
+}
+
+// writeBlockSeparator writes a blank line before the given block unless the
+// block is in an inline container like a table cell.
+func (r *Renderer) writeBlockSeparator(w util.BufWriter, n ast.Node) {
+	if isInlineContainer(n.Parent()) {
+		return
+	}
+	if renderutil.PreviousBlock(n, ast.KindHTMLBlock, ast.KindLinkReferenceDefinition) != nil {
+		_ = w.WriteByte('\n')
+	}
+}
+
+// cellLineBreak is a line break in table cells.
+const cellLineBreak = ` \\ `
+
+// inTableCell returns true if the given node is in a table cell.
+// The wiki markup does not support multiple lines in cells, so blocks in
+// cells are written in a line and separated by line breaks.
+func inTableCell(n ast.Node) bool {
+	for p := n.Parent(); p != nil; p = p.Parent() {
+		if p.Kind() == east.KindTableCell {
+			return true
+		}
+	}
+	return false
+}
+
+// writeCellLineBreak writes a line break before the given block in a table
+// cell unless the block is at the beginning of the cell or of a list item.
+func writeCellLineBreak(w util.BufWriter, n ast.Node) {
+	for ; n.Kind() != east.KindTableCell; n = n.Parent() {
+		if renderutil.PreviousBlock(n, ast.KindHTMLBlock, ast.KindLinkReferenceDefinition, ast.KindThematicBreak) != nil {
+			_, _ = w.WriteString(cellLineBreak)
+			return
+		}
+		if n.Parent().Kind() == ast.KindListItem {
+			return
+		}
+	}
+}
+
+func (r *Renderer) renderHeading(
+	w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	n := node.(*ast.Heading)
+	// headings in table cells are written as bold texts.
+	if inTableCell(n) {
+		if entering {
+			writeCellLineBreak(w, n)
+		}
+		_ = w.WriteByte('*')
+		return ast.WalkContinue, nil
+	}
+	if entering {
+		r.writeBlockSeparator(w, n)
+		_, _ = fmt.Fprintf(w, "h%d. ", n.Level)
+		if id, ok := n.AttributeString("id"); ok {
+			if value, ok := id.([]byte); ok {
+				_, _ = fmt.Fprintf(w, "{anchor:%s}", value)
+			}
+		}
+	} else {
+		_ = w.WriteByte('\n')
+	}
+	return ast.WalkContinue, nil
+}
+
+func (r *Renderer) renderBlockquote(
+	w util.BufWriter, source []byte, n ast.Node, entering bool) (ast.WalkStatus, error) {
+	if inTableCell(n) {
+		return ast.WalkContinue, nil
+	}
+	if entering {
+		r.writeBlockSeparator(w, n)
+		_, _ = w.WriteString("{quote}\n")
+	} else {
+		_, _ = w.WriteString("{quote}\n")
+	}
+	return ast.WalkContinue, nil
+}
+
+func (r *Renderer) renderCodeBlock(
+	w util.BufWriter, source []byte, n ast.Node, entering bool) (ast.WalkStatus, error) {
+	if !entering {
+		return ast.WalkContinue, nil
+	}
+	// lines of code blocks in table cells are written as monospaced texts.
+	if inTableCell(n) {
+		writeCellLineBreak(w, n)
+		for i := range n.Lines().Len() {
+			line := n.Lines().At(i)
+			if i != 0 {
+				_, _ = w.WriteString(cellLineBreak)
+			}
+			_, _ = w.WriteString("{{")
+			writeEscaped(w, util.TrimRightSpace(line.Value(source)), false)
+			_, _ = w.WriteString("}}")
+		}
+		return ast.WalkSkipChildren, nil
+	}
+	r.writeBlockSeparator(w, n)
+	_, _ = w.WriteString("{code")
+	if fcb, ok := n.(*ast.FencedCodeBlock); ok {
+		if language := fcb.Language(source); language != nil {
+			_ = w.WriteByte(':')
+			_, _ = w.Write(language)
+		}
+	}
+	_, _ = w.WriteString("}\n")
+	l := n.Lines().Len()
+	for i := range l {
+		line := n.Lines().At(i)
+		_, _ = w.Write(line.Value(source))
+	}
+	_, _ = w.WriteString("{code}\n")
+	return ast.WalkSkipChildren, nil
+}
+
+func (r *Renderer) renderList(w util.BufWriter, source []byte, n ast.Node, entering bool) (ast.WalkStatus, error) {
+	if entering && !inTableCell(n) {
+		r.writeBlockSeparator(w, n)
+	}
+	return ast.WalkContinue, nil
+}
+
+// listPrefix returns a prefix of the given list item like '*#'.
+func listPrefix(n ast.Node) string {
+	prefix := ""
+	for p := n.Parent(); p != nil; p = p.Parent() {
+		if list, ok := p.(*ast.List); ok {
+			if list.IsOrdered() {
+				prefix = "#" + prefix
+			} else {
+				prefix = "*" + prefix
+			}
+		}
+	}
+	return prefix
+}
+
+func (r *Renderer) renderListItem(
+	w util.BufWriter, source []byte, n ast.Node, entering bool) (ast.WalkStatus, error) {
+	// list items in table cells are written as lines that start with markers.
+	if inTableCell(n) {
+		if entering {
+			writeCellLineBreak(w, n)
+			if list := n.Parent().(*ast.List); list.IsOrdered() {
+				index := 0
+				for c := n.PreviousSibling(); c != nil; c = c.PreviousSibling() {
+					index++
+				}
+				_, _ = fmt.Fprintf(w, "%d. ", list.Start+index)
+			} else {
+				_, _ = w.WriteString("- ")
+			}
+		}
+		return ast.WalkContinue, nil
+	}
+	if entering {
+		_, _ = w.WriteString(listPrefix(n))
+		_ = w.WriteByte(' ')
+	} else if !n.HasChildren() {
+		_ = w.WriteByte('\n')
+	}
+	return ast.WalkContinue, nil
+}
+
+func isParagraph(n ast.Node) bool {
+	return n != nil && (n.Kind() == ast.KindParagraph || n.Kind() == ast.KindTextBlock)
+}
+
+func (r *Renderer) renderParagraph(
+	w util.BufWriter, source []byte, n ast.Node, entering bool) (ast.WalkStatus, error) {
+	if inTableCell(n) {
+		if entering {
+			writeCellLineBreak(w, n)
+		}
+		return ast.WalkContinue, nil
+	}
+	// list items can not contain multiple lines,
+	// so paragraphs are separated by line breaks.
+	if isInlineContainer(n.Parent()) {
+		if entering && isParagraph(n.PreviousSibling()) {
+			_, _ = w.WriteString(` \\ `)
+		}
+		if !entering && !isParagraph(n.NextSibling()) && n.Parent().Kind() == ast.KindListItem {
+			_ = w.WriteByte('\n')
+		}
+		return ast.WalkContinue, nil
+	}
+	if entering {
+		r.writeBlockSeparator(w, n)
+	} else {
+		_ = w.WriteByte('\n')
+	}
+	return ast.WalkContinue, nil
+}
+
+func (r *Renderer) renderThematicBreak(
+	w util.BufWriter, source []byte, n ast.Node, entering bool) (ast.WalkStatus, error) {
+	if entering && !inTableCell(n) {
+		r.writeBlockSeparator(w, n)
+		_, _ = w.WriteString("----\n")
+	}
+	return ast.WalkContinue, nil
+}
+
+func (r *Renderer) renderAutoLink(
+	w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	if !entering {
+		return ast.WalkContinue, nil
+	}
+	n := node.(*ast.AutoLink)
+	_ = w.WriteByte('[')
+	if n.AutoLinkType == ast.AutoLinkEmail {
+		_, _ = w.WriteString("mailto:")
+	}
+	writeURL(w, n.URL(source))
+	_ = w.WriteByte(']')
+	return ast.WalkContinue, nil
+}
+
+func (r *Renderer) renderCodeSpan(w util.BufWriter, source []byte, n ast.Node, entering bool) (ast.WalkStatus, error) {
+	if !entering {
+		return ast.WalkContinue, nil
+	}
+	_, _ = w.WriteString("{{")
+	for c := n.FirstChild(); c != nil; c = c.NextSibling() {
+		segment := c.(*ast.Text).Segment
+		value := segment.Value(source)
+		if len(value) != 0 && value[len(value)-1] == '\n' {
+			value = append(value[:len(value)-1:len(value)-1], ' ')
+		}
+		writeEscaped(w, value, false)
+	}
+	_, _ = w.WriteString("}}")
+	return ast.WalkSkipChildren, nil
+}
+
+func (r *Renderer) renderEmphasis(
+	w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	n := node.(*ast.Emphasis)
+	if n.Level == 2 {
+		_ = w.WriteByte('*')
+	} else {
+		_ = w.WriteByte('_')
+	}
+	return ast.WalkContinue, nil
+}
+
+// writeURL writes the given URL with escaping characters that terminate links.
+func writeURL(w util.BufWriter, url []byte) {
+	for _, c := range url {
+		switch c {
+		case '|', ']', ' ':
+			_, _ = fmt.Fprintf(w, "%%%02X", c)
+		default:
+			_ = w.WriteByte(c)
+		}
+	}
+}
+
+func (r *Renderer) renderLink(w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	n := node.(*ast.Link)
+	if entering {
+		_ = w.WriteByte('[')
+		if !n.HasChildren() {
+			writeURL(w, n.Destination)
+			_ = w.WriteByte(']')
+			return ast.WalkSkipChildren, nil
+		}
+	} else {
+		_ = w.WriteByte('|')
+		writeURL(w, n.Destination)
+		_ = w.WriteByte(']')
+	}
+	return ast.WalkContinue, nil
+}
+
+func (r *Renderer) renderImage(w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	if !entering {
+		return ast.WalkContinue, nil
+	}
+	n := node.(*ast.Image)
+	_ = w.WriteByte('!')
+	for _, c := range n.Destination {
+		switch c {
+		case '|', '!', ' ':
+			_, _ = fmt.Fprintf(w, "%%%02X", c)
+		default:
+			_ = w.WriteByte(c)
+		}
+	}
+	_ = w.WriteByte('!')
+	return ast.WalkSkipChildren, nil
+}
+
+// isLineStart returns true if the given inline node is rendered at the
+// beginning of a line. Blocks in inline containers like list items and
+// blocks in table cells do not start lines.
+func isLineStart(n ast.Node) bool {
+	return renderutil.IsLineStart(n, false, func(p ast.Node) bool {
+		return p.Kind() == ast.KindLink
+	}, func(b ast.Node) bool {
+		return !isInlineContainer(b.Parent()) && !inTableCell(b)
+	})
+}
+
+func (r *Renderer) renderText(w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	if !entering {
+		return ast.WalkContinue, nil
+	}
+	n := node.(*ast.Text)
+	segment := n.Segment
+	value := segment.Value(source)
+	if !n.IsRaw() {
+		value = renderutil.ResolveText(value)
+	}
+	writeEscaped(w, value, isLineStart(n))
+	if n.HardLineBreak() && inTableCell(n) {
+		_, _ = w.WriteString(cellLineBreak)
+	} else if n.HardLineBreak() {
+		_, _ = w.WriteString("\\\\\n")
+	} else if n.SoftLineBreak() {
+		// newlines are rendered as line breaks.
+		_ = w.WriteByte(' ')
+	}
+	return ast.WalkContinue, nil
+}
+
+func (r *Renderer) renderString(
+	w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	if !entering {
+		return ast.WalkContinue, nil
+	}
+	n := node.(*ast.String)
+	value := n.Value
+	if !n.IsCode() && !n.IsRaw() {
+		value = renderutil.ResolveText(value)
+	}
+	writeEscaped(w, value, isLineStart(n))
+	return ast.WalkContinue, nil
+}
+
+// specialChars are characters that start links, images, macros and tables.
+var specialChars = [256]bool{
+	'[': true, ']': true, '{': true, '}': true, '|': true, '!': true,
+}
+
+// effectChars are characters that start text effects like '*strong*'.
+// Text effects do not start in words.
+var effectChars = [256]bool{
+	'*': true, '_': true, '-': true, '+': true, '^': true, '~': true, '?': true,
+}
+
+// emoticons are emoticons that start with the key character.
+var emoticons = map[byte][]string{
+	':': {":)", ":(", ":P", ":D"},
+	';': {";)"},
+	'(': {
+		"(y)", "(n)", "(i)", "(/)", "(x)", "(!)", "(+)", "(-)", "(?)", "(on)", "(off)",
+		"(*)", "(*r)", "(*g)", "(*b)", "(*y)", "(flag)", "(flagoff)",
+	},
+}
+
+// isEmoticon returns true if the given text starts with an emoticon.
+func isEmoticon(value []byte) bool {
+	for _, emoticon := range emoticons[value[0]] {
+		if len(value) >= len(emoticon) && bytes.EqualFold(value[:len(emoticon)], []byte(emoticon)) {
+			return true
+		}
+	}
+	return false
+}
+
+func isWordChar(c byte) bool {
+	return util.IsAlphaNumeric(c) || c >= 0x80
+}
+
+// writeEscaped writes the given text with escaping characters that have
+// special meanings in the wiki markup. lineStart is true if the text
+// starts at the beginning of a line.
+func writeEscaped(w util.BufWriter, value []byte, lineStart bool) {
+	for i, c := range value {
+		if c == '\\' {
+			// '\\' is a forced line break, so backslashes are written as
+			// character references.
+			_, _ = w.WriteString("&#92;")
+			continue
+		}
+		// emoticons like '(x)' are rendered as icons, so their first
+		// characters are written as character references.
+		if isEmoticon(value[i:]) {
+			_, _ = fmt.Fprintf(w, "&#%d;", c)
+			continue
+		}
+		escape := specialChars[c]
+		if effectChars[c] {
+			escape = i == 0 || i == len(value)-1 || !isWordChar(value[i-1]) || !isWordChar(value[i+1])
+		}
+		if c == '#' {
+			escape = i == 0 && lineStart
+		}
+		if escape {
+			_ = w.WriteByte('\\')
+		}
+		_ = w.WriteByte(c)
+	}
+}
@@ -0,0 +1,156 @@
+package jira_test
+
+import (
+	"testing"
+
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/extension"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/renderer"
+	"github.com/yuin/goldmark/renderer/jira"
+	"github.com/yuin/goldmark/testutil"
+	"github.com/yuin/goldmark/util"
+)
+
+func TestRenderer(t *testing.T) {
+	markdown := goldmark.New(
+		goldmark.WithRenderer(renderer.NewRenderer(
+			renderer.WithNodeRenderers(util.Prioritized(jira.NewRenderer(), 100)),
+		)),
+		goldmark.WithExtensions(
+			extension.GFM,
+			extension.Footnote,
+		),
+		goldmark.WithParserOptions(parser.WithAutoHeadingID()),
+	)
+	testutil.DoTestCase(
+		markdown,
+		testutil.MarkdownTestCase{
+			No:          1,
+			Description: "Core nodes and escaping",
+			Markdown: `# Release *1.2.0*
+
+Hello **bold _it_** ` + "`a_b {x}`" + ` [site](http://example.com/a|b) ![img](/i.png) <http://example.com>
+well-known a - b [brackets] !bang  
+\# not heading
+
+- a
+  1. x
+  2. y
+- b
+
+  second paragraph
+
+> quote
+
+` + "```go\nfmt.Println(\"x\")\n```" + `
+
+***
+`,
+			Expected: `h1. {anchor:release-120}Release _1.2.0_
+
+Hello *bold _it_* {{a_b \{x\}}} [site|http://example.com/a%7Cb] !/i.png! [http://example.com] well-known a \- b \[brackets\] \!bang\\
+\# not heading
+
+* a
+*# x
+*# y
+* b \\ second paragraph
+
+{quote}
+quote
+{quote}
+
+{code:go}
+fmt.Println("x")
+{code}
+
+----
+`,
+		},
+		t,
+	)
+
+	testutil.DoTestCase(
+		markdown,
+		testutil.MarkdownTestCase{
+			No:          2,
+			Description: "Extension nodes",
+			Markdown: `- [x] done
+- [ ] ~~todo~~
+
+| Name | Qty |
+|:-|-:|
+| apple | 1 |
+| | 12[^1] |
+
+[^1]: note.
+`,
+			Expected: `* (/) done
+* (x) -todo-
+
+||Name||Qty||
+|apple|1|
+| |12^1^|
+
+----
+^1^ note.
+`,
+		},
+		t,
+	)
+
+	testutil.DoTestCase(
+		markdown,
+		testutil.MarkdownTestCase{
+			No:          3,
+			Description: "Backslashes",
+			Markdown: `Text with \\ back ` + "`code $ & \\ {}`" + `
+`,
+			Expected: `Text with &#92; back {{code $ & &#92; \{\}}}
+`,
+		},
+		t,
+	)
+
+	testutil.DoTestCase(
+		markdown,
+		testutil.MarkdownTestCase{
+			No:          4,
+			Description: "Emoticons",
+			Markdown: `(x) (/) :) ;) (on) (y)es f(x)
+`,
+			Expected: `&#40;x) &#40;/) &#58;) &#59;) &#40;on) &#40;y)es f&#40;x)
+`,
+		},
+		t,
+	)
+
+	testutil.DoTestCase(
+		goldmark.New(
+			goldmark.WithRenderer(renderer.NewRenderer(
+				renderer.WithNodeRenderers(util.Prioritized(jira.NewRenderer(), 100)),
+			)),
+			goldmark.WithExtensions(
+				extension.NewTable(extension.WithTableGridTables()),
+			),
+		),
+		testutil.MarkdownTestCase{
+			No:          5,
+			Description: "Blocks in table cells",
+			Markdown: `+-------------+--------------+
+| # Title     | - one        |
+|             | - two        |
+| > quoted    |              |
+|             | ` + "```" + `          |
+|             | x := 1       |
+|             | y := 2       |
+|             | ` + "```" + `          |
++-------------+--------------+
+`,
+			Expected: `|*Title* \\ quoted|- one \\ - two \\ {{x := 1}} \\ {{y := 2}}|
+`,
+		},
+		t,
+	)
+}
@@ -0,0 +1,303 @@
+package latex
+
+import (
+	"fmt"
+
+	"github.com/yuin/goldmark/ast"
+	east "github.com/yuin/goldmark/extension/ast"
+	"github.com/yuin/goldmark/renderer/internal/renderutil"
+	"github.com/yuin/goldmark/util"
+)
+
+// hasTableBlockCells returns true if cells of the table contain blocks like lists.
+func hasTableBlockCells(table ast.Node) bool {
+	found := false
+	_ = ast.Walk(table, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		if entering && n.Type() == ast.TypeBlock && n.Kind() != ast.KindTextBlock &&
+			n.Kind() != ast.KindParagraph && n.Parent().Kind() == east.KindTableCell {
+			found = true
+			return ast.WalkStop, nil
+		}
+		return ast.WalkContinue, nil
+	})
+	return found
+}
+
+// tableColumnSpec returns a column specification like '{lcr}' of the table.
+func (r *Renderer) tableColumnSpec(table *east.Table) string {
+	// cells that contain blocks need paragraph columns.
+	paragraph := hasTableBlockCells(table)
+	spec := "{"
+	for _, alignment := range table.Alignments {
+		spec += tableAlignment(alignment, paragraph, 1, len(table.Alignments))
+	}
+	return spec + "}"
+}
+
+// tableAlignment returns a column specification of the given alignment.
+// If paragraph is true, tableAlignment returns a paragraph column that
+// spans the given number of columns.
+func tableAlignment(alignment east.Alignment, paragraph bool, span, columns int) string {
+	if paragraph {
+		command := `\raggedright`
+		switch alignment {
+		case east.AlignRight:
+			command = `\raggedleft`
+		case east.AlignCenter:
+			command = `\centering`
+		}
+		return fmt.Sprintf(">{%s\\arraybackslash}p{%.2f\\linewidth}", command, 0.9*float64(span)/float64(columns))
+	}
+	switch alignment {
+	case east.AlignRight:
+		return "r"
+	case east.AlignCenter:
+		return "c"
+	}
+	return "l"
+}
+
+func hasTableCaption(table ast.Node) bool {
+	return table.FirstChild() != nil && table.FirstChild().Kind() == east.KindCaption
+}
+
+func (r *Renderer) renderTable(
+	w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	n := node.(*east.Table)
+	if entering {
+		r.writeBlockSeparator(w, n)
+		switch {
+		case r.LongTable:
+			_, _ = fmt.Fprintf(w, "\\begin{longtable}%s\n", r.tableColumnSpec(n))
+			if !hasTableCaption(n) {
+				_, _ = w.WriteString("\\hline\n")
+			}
+		case hasTableCaption(n):
+			_, _ = w.WriteString("\\begin{table}[htbp]\n\\centering\n")
+		default:
+			_, _ = fmt.Fprintf(w, "\\begin{tabular}%s\n\\hline\n", r.tableColumnSpec(n))
+		}
+	} else {
+		_, _ = w.WriteString("\\hline\n")
+		switch {
+		case r.LongTable:
+			_, _ = w.WriteString("\\end{longtable}\n")
+		case hasTableCaption(n):
+			_, _ = w.WriteString("\\end{tabular}\n\\end{table}\n")
+		default:
+			_, _ = w.WriteString("\\end{tabular}\n")
+		}
+	}
+	return ast.WalkContinue, nil
+}
+
+func (r *Renderer) renderCaption(
+	w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	table := node.Parent().(*east.Table)
+	if entering {
+		_, _ = w.WriteString(`\caption{`)
+	} else {
+		_ = w.WriteByte('}')
+		r.writeLabel(w, table)
+		if r.LongTable {
+			_, _ = w.WriteString(" \\\\\n\\hline\n")
+		} else {
+			_, _ = fmt.Fprintf(w, "\n\\begin{tabular}%s\n\\hline\n", r.tableColumnSpec(table))
+		}
+	}
+	return ast.WalkContinue, nil
+}
+
+// writeMultirowCells writes empty cells for columns in [from, to) that are
+// occupied by '\multirow' cells in rows above.
+func writeMultirowCells(w util.BufWriter, occupied []bool, from, to int) {
+	for c := from; c < to && c < len(occupied); c++ {
+		if occupied[c] && c > 0 {
+			_, _ = w.WriteString(" & ")
+		}
+	}
+}
+
+func (r *Renderer) renderTableRow(
+	w util.BufWriter, source []byte, n ast.Node, entering bool) (ast.WalkStatus, error) {
+	if entering {
+		return ast.WalkContinue, nil
+	}
+	columns, occupied := renderutil.TableRowLayout(n)
+	end := 0
+	if last := n.LastChild(); last != nil {
+		end = columns[last] + last.(*east.TableCell).ColSpan
+	}
+	writeMultirowCells(w, occupied, end, len(occupied))
+	_, _ = w.WriteString(" \\\\\n")
+	if n.Kind() == east.KindTableHeader {
+		_, _ = w.WriteString("\\hline\n")
+		if r.LongTable {
+			_, _ = w.WriteString("\\endhead\n")
+		}
+	}
+	return ast.WalkContinue, nil
+}
+
+func (r *Renderer) renderTableCell(
+	w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	n := node.(*east.TableCell)
+	if entering {
+		columns, occupied := renderutil.TableRowLayout(n.Parent())
+		col := columns[n]
+		prevEnd := 0
+		if prev := n.PreviousSibling(); prev != nil {
+			prevEnd = columns[prev] + prev.(*east.TableCell).ColSpan
+		}
+		writeMultirowCells(w, occupied, prevEnd, col)
+		if col > 0 {
+			_, _ = w.WriteString(" & ")
+		}
+		if n.ColSpan > 1 {
+			table := n.Parent().Parent().(*east.Table)
+			spec := tableAlignment(n.Alignment, hasTableBlockCells(table), n.ColSpan, len(table.Alignments))
+			_, _ = fmt.Fprintf(w, "\\multicolumn{%d}{%s}{", n.ColSpan, spec)
+		}
+		if n.RowSpan > 1 {
+			_, _ = fmt.Fprintf(w, "\\multirow{%d}{*}{", n.RowSpan)
+		}
+	} else {
+		if n.RowSpan > 1 {
+			_ = w.WriteByte('}')
+		}
+		if n.ColSpan > 1 {
+			_ = w.WriteByte('}')
+		}
+	}
+	return ast.WalkContinue, nil
+}
+
+func (r *Renderer) renderStrikethrough(
+	w util.BufWriter, source []byte, n ast.Node, entering bool) (ast.WalkStatus, error) {
+	if entering {
+		_, _ = w.WriteString(`\sout{`)
+	} else {
+		_ = w.WriteByte('}')
+	}
+	return ast.WalkContinue, nil
+}
+
+func (r *Renderer) writeTaskCheckBox(w util.BufWriter, n *east.TaskCheckBox) {
+	if n.IsChecked {
+		_, _ = w.WriteString(`$\boxtimes$`)
+	} else {
+		_, _ = w.WriteString(`$\square$`)
+	}
+}
+
+func (r *Renderer) renderTaskCheckBox(
+	w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	if !entering {
+		return ast.WalkContinue, nil
+	}
+	n := node.(*east.TaskCheckBox)
+	// check boxes at the beginning of list items are rendered as labels of the items.
+	if p := n.Parent(); p.PreviousSibling() == nil && p.Parent() != nil && p.Parent().Kind() == ast.KindListItem &&
+		n.PreviousSibling() == nil {
+		return ast.WalkContinue, nil
+	}
+	r.writeTaskCheckBox(w, n)
+	_ = w.WriteByte(' ')
+	return ast.WalkContinue, nil
+}
+
+func (r *Renderer) renderDefinitionList(
+	w util.BufWriter, source []byte, n ast.Node, entering bool) (ast.WalkStatus, error) {
+	if entering {
+		r.writeBlockSeparator(w, n)
+		_, _ = w.WriteString("\\begin{description}\n")
+	} else {
+		_, _ = w.WriteString("\\end{description}\n")
+	}
+	return ast.WalkContinue, nil
+}
+
+func (r *Renderer) renderDefinitionTerm(
+	w util.BufWriter, source []byte, n ast.Node, entering bool) (ast.WalkStatus, error) {
+	if entering {
+		_, _ = w.WriteString(`\item[{`)
+	} else {
+		_, _ = w.WriteString("}]\n")
+	}
+	return ast.WalkContinue, nil
+}
+
+func (r *Renderer) renderDefinitionDescription(
+	w util.BufWriter, source []byte, n ast.Node, entering bool) (ast.WalkStatus, error) {
+	if entering {
+		if prev := n.PreviousSibling(); prev != nil && prev.Kind() == east.KindDefinitionDescription {
+			_, _ = w.WriteString("\\item ")
+		}
+	}
+	return ast.WalkContinue, nil
+}
+
+// findFootnote returns a footnote that has the given index.
+func findFootnote(n ast.Node, index int) *east.Footnote {
+	root := n
+	for root.Parent() != nil {
+		root = root.Parent()
+	}
+	var footnote *east.Footnote
+	_ = ast.Walk(root, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		if f, ok := n.(*east.Footnote); ok && entering {
+			if f.Index == index {
+				footnote = f
+				return ast.WalkStop, nil
+			}
+			return ast.WalkSkipChildren, nil
+		}
+		return ast.WalkContinue, nil
+	})
+	return footnote
+}
+
+func (r *Renderer) renderFootnoteLink(
+	w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	if !entering {
+		return ast.WalkContinue, nil
+	}
+	n := node.(*east.FootnoteLink)
+	if n.RefIndex > 0 {
+		_, _ = fmt.Fprintf(w, "\\footnotemark[%d]", n.Index)
+		return ast.WalkContinue, nil
+	}
+	footnote := findFootnote(n, n.Index)
+	if footnote == nil {
+		return ast.WalkContinue, nil
+	}
+	contents, err := renderutil.RenderChildrenToBytes(source, r.funcs, footnote)
+	if err != nil {
+		return ast.WalkStop, err
+	}
+	_, _ = fmt.Fprintf(w, "\\footnote[%d]{", n.Index)
+	_, _ = w.Write(contents)
+	_ = w.WriteByte('}')
+	return ast.WalkContinue, nil
+}
+
+func (r *Renderer) renderFigure(
+	w util.BufWriter, source []byte, n ast.Node, entering bool) (ast.WalkStatus, error) {
+	if entering {
+		r.writeBlockSeparator(w, n)
+		_, _ = w.WriteString("\\begin{figure}[htbp]\n\\centering\n")
+	} else {
+		_, _ = w.WriteString("\\end{figure}\n")
+	}
+	return ast.WalkContinue, nil
+}
+
+func (r *Renderer) renderFigureCaption(
+	w util.BufWriter, source []byte, n ast.Node, entering bool) (ast.WalkStatus, error) {
+	if entering {
+		_, _ = w.WriteString("\n\\caption{")
+	} else {
+		_, _ = w.WriteString("}\n")
+	}
+	return ast.WalkContinue, nil
+}
@@ -0,0 +1,720 @@
+// Package latex implements renderer that outputs LaTeX.
+package latex
+
+import (
+	"bytes"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/yuin/goldmark/ast"
+	east "github.com/yuin/goldmark/extension/ast"
+	"github.com/yuin/goldmark/renderer"
+	"github.com/yuin/goldmark/util"
+)
+
+// A Config struct has configurations for the LaTeX based renderers.
+type Config struct {
+	Writer Writer
+
+	// DocumentClass is a class of the document like 'article'.
+	// If DocumentClass is empty, the renderer outputs a fragment
+	// that does not have a preamble.
+	DocumentClass string
+
+	// DocumentClassOptions is options of the document class like 'a4paper'.
+	DocumentClassOptions []string
+
+	// Preamble is additional LaTeX commands that are written
+	// before '\begin{document}'.
+	Preamble string
+
+	// LongTable is true if tables are rendered as 'longtable' instead of 'tabular'.
+	LongTable bool
+
+	HardWraps bool
+}
+
+// NewConfig returns a new Config with defaults.
+func NewConfig() Config {
+	return Config{
+		Writer:    DefaultWriter,
+		LongTable: false,
+		HardWraps: false,
+	}
+}
+
+// SetOption implements renderer.NodeRenderer.SetOption.
+func (c *Config) SetOption(name renderer.OptionName, value any) {
+	switch name {
+	case optDocumentClass:
+		v := value.(documentClass)
+		c.DocumentClass = v.name
+		c.DocumentClassOptions = v.options
+	case optPreamble:
+		c.Preamble = value.(string)
+	case optLongTable:
+		c.LongTable = value.(bool)
+	case optHardWraps:
+		c.HardWraps = value.(bool)
+	case optTextWriter:
+		c.Writer = value.(Writer)
+	}
+}
+
+// An Option interface sets options for LaTeX based renderers.
+type Option interface {
+	SetLaTeXOption(*Config)
+}
+
+// TextWriter is an option name used in WithWriter.
+const optTextWriter renderer.OptionName = "LaTeXWriter"
+
+type withWriter struct {
+	value Writer
+}
+
+func (o *withWriter) SetConfig(c *renderer.Config) {
+	c.Options[optTextWriter] = o.value
+}
+
+func (o *withWriter) SetLaTeXOption(c *Config) {
+	c.Writer = o.value
+}
+
+// WithWriter is a functional option that allow you to set the given writer to
+// the renderer.
+func WithWriter(writer Writer) interface {
+	renderer.Option
+	Option
+} {
+	return &withWriter{writer}
+}
+
+// DocumentClass is an option name used in WithDocumentClass.
+const optDocumentClass renderer.OptionName = "LaTeXDocumentClass"
+
+type documentClass struct {
+	name    string
+	options []string
+}
+
+type withDocumentClass struct {
+	value documentClass
+}
+
+func (o *withDocumentClass) SetConfig(c *renderer.Config) {
+	c.Options[optDocumentClass] = o.value
+}
+
+func (o *withDocumentClass) SetLaTeXOption(c *Config) {
+	c.DocumentClass = o.value.name
+	c.DocumentClassOptions = o.value.options
+}
+
+// WithDocumentClass is a functional option that indicates the renderer
+// should output a complete document that has a preamble with the given
+// document class like 'article'.
+func WithDocumentClass(name string, options ...string) interface {
+	renderer.Option
+	Option
+} {
+	return &withDocumentClass{documentClass{name, options}}
+}
+
+// Preamble is an option name used in WithPreamble.
+const optPreamble renderer.OptionName = "LaTeXPreamble"
+
+type withPreamble struct {
+	value string
+}
+
+func (o *withPreamble) SetConfig(c *renderer.Config) {
+	c.Options[optPreamble] = o.value
+}
+
+func (o *withPreamble) SetLaTeXOption(c *Config) {
+	c.Preamble = o.value
+}
+
+// WithPreamble is a functional option that adds the given LaTeX commands to
+// the preamble. This option takes effect only with WithDocumentClass.
+func WithPreamble(preamble string) interface {
+	renderer.Option
+	Option
+} {
+	return &withPreamble{preamble}
+}
+
+// LongTable is an option name used in WithLongTable.
+const optLongTable renderer.OptionName = "LaTeXLongTable"
+
+type withLongTable struct {
+}
+
+func (o *withLongTable) SetConfig(c *renderer.Config) {
+	c.Options[optLongTable] = true
+}
+
+func (o *withLongTable) SetLaTeXOption(c *Config) {
+	c.LongTable = true
+}
+
+// WithLongTable is a functional option that indicates tables should be
+// rendered as 'longtable' that can be broken across pages.
+func WithLongTable() interface {
+	renderer.Option
+	Option
+} {
+	return &withLongTable{}
+}
+
+// HardWraps is an option name used in WithHardWraps.
+const optHardWraps renderer.OptionName = "LaTeXHardWraps"
+
+type withHardWraps struct {
+}
+
+func (o *withHardWraps) SetConfig(c *renderer.Config) {
+	c.Options[optHardWraps] = true
+}
+
+func (o *withHardWraps) SetLaTeXOption(c *Config) {
+	c.HardWraps = true
+}
+
+// WithHardWraps is a functional option that indicates whether softline breaks
+// should be rendered as '\\'.
+func WithHardWraps() interface {
+	renderer.Option
+	Option
+} {
+	return &withHardWraps{}
+}
+
+// A Renderer struct is an implementation of renderer.NodeRenderer that renders
+// nodes as LaTeX.
+//
+// Renderer renders nodes defined in the extension/ast package as well as
+// core nodes.
+type Renderer struct {
+	Config
+	funcs map[ast.NodeKind]renderer.NodeRendererFunc
+}
+
+// NewRenderer returns a new Renderer with given options.
+func NewRenderer(opts ...Option) renderer.NodeRenderer {
+	r := &Renderer{
+		Config: NewConfig(),
+	}
+
+	for _, opt := range opts {
+		opt.SetLaTeXOption(&r.Config)
+	}
+	r.funcs = map[ast.NodeKind]renderer.NodeRendererFunc{
+		// blocks
+
+		ast.KindDocument:                r.renderDocument,
+		ast.KindHeading:                 r.renderHeading,
+		ast.KindBlockquote:              r.renderBlockquote,
+		ast.KindCodeBlock:               r.renderCodeBlock,
+		ast.KindFencedCodeBlock:         r.renderCodeBlock,
+		ast.KindHTMLBlock:               r.renderHTMLBlock,
+		ast.KindList:                    r.renderList,
+		ast.KindListItem:                r.renderListItem,
+		ast.KindParagraph:               r.renderParagraph,
+		ast.KindTextBlock:               r.renderTextBlock,
+		ast.KindThematicBreak:           r.renderThematicBreak,
+		ast.KindLinkReferenceDefinition: renderNothing,
+
+		// inlines
+
+		ast.KindAutoLink: r.renderAutoLink,
+		ast.KindCodeSpan: r.renderCodeSpan,
+		ast.KindEmphasis: r.renderEmphasis,
+		ast.KindImage:    r.renderImage,
+		ast.KindLink:     r.renderLink,
+		ast.KindRawHTML:  renderNothing,
+		ast.KindText:     r.renderText,
+		ast.KindString:   r.renderString,
+
+		// extensions
+
+		east.KindTable:                  r.renderTable,
+		east.KindTableHeader:            r.renderTableRow,
+		east.KindTableRow:               r.renderTableRow,
+		east.KindTableCell:              r.renderTableCell,
+		east.KindCaption:                r.renderCaption,
+		east.KindStrikethrough:          r.renderStrikethrough,
+		east.KindTaskCheckBox:           r.renderTaskCheckBox,
+		east.KindDefinitionList:         r.renderDefinitionList,
+		east.KindDefinitionTerm:         r.renderDefinitionTerm,
+		east.KindDefinitionDescription:  r.renderDefinitionDescription,
+		east.KindFootnoteLink:           r.renderFootnoteLink,
+		east.KindFootnoteBacklink:       renderNothing,
+		east.KindFootnote:               renderNothing,
+		east.KindFootnoteList:           renderNothing,
+		east.KindAbbreviation:           renderContents,
+		east.KindAbbreviationDefinition: renderNothing,
+		east.KindFigure:                 r.renderFigure,
+		east.KindFigureCaption:          r.renderFigureCaption,
+	}
+	return r
+}
+
+// RegisterFuncs implements NodeRenderer.RegisterFuncs .
+func (r *Renderer) RegisterFuncs(reg renderer.NodeRendererFuncRegisterer) {
+	for kind, f := range r.funcs {
+		reg.Register(kind, f)
+	}
+}
+
+func renderNothing(_ util.BufWriter, _ []byte, _ ast.Node, _ bool) (ast.WalkStatus, error) {
+	return ast.WalkSkipChildren, nil
+}
+
+func renderContents(_ util.BufWriter, _ []byte, _ ast.Node, _ bool) (ast.WalkStatus, error) {
+	return ast.WalkContinue, nil
+}
+
+// writeBlockSeparator writes a blank line before the given block unless the
+// block follows a text of a tight list item.
+func (r *Renderer) writeBlockSeparator(w util.BufWriter, n ast.Node) {
+	if prev := n.PreviousSibling(); prev != nil && prev.Kind() != ast.KindTextBlock {
+		_ = w.WriteByte('\n')
+	}
+}
+
+// writeLabel writes '\label' command if the given node has an id attribute.
+func (r *Renderer) writeLabel(w util.BufWriter, n ast.Node) {
+	id, ok := n.AttributeString("id")
+	if !ok {
+		return
+	}
+	var value []byte
+	switch typed := id.(type) {
+	case []byte:
+		value = typed
+	case string:
+		value = util.StringToReadOnlyBytes(typed)
+	}
+	if len(value) == 0 {
+		return
+	}
+	_, _ = w.WriteString(`\label{`)
+	writeLabelName(w, value)
+	_ = w.WriteByte('}')
+}
+
+func writeLabelName(w util.BufWriter, value []byte) {
+	for _, c := range value {
+		switch c {
+		case '\\', '{', '}', '%', '#', '~', '^', '&', '$':
+			continue
+		}
+		_ = w.WriteByte(c)
+	}
+}
+
+var defaultPackages = []string{
+	`\usepackage[T1]{fontenc}`,
+	`\usepackage[utf8]{inputenc}`,
+	`\usepackage{amssymb}`,
+	`\usepackage{graphicx}`,
+	`\usepackage{array}`,
+	`\usepackage{longtable}`,
+	`\usepackage{multirow}`,
+	`\usepackage[normalem]{ulem}`,
+	`\usepackage{hyperref}`,
+}
+
+func (r *Renderer) renderDocument(
+	w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	if len(r.DocumentClass) == 0 {
+		return ast.WalkContinue, nil
+	}
+	if entering {
+		_, _ = w.WriteString(`\documentclass`)
+		if len(r.DocumentClassOptions) != 0 {
+			_, _ = fmt.Fprintf(w, "[%s]", strings.Join(r.DocumentClassOptions, ","))
+		}
+		_, _ = fmt.Fprintf(w, "{%s}\n", r.DocumentClass)
+		for _, p := range defaultPackages {
+			_, _ = w.WriteString(p)
+			_ = w.WriteByte('\n')
+		}
+		if len(r.Preamble) != 0 {
+			_, _ = w.WriteString(r.Preamble)
+			if !strings.HasSuffix(r.Preamble, "\n") {
+				_ = w.WriteByte('\n')
+			}
+		}
+		_, _ = w.WriteString("\\begin{document}\n\n")
+	} else {
+		_, _ = w.WriteString("\n\\end{document}\n")
+	}
+	return ast.WalkContinue, nil
+}
+
+var headingCommands = []string{
+	`\section`,
+	`\subsection`,
+	`\subsubsection`,
+	`\paragraph`,
+	`\subparagraph`,
+}
+
+func (r *Renderer) renderHeading(
+	w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	n := node.(*ast.Heading)
+	if entering {
+		r.writeBlockSeparator(w, n)
+		level := min(n.Level, len(headingCommands))
+		_, _ = w.WriteString(headingCommands[level-1])
+		_ = w.WriteByte('{')
+	} else {
+		_ = w.WriteByte('}')
+		r.writeLabel(w, n)
+		_ = w.WriteByte('\n')
+	}
+	return ast.WalkContinue, nil
+}
+
+func (r *Renderer) renderBlockquote(
+	w util.BufWriter, source []byte, n ast.Node, entering bool) (ast.WalkStatus, error) {
+	if entering {
+		r.writeBlockSeparator(w, n)
+		_, _ = w.WriteString("\\begin{quote}\n")
+	} else {
+		_, _ = w.WriteString("\\end{quote}\n")
+	}
+	return ast.WalkContinue, nil
+}
+
+func (r *Renderer) renderCodeBlock(w util.BufWriter, source []byte, n ast.Node, entering bool) (ast.WalkStatus, error) {
+	if entering {
+		r.writeBlockSeparator(w, n)
+		_, _ = w.WriteString("\\begin{verbatim}\n")
+		l := n.Lines().Len()
+		for i := range l {
+			line := n.Lines().At(i)
+			_, _ = w.Write(line.Value(source))
+		}
+	} else {
+		_, _ = w.WriteString("\\end{verbatim}\n")
+	}
+	return ast.WalkContinue, nil
+}
+
+func (r *Renderer) renderHTMLBlock(
+	w util.BufWriter, source []byte, n ast.Node, entering bool) (ast.WalkStatus, error) {
+	if entering {
+		r.writeBlockSeparator(w, n)
+		_, _ = w.WriteString("% raw HTML omitted\n")
+	}
+	return ast.WalkSkipChildren, nil
+}
+
+func (r *Renderer) renderList(w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	n := node.(*ast.List)
+	env := "itemize"
+	if n.IsOrdered() {
+		env = "enumerate"
+	}
+	if entering {
+		r.writeBlockSeparator(w, n)
+		_, _ = fmt.Fprintf(w, "\\begin{%s}\n", env)
+		if n.IsOrdered() && n.Start != 1 {
+			depth := 0
+			for p := n.Parent(); p != nil; p = p.Parent() {
+				if l, ok := p.(*ast.List); ok && l.IsOrdered() {
+					depth++
+				}
+			}
+			if depth < 4 {
+				_, _ = fmt.Fprintf(w, "\\setcounter{enum%s}{%d}\n",
+					[]string{"i", "ii", "iii", "iv"}[depth], n.Start-1)
+			}
+		}
+	} else {
+		_, _ = fmt.Fprintf(w, "\\end{%s}\n", env)
+	}
+	return ast.WalkContinue, nil
+}
+
+func (r *Renderer) renderListItem(w util.BufWriter, source []byte, n ast.Node, entering bool) (ast.WalkStatus, error) {
+	if !entering {
+		return ast.WalkContinue, nil
+	}
+	_, _ = w.WriteString(`\item`)
+	if fc := n.FirstChild(); fc != nil {
+		if cb, ok := fc.FirstChild().(*east.TaskCheckBox); ok {
+			_ = w.WriteByte('[')
+			r.writeTaskCheckBox(w, cb)
+			_ = w.WriteByte(']')
+		}
+		if fc.Kind() != ast.KindTextBlock && fc.Kind() != ast.KindParagraph {
+			_ = w.WriteByte('\n')
+			return ast.WalkContinue, nil
+		}
+	}
+	_ = w.WriteByte(' ')
+	return ast.WalkContinue, nil
+}
+
+func (r *Renderer) renderParagraph(w util.BufWriter, source []byte, n ast.Node, entering bool) (ast.WalkStatus, error) {
+	if entering {
+		r.writeBlockSeparator(w, n)
+	} else {
+		_ = w.WriteByte('\n')
+	}
+	return ast.WalkContinue, nil
+}
+
+func (r *Renderer) renderTextBlock(w util.BufWriter, source []byte, n ast.Node, entering bool) (ast.WalkStatus, error) {
+	if !entering {
+		if n.NextSibling() != nil || n.Parent().Kind() != east.KindTableCell {
+			_ = w.WriteByte('\n')
+		}
+	}
+	return ast.WalkContinue, nil
+}
+
+func (r *Renderer) renderThematicBreak(
+	w util.BufWriter, source []byte, n ast.Node, entering bool) (ast.WalkStatus, error) {
+	if entering {
+		r.writeBlockSeparator(w, n)
+		_, _ = w.WriteString("\\begin{center}\\rule{0.5\\linewidth}{0.5pt}\\end{center}\n")
+	}
+	return ast.WalkContinue, nil
+}
+
+func (r *Renderer) renderAutoLink(
+	w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	n := node.(*ast.AutoLink)
+	if !entering {
+		return ast.WalkContinue, nil
+	}
+	url := util.URLEscape(n.URL(source), false)
+	if n.AutoLinkType == ast.AutoLinkEmail {
+		_, _ = w.WriteString(`\href{`)
+		if !bytes.HasPrefix(bytes.ToLower(url), []byte("mailto:")) {
+			_, _ = w.WriteString("mailto:")
+		}
+		writeURL(w, url)
+		_, _ = w.WriteString("}{")
+		r.Writer.RawWrite(w, n.Label(source))
+		_ = w.WriteByte('}')
+		return ast.WalkContinue, nil
+	}
+	_, _ = w.WriteString(`\url{`)
+	writeURL(w, url)
+	_ = w.WriteByte('}')
+	return ast.WalkContinue, nil
+}
+
+// writeURL writes the given url with escaping characters that have special
+// meanings in the arguments of '\href' and '\url'.
+func writeURL(w util.BufWriter, url []byte) {
+	for _, c := range url {
+		switch c {
+		case '%', '#':
+			_ = w.WriteByte('\\')
+			_ = w.WriteByte(c)
+		case '\\', '{', '}':
+			_, _ = fmt.Fprintf(w, "\\%%%02X", c)
+		default:
+			_ = w.WriteByte(c)
+		}
+	}
+}
+
+func (r *Renderer) renderCodeSpan(w util.BufWriter, source []byte, n ast.Node, entering bool) (ast.WalkStatus, error) {
+	if entering {
+		_, _ = w.WriteString(`\texttt{`)
+		for c := n.FirstChild(); c != nil; c = c.NextSibling() {
+			segment := c.(*ast.Text).Segment
+			value := segment.Value(source)
+			if bytes.HasSuffix(value, []byte("\n")) {
+				r.Writer.RawWrite(w, value[:len(value)-1])
+				r.Writer.RawWrite(w, []byte(" "))
+			} else {
+				r.Writer.RawWrite(w, value)
+			}
+		}
+		_ = w.WriteByte('}')
+	}
+	return ast.WalkSkipChildren, nil
+}
+
+func (r *Renderer) renderEmphasis(
+	w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	n := node.(*ast.Emphasis)
+	if entering {
+		if n.Level == 2 {
+			_, _ = w.WriteString(`\textbf{`)
+		} else {
+			_, _ = w.WriteString(`\emph{`)
+		}
+	} else {
+		_ = w.WriteByte('}')
+	}
+	return ast.WalkContinue, nil
+}
+
+func (r *Renderer) renderLink(w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	n := node.(*ast.Link)
+	if entering {
+		dest := util.URLEscape(n.Destination, true)
+		if len(dest) > 1 && dest[0] == '#' {
+			_, _ = w.WriteString(`\hyperref[`)
+			writeLabelName(w, dest[1:])
+			_, _ = w.WriteString("]{")
+		} else {
+			_, _ = w.WriteString(`\href{`)
+			writeURL(w, dest)
+			_, _ = w.WriteString("}{")
+		}
+	} else {
+		_ = w.WriteByte('}')
+	}
+	return ast.WalkContinue, nil
+}
+
+func (r *Renderer) renderImage(w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	if !entering {
+		return ast.WalkContinue, nil
+	}
+	n := node.(*ast.Image)
+	dest := util.URLEscape(n.Destination, true)
+	// destinations are URLs, so '%20' is a space of the file name.
+	path := util.UnescapePunctuations(n.Destination)
+	if p, err := url.PathUnescape(string(path)); err == nil {
+		path = []byte(p)
+	}
+	// remote images can not be included. '\detokenize' can not have
+	// unbalanced braces, '%', '#' and line breaks, and '\' would be written
+	// as a command, so images that have such paths are linked instead.
+	if bytes.HasPrefix(dest, []byte("http://")) || bytes.HasPrefix(dest, []byte("https://")) ||
+		bytes.ContainsAny(path, "\\{}%#\r\n") {
+		_, _ = w.WriteString(`\href{`)
+		writeURL(w, dest)
+		_, _ = w.WriteString("}{")
+		r.renderTexts(w, source, n)
+		_ = w.WriteByte('}')
+		return ast.WalkSkipChildren, nil
+	}
+	_, _ = w.WriteString(`\includegraphics{\detokenize{`)
+	_, _ = w.Write(path)
+	_, _ = w.WriteString("}}")
+	return ast.WalkSkipChildren, nil
+}
+
+func (r *Renderer) renderText(w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	if !entering {
+		return ast.WalkContinue, nil
+	}
+	n := node.(*ast.Text)
+	segment := n.Segment
+	if n.IsRaw() {
+		r.Writer.RawWrite(w, segment.Value(source))
+	} else {
+		r.Writer.Write(w, segment.Value(source))
+	}
+	if n.HardLineBreak() || (n.SoftLineBreak() && r.HardWraps) {
+		_, _ = w.WriteString("\\\\\n")
+	} else if n.SoftLineBreak() {
+		_ = w.WriteByte('\n')
+	}
+	return ast.WalkContinue, nil
+}
+
+func (r *Renderer) renderString(w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	if !entering {
+		return ast.WalkContinue, nil
+	}
+	n := node.(*ast.String)
+	if n.IsCode() || n.IsRaw() {
+		r.Writer.RawWrite(w, n.Value)
+	} else {
+		r.Writer.Write(w, n.Value)
+	}
+	return ast.WalkContinue, nil
+}
+
+func (r *Renderer) renderTexts(w util.BufWriter, source []byte, n ast.Node) {
+	for c := n.FirstChild(); c != nil; c = c.NextSibling() {
+		if s, ok := c.(*ast.String); ok {
+			_, _ = r.renderString(w, source, s, true)
+		} else if t, ok := c.(*ast.Text); ok {
+			_, _ = r.renderText(w, source, t, true)
+		} else {
+			r.renderTexts(w, source, c)
+		}
+	}
+}
+
+// A Writer interface writes textual contents to a writer.
+type Writer interface {
+	// Write writes the given source to writer with resolving references and unescaping
+	// backslash escaped characters.
+	Write(writer util.BufWriter, source []byte)
+
+	// RawWrite writes the given source to writer without resolving references and
+	// unescaping backslash escaped characters.
+	RawWrite(writer util.BufWriter, source []byte)
+}
+
+type defaultWriter struct {
+}
+
+// NewWriter returns a new Writer.
+func NewWriter() Writer {
+	return &defaultWriter{}
+}
+
+var latexEscapes = [256][]byte{
+	'\\': []byte(`\textbackslash{}`),
+	'{':  []byte(`\{`),
+	'}':  []byte(`\}`),
+	'$':  []byte(`\$`),
+	'&':  []byte(`\&`),
+	'#':  []byte(`\#`),
+	'%':  []byte(`\%`),
+	'_':  []byte(`\_`),
+	'~':  []byte(`\textasciitilde{}`),
+	'^':  []byte(`\textasciicircum{}`),
+	'[':  []byte(`{[}`),
+	']':  []byte(`{]}`),
+}
+
+func (d *defaultWriter) RawWrite(writer util.BufWriter, source []byte) {
+	n := 0
+	l := len(source)
+	for i := range l {
+		v := latexEscapes[source[i]]
+		if source[i] == 0 {
+			v = []byte("\ufffd")
+		}
+		if v != nil {
+			_, _ = writer.Write(source[i-n : i])
+			n = 0
+			_, _ = writer.Write(v)
+			continue
+		}
+		n++
+	}
+	if n != 0 {
+		_, _ = writer.Write(source[l-n:])
+	}
+}
+
+func (d *defaultWriter) Write(writer util.BufWriter, source []byte) {
+	source = util.UnescapePunctuations(source)
+	source = util.ResolveNumericReferences(source)
+	source = util.ResolveEntityNames(source)
+	d.RawWrite(writer, source)
+}
+
+// DefaultWriter is a default instance of the Writer.
+var DefaultWriter = NewWriter()
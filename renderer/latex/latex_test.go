@@ -0,0 +1,171 @@
+package latex_test
+
+import (
+	"testing"
+
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/extension"
+	"github.com/yuin/goldmark/renderer"
+	"github.com/yuin/goldmark/renderer/latex"
+	"github.com/yuin/goldmark/testutil"
+	"github.com/yuin/goldmark/util"
+)
+
+func newMarkdown(opts ...latex.Option) goldmark.Markdown {
+	return goldmark.New(
+		goldmark.WithRenderer(renderer.NewRenderer(
+			renderer.WithNodeRenderers(util.Prioritized(latex.NewRenderer(opts...), 100)),
+		)),
+		goldmark.WithExtensions(
+			extension.NewTable(extension.WithTableCellSpans(), extension.WithTableCaptions()),
+			extension.Strikethrough,
+			extension.TaskList,
+			extension.DefinitionList,
+			extension.Footnote,
+		),
+	)
+}
+
+func TestRenderer(t *testing.T) {
+	markdown := newMarkdown()
+	testutil.DoTestCase(
+		markdown,
+		testutil.MarkdownTestCase{
+			No:          1,
+			Description: "Core nodes and escaping",
+			Markdown: `# 100% $cost_1 & {more} {#id}
+
+Text with *em*, **strong**, ` + "`a_b`" + `, [link](http://example.com/#a%20b) and [ref](#id).
+\*not em\* &amp; ~^\
+
+1. one
+2. two
+
+> - item
+
+    code \n
+`,
+			Expected: `\section{100\% \$cost\_1 \& \{more\} \{\#id\}}
+
+Text with \emph{em}, \textbf{strong}, \texttt{a\_b}, \href{http://example.com/\#a\%20b}{link} and \hyperref[id]{ref}.
+*not em* \& \textasciitilde{}\textasciicircum{}\textbackslash{}
+
+\begin{enumerate}
+\item one
+\item two
+\end{enumerate}
+
+\begin{quote}
+\begin{itemize}
+\item item
+\end{itemize}
+\end{quote}
+
+\begin{verbatim}
+code \n
+\end{verbatim}
+`,
+		},
+		t,
+	)
+
+	testutil.DoTestCase(
+		markdown,
+		testutil.MarkdownTestCase{
+			No:          2,
+			Description: "Extension nodes",
+			Markdown: `- [ ] todo
+- [x] ~~done~~
+
+Term
+: Description[^1]
+
+| a  | b   ||
+| -- | :-: | -: |
+| 1  | 2   | 3  |
+| ^^ | 4   | 5  |
+Table: Caption {#tbl:t}
+
+[^1]: Note.
+`,
+			Expected: `\begin{itemize}
+\item[$\square$] todo
+\item[$\boxtimes$] \sout{done}
+\end{itemize}
+
+\begin{description}
+\item[{Term}]
+Description\footnote[1]{Note.}
+\end{description}
+
+\begin{table}[htbp]
+\centering
+\caption{Caption}\label{tbl:t}
+\begin{tabular}{lcr}
+\hline
+a & \multicolumn{2}{c}{b} \\
+\hline
+\multirow{2}{*}{1} & 2 & 3 \\
+ & 4 & 5 \\
+\hline
+\end{tabular}
+\end{table}
+`,
+		},
+		t,
+	)
+
+	testutil.DoTestCase(
+		newMarkdown(latex.WithDocumentClass("article", "a4paper"), latex.WithPreamble(`\title{T}`), latex.WithLongTable()),
+		testutil.MarkdownTestCase{
+			No:          3,
+			Description: "Standalone documents",
+			Markdown: `| a |
+| - |
+| b |
+`,
+			Expected: `\documentclass[a4paper]{article}
+\usepackage[T1]{fontenc}
+\usepackage[utf8]{inputenc}
+\usepackage{amssymb}
+\usepackage{graphicx}
+\usepackage{array}
+\usepackage{longtable}
+\usepackage{multirow}
+\usepackage[normalem]{ulem}
+\usepackage{hyperref}
+\title{T}
+\begin{document}
+
+\begin{longtable}{l}
+\hline
+a \\
+\hline
+\endhead
+b \\
+\hline
+\end{longtable}
+
+\end{document}
+`,
+		},
+		t,
+	)
+
+	testutil.DoTestCase(
+		markdown,
+		testutil.MarkdownTestCase{
+			No:          4,
+			Description: "Image paths can not have commands",
+			Markdown: `![a](dir/a%20b_c.png)
+![b](\input{/etc/passwd})
+![c](100%25.png)
+`,
+			Expected: `\includegraphics{\detokenize{dir/a b_c.png}}
+\href{\%5Cinput\%7B/etc/passwd\%7D}{b}
+\href{100\%25.png}{c}
+`,
+		},
+		t,
+	)
+}
@@ -0,0 +1,223 @@
+package man
+
+import (
+	"fmt"
+
+	"github.com/yuin/goldmark/ast"
+	east "github.com/yuin/goldmark/extension/ast"
+	"github.com/yuin/goldmark/renderer/internal/renderutil"
+	"github.com/yuin/goldmark/util"
+)
+
+func hasTableCaption(table ast.Node) bool {
+	return table.FirstChild() != nil && table.FirstChild().Kind() == east.KindCaption
+}
+
+// writeTableFormat writes a format line of the given row for tbl.
+// Columns spanned by cells are written as 's' and columns occupied by
+// cells in rows above are written as '^'.
+func writeTableFormat(w util.BufWriter, row ast.Node) {
+	columns, occupied := renderutil.TableRowLayout(row)
+	format := make([]string, len(occupied))
+	for i, v := range occupied {
+		format[i] = "l"
+		if v {
+			format[i] = "^"
+		}
+	}
+	for c := row.FirstChild(); c != nil; c = c.NextSibling() {
+		cell := c.(*east.TableCell)
+		col := columns[c]
+		if col >= len(format) {
+			continue
+		}
+		switch cell.Alignment {
+		case east.AlignRight:
+			format[col] = "r"
+		case east.AlignCenter:
+			format[col] = "c"
+		default:
+			format[col] = "l"
+		}
+		if row.Kind() == east.KindTableHeader {
+			format[col] += "b"
+		}
+		for k := col + 1; k < col+cell.ColSpan && k < len(format); k++ {
+			format[k] = "s"
+		}
+	}
+	for i, f := range format {
+		if i != 0 {
+			_ = w.WriteByte(' ')
+		}
+		_, _ = w.WriteString(f)
+	}
+}
+
+// writeTableStart writes a '.TS' macro and format lines of the table.
+func writeTableStart(w util.BufWriter, table ast.Node) {
+	_, _ = w.WriteString(".TS\nallbox;\n")
+	var last ast.Node
+	for r := table.FirstChild(); r != nil; r = r.NextSibling() {
+		if r.Kind() == east.KindTableRow || r.Kind() == east.KindTableHeader {
+			if last != nil {
+				writeTableFormat(w, last)
+				_ = w.WriteByte('\n')
+			}
+			last = r
+		}
+	}
+	if last != nil {
+		writeTableFormat(w, last)
+	}
+	_, _ = w.WriteString(".\n")
+}
+
+func (r *Renderer) renderTable(
+	w util.BufWriter, source []byte, n ast.Node, entering bool) (ast.WalkStatus, error) {
+	if entering {
+		r.writeParagraphMacro(w, n)
+		if !hasTableCaption(n) {
+			writeTableStart(w, n)
+		}
+	} else {
+		_, _ = w.WriteString(".TE\n")
+	}
+	return ast.WalkContinue, nil
+}
+
+func (r *Renderer) renderCaption(
+	w util.BufWriter, source []byte, n ast.Node, entering bool) (ast.WalkStatus, error) {
+	if !entering {
+		_ = w.WriteByte('\n')
+		writeTableStart(w, n.Parent())
+	}
+	return ast.WalkContinue, nil
+}
+
+// writeEmptyEntries writes tab separators of empty entries for columns in
+// [from, to). tbl requires entries for columns that are spanned by cells in
+// rows above.
+func writeEmptyEntries(w util.BufWriter, from, to int) {
+	for c := from; c < to; c++ {
+		if c > 0 {
+			_ = w.WriteByte('\t')
+		}
+	}
+}
+
+func (r *Renderer) renderTableRow(
+	w util.BufWriter, source []byte, n ast.Node, entering bool) (ast.WalkStatus, error) {
+	if entering {
+		return ast.WalkContinue, nil
+	}
+	columns, occupied := renderutil.TableRowLayout(n)
+	end := 0
+	if last := n.LastChild(); last != nil {
+		end = columns[last] + last.(*east.TableCell).ColSpan
+	}
+	writeEmptyEntries(w, end, len(occupied))
+	_ = w.WriteByte('\n')
+	return ast.WalkContinue, nil
+}
+
+// hasBlockChildren returns true if the given table cell has blocks like
+// paragraphs. Such cells are written as text blocks of tbl.
+func hasBlockChildren(cell ast.Node) bool {
+	return cell.FirstChild() != nil && cell.FirstChild().Type() == ast.TypeBlock
+}
+
+func (r *Renderer) renderTableCell(
+	w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	n := node.(*east.TableCell)
+	if entering {
+		columns, _ := renderutil.TableRowLayout(n.Parent())
+		prevEnd := 0
+		if prev := n.PreviousSibling(); prev != nil {
+			prevEnd = columns[prev] + prev.(*east.TableCell).ColSpan
+		}
+		writeEmptyEntries(w, prevEnd, columns[n]+1)
+		if hasBlockChildren(n) {
+			_, _ = w.WriteString("T{\n")
+		}
+	} else {
+		if hasBlockChildren(n) {
+			_, _ = w.WriteString("T}")
+		}
+		columns, _ := renderutil.TableRowLayout(n.Parent())
+		writeEmptyEntries(w, columns[n]+1, columns[n]+n.ColSpan)
+	}
+	return ast.WalkContinue, nil
+}
+
+func (r *Renderer) renderTaskCheckBox(
+	w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	if !entering {
+		return ast.WalkContinue, nil
+	}
+	n := node.(*east.TaskCheckBox)
+	if n.IsChecked {
+		_, _ = w.WriteString("[x] ")
+	} else {
+		_, _ = w.WriteString("[ ] ")
+	}
+	return ast.WalkContinue, nil
+}
+
+func (r *Renderer) renderDefinitionTerm(
+	w util.BufWriter, source []byte, n ast.Node, entering bool) (ast.WalkStatus, error) {
+	if entering {
+		if prev := n.PreviousSibling(); prev != nil && prev.Kind() == east.KindDefinitionTerm {
+			_, _ = w.WriteString(".TQ\n")
+		} else {
+			_, _ = w.WriteString(".TP\n")
+		}
+	} else {
+		_ = w.WriteByte('\n')
+	}
+	return ast.WalkContinue, nil
+}
+
+func (r *Renderer) renderFootnoteLink(
+	w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	if entering {
+		n := node.(*east.FootnoteLink)
+		_, _ = fmt.Fprintf(w, "[%d]", n.Index)
+	}
+	return ast.WalkContinue, nil
+}
+
+func (r *Renderer) renderFootnoteList(
+	w util.BufWriter, source []byte, n ast.Node, entering bool) (ast.WalkStatus, error) {
+	if entering {
+		_, _ = w.WriteString(".SH\nNOTES\n")
+	}
+	return ast.WalkContinue, nil
+}
+
+func (r *Renderer) renderFootnote(
+	w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	if entering {
+		n := node.(*east.Footnote)
+		_, _ = fmt.Fprintf(w, ".IP \"[%d]\" 4\n", n.Index)
+	}
+	return ast.WalkContinue, nil
+}
+
+func (r *Renderer) renderFigure(
+	w util.BufWriter, source []byte, n ast.Node, entering bool) (ast.WalkStatus, error) {
+	if entering {
+		r.writeParagraphMacro(w, n)
+	} else {
+		_ = w.WriteByte('\n')
+	}
+	return ast.WalkContinue, nil
+}
+
+func (r *Renderer) renderFigureCaption(
+	w util.BufWriter, source []byte, n ast.Node, entering bool) (ast.WalkStatus, error) {
+	if entering {
+		_, _ = w.WriteString("\n.br\n")
+	}
+	return ast.WalkContinue, nil
+}
@@ -0,0 +1,589 @@
+// Package man implements renderer that outputs roff with the man macros.
+package man
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/yuin/goldmark/ast"
+	east "github.com/yuin/goldmark/extension/ast"
+	"github.com/yuin/goldmark/renderer"
+	"github.com/yuin/goldmark/renderer/internal/renderutil"
+	"github.com/yuin/goldmark/util"
+)
+
+// A Header struct is arguments of the '.TH' macro.
+type Header struct {
+	// Title is a title of the manual page like 'GOLDMARK'.
+	Title string
+
+	// Section is a section number of the manual page like '1'.
+	Section string
+
+	// Date is a date of the last nontrivial change.
+	Date string
+
+	// Source is a source of the command like 'goldmark 1.0'.
+	Source string
+
+	// Manual is a title of the manual like 'User Commands'.
+	Manual string
+}
+
+// A Config struct has configurations for the man based renderers.
+type Config struct {
+	Writer Writer
+
+	// Header is a default header of the manual page.
+	// Values in the metadata of the document(e.g. front matter) like
+	// 'title' and 'section' take precedence over this value.
+	// If no title is given, the renderer does not output the '.TH' macro.
+	Header Header
+}
+
+// NewConfig returns a new Config with defaults.
+func NewConfig() Config {
+	return Config{
+		Writer: DefaultWriter,
+	}
+}
+
+// SetOption implements renderer.NodeRenderer.SetOption.
+func (c *Config) SetOption(name renderer.OptionName, value any) {
+	switch name {
+	case optHeader:
+		c.Header = value.(Header)
+	case optTextWriter:
+		c.Writer = value.(Writer)
+	}
+}
+
+// An Option interface sets options for man based renderers.
+type Option interface {
+	SetManOption(*Config)
+}
+
+// TextWriter is an option name used in WithWriter.
+const optTextWriter renderer.OptionName = "ManWriter"
+
+type withWriter struct {
+	value Writer
+}
+
+func (o *withWriter) SetConfig(c *renderer.Config) {
+	c.Options[optTextWriter] = o.value
+}
+
+func (o *withWriter) SetManOption(c *Config) {
+	c.Writer = o.value
+}
+
+// WithWriter is a functional option that allow you to set the given writer to
+// the renderer.
+func WithWriter(writer Writer) interface {
+	renderer.Option
+	Option
+} {
+	return &withWriter{writer}
+}
+
+// Header is an option name used in WithHeader.
+const optHeader renderer.OptionName = "ManHeader"
+
+type withHeader struct {
+	value Header
+}
+
+func (o *withHeader) SetConfig(c *renderer.Config) {
+	c.Options[optHeader] = o.value
+}
+
+func (o *withHeader) SetManOption(c *Config) {
+	c.Header = o.value
+}
+
+// WithHeader is a functional option that sets the default arguments
+// of the '.TH' macro.
+func WithHeader(header Header) interface {
+	renderer.Option
+	Option
+} {
+	return &withHeader{header}
+}
+
+// A Renderer struct is an implementation of renderer.NodeRenderer that renders
+// nodes as roff with the man macros.
+type Renderer struct {
+	Config
+}
+
+// NewRenderer returns a new Renderer with given options.
+func NewRenderer(opts ...Option) renderer.NodeRenderer {
+	r := &Renderer{
+		Config: NewConfig(),
+	}
+
+	for _, opt := range opts {
+		opt.SetManOption(&r.Config)
+	}
+	return r
+}
+
+// RegisterFuncs implements NodeRenderer.RegisterFuncs .
+func (r *Renderer) RegisterFuncs(reg renderer.NodeRendererFuncRegisterer) {
+	// blocks
+
+	reg.Register(ast.KindDocument, r.renderDocument)
+	reg.Register(ast.KindHeading, r.renderHeading)
+	reg.Register(ast.KindBlockquote, r.renderBlockquote)
+	reg.Register(ast.KindCodeBlock, r.renderCodeBlock)
+	reg.Register(ast.KindFencedCodeBlock, r.renderCodeBlock)
+	reg.Register(ast.KindHTMLBlock, r.renderHTMLBlock)
+	reg.Register(ast.KindList, r.renderList)
+	reg.Register(ast.KindListItem, r.renderListItem)
+	reg.Register(ast.KindParagraph, r.renderParagraph)
+	reg.Register(ast.KindTextBlock, r.renderParagraph)
+	reg.Register(ast.KindThematicBreak, r.renderThematicBreak)
+	reg.Register(ast.KindLinkReferenceDefinition, renderNothing)
+
+	// inlines
+
+	reg.Register(ast.KindAutoLink, r.renderAutoLink)
+	reg.Register(ast.KindCodeSpan, r.renderCodeSpan)
+	reg.Register(ast.KindEmphasis, r.renderEmphasis)
+	reg.Register(ast.KindImage, r.renderLink)
+	reg.Register(ast.KindLink, r.renderLink)
+	reg.Register(ast.KindRawHTML, renderNothing)
+	reg.Register(ast.KindText, r.renderText)
+	reg.Register(ast.KindString, r.renderString)
+
+	// extensions
+
+	reg.Register(east.KindTable, r.renderTable)
+	reg.Register(east.KindCaption, r.renderCaption)
+	reg.Register(east.KindTableHeader, r.renderTableRow)
+	reg.Register(east.KindTableRow, r.renderTableRow)
+	reg.Register(east.KindTableCell, r.renderTableCell)
+	reg.Register(east.KindStrikethrough, renderContents)
+	reg.Register(east.KindTaskCheckBox, r.renderTaskCheckBox)
+	reg.Register(east.KindDefinitionList, r.renderList)
+	reg.Register(east.KindDefinitionTerm, r.renderDefinitionTerm)
+	reg.Register(east.KindDefinitionDescription, renderContents)
+	reg.Register(east.KindFootnoteLink, r.renderFootnoteLink)
+	reg.Register(east.KindFootnoteBacklink, renderNothing)
+	reg.Register(east.KindFootnote, r.renderFootnote)
+	reg.Register(east.KindFootnoteList, r.renderFootnoteList)
+	reg.Register(east.KindAbbreviation, renderContents)
+	reg.Register(east.KindAbbreviationDefinition, renderNothing)
+	reg.Register(east.KindFigure, r.renderFigure)
+	reg.Register(east.KindFigureCaption, r.renderFigureCaption)
+}
+
+func renderNothing(_ util.BufWriter, _ []byte, _ ast.Node, _ bool) (ast.WalkStatus, error) {
+	return ast.WalkSkipChildren, nil
+}
+
+func renderContents(_ util.BufWriter, _ []byte, _ ast.Node, _ bool) (ast.WalkStatus, error) {
+	return ast.WalkContinue, nil
+}
+
+// isIndented returns true if the given node is a child of nodes that are
+// rendered as indented paragraphs like list items.
+func isIndented(n ast.Node) bool {
+	p := n.Parent()
+	if p == nil {
+		return false
+	}
+	switch p.Kind() {
+	case ast.KindListItem, east.KindDefinitionDescription, east.KindFootnote:
+		return true
+	}
+	return false
+}
+
+// writeParagraphMacro writes a macro that starts a new paragraph.
+// First paragraphs of list items are started by '.IP' and '.TP' of the items.
+func (r *Renderer) writeParagraphMacro(w util.BufWriter, n ast.Node) {
+	p := n.Parent()
+	switch {
+	case p != nil && p.Kind() == east.KindTableCell:
+		if n.PreviousSibling() != nil {
+			_, _ = w.WriteString(".sp\n")
+		}
+	case isIndented(n):
+		if n.PreviousSibling() == nil && (p.Kind() != east.KindDefinitionDescription ||
+			(p.PreviousSibling() != nil && p.PreviousSibling().Kind() == east.KindDefinitionTerm)) {
+			return
+		}
+		_, _ = w.WriteString(".IP\n")
+	default:
+		_, _ = w.WriteString(".PP\n")
+	}
+}
+
+// writeQuoted writes the given value as a quoted macro argument.
+func (r *Renderer) writeQuoted(w util.BufWriter, value string) {
+	_ = w.WriteByte('"')
+	for i, s := range strings.Split(value, `"`) {
+		if i != 0 {
+			_, _ = w.WriteString(`\(dq`)
+		}
+		r.Writer.RawWrite(w, []byte(s))
+	}
+	_ = w.WriteByte('"')
+}
+
+// header returns arguments of the '.TH' macro of the given document.
+func (r *Renderer) header(doc *ast.Document) Header {
+	header := r.Header
+	for key, value := range doc.Meta() {
+		s := fmt.Sprint(value)
+		switch key {
+		case "title":
+			header.Title = s
+		case "section":
+			header.Section = s
+		case "date":
+			header.Date = s
+		case "source":
+			header.Source = s
+		case "manual":
+			header.Manual = s
+		}
+	}
+	return header
+}
+
+func (r *Renderer) renderDocument(
+	w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	if !entering {
+		return ast.WalkContinue, nil
+	}
+	header := r.header(node.(*ast.Document))
+	if len(header.Title) == 0 {
+		return ast.WalkContinue, nil
+	}
+	_, _ = w.WriteString(".TH ")
+	for i, v := range []string{header.Title, header.Section, header.Date, header.Source, header.Manual} {
+		if i != 0 {
+			_ = w.WriteByte(' ')
+		}
+		r.writeQuoted(w, v)
+	}
+	_ = w.WriteByte('\n')
+	return ast.WalkContinue, nil
+}
+
+func (r *Renderer) renderHeading(
+	w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	n := node.(*ast.Heading)
+	if entering {
+		switch n.Level {
+		case 1:
+			_, _ = w.WriteString(".SH\n")
+		case 2:
+			_, _ = w.WriteString(".SS\n")
+		default:
+			// man macros have only 2 levels of headings.
+			_, _ = w.WriteString(".PP\n\\fB")
+		}
+	} else {
+		if n.Level > 2 {
+			_, _ = w.WriteString(`\fR`)
+		}
+		_ = w.WriteByte('\n')
+	}
+	return ast.WalkContinue, nil
+}
+
+func (r *Renderer) renderBlockquote(
+	w util.BufWriter, source []byte, n ast.Node, entering bool) (ast.WalkStatus, error) {
+	if entering {
+		r.writeParagraphMacro(w, n)
+		_, _ = w.WriteString(".RS\n")
+	} else {
+		_, _ = w.WriteString(".RE\n")
+	}
+	return ast.WalkContinue, nil
+}
+
+func (r *Renderer) renderCodeBlock(
+	w util.BufWriter, source []byte, n ast.Node, entering bool) (ast.WalkStatus, error) {
+	if !entering {
+		return ast.WalkContinue, nil
+	}
+	r.writeParagraphMacro(w, n)
+	_, _ = w.WriteString(".RS 4\n.nf\n")
+	l := n.Lines().Len()
+	for i := range l {
+		line := n.Lines().At(i)
+		r.Writer.RawWrite(w, line.Value(source))
+	}
+	_, _ = w.WriteString(".fi\n.RE\n")
+	return ast.WalkSkipChildren, nil
+}
+
+func (r *Renderer) renderHTMLBlock(
+	w util.BufWriter, source []byte, n ast.Node, entering bool) (ast.WalkStatus, error) {
+	if entering {
+		_, _ = w.WriteString(".\\\" raw HTML omitted\n")
+	}
+	return ast.WalkSkipChildren, nil
+}
+
+// renderList renders lists and definition lists.
+// Nested lists are indented by '.RS'.
+func (r *Renderer) renderList(w util.BufWriter, source []byte, n ast.Node, entering bool) (ast.WalkStatus, error) {
+	if !isIndented(n) {
+		return ast.WalkContinue, nil
+	}
+	if entering {
+		_, _ = w.WriteString(".RS\n")
+	} else {
+		_, _ = w.WriteString(".RE\n")
+	}
+	return ast.WalkContinue, nil
+}
+
+func (r *Renderer) renderListItem(
+	w util.BufWriter, source []byte, n ast.Node, entering bool) (ast.WalkStatus, error) {
+	if !entering {
+		return ast.WalkContinue, nil
+	}
+	list := n.Parent().(*ast.List)
+	if !list.IsOrdered() {
+		_, _ = w.WriteString(".IP \\(bu 2\n")
+		return ast.WalkContinue, nil
+	}
+	number := list.Start
+	for c := n.PreviousSibling(); c != nil; c = c.PreviousSibling() {
+		number++
+	}
+	_, _ = fmt.Fprintf(w, ".IP \"%d%c\" 4\n", number, list.Marker)
+	return ast.WalkContinue, nil
+}
+
+func (r *Renderer) renderParagraph(
+	w util.BufWriter, source []byte, n ast.Node, entering bool) (ast.WalkStatus, error) {
+	if entering {
+		r.writeParagraphMacro(w, n)
+	} else {
+		_ = w.WriteByte('\n')
+	}
+	return ast.WalkContinue, nil
+}
+
+func (r *Renderer) renderThematicBreak(
+	w util.BufWriter, source []byte, n ast.Node, entering bool) (ast.WalkStatus, error) {
+	if entering {
+		r.writeParagraphMacro(w, n)
+		_, _ = w.WriteString(".ce\n* * *\n")
+	}
+	return ast.WalkContinue, nil
+}
+
+// writeURL writes the given URL in angle brackets.
+func (r *Renderer) writeURL(w util.BufWriter, url []byte) {
+	_, _ = w.WriteString(`\[la]`)
+	r.Writer.RawWrite(w, url)
+	_, _ = w.WriteString(`\[ra]`)
+}
+
+func (r *Renderer) renderAutoLink(
+	w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	if !entering {
+		return ast.WalkContinue, nil
+	}
+	n := node.(*ast.AutoLink)
+	if n.AutoLinkType == ast.AutoLinkEmail {
+		r.Writer.RawWrite(w, n.Label(source))
+	} else {
+		r.writeURL(w, n.URL(source))
+	}
+	return ast.WalkContinue, nil
+}
+
+// font returns a font of the given inline node.
+func font(n ast.Node) string {
+	italic, bold := false, false
+	for ; n != nil; n = n.Parent() {
+		switch typed := n.(type) {
+		case *ast.Emphasis:
+			if typed.Level == 2 {
+				bold = true
+			} else {
+				italic = true
+			}
+		case *ast.CodeSpan:
+			bold = true
+		case *ast.Heading:
+			bold = bold || typed.Level > 2
+		}
+	}
+	switch {
+	case italic && bold:
+		return `\f(BI`
+	case italic:
+		return `\fI`
+	case bold:
+		return `\fB`
+	}
+	return `\fR`
+}
+
+func (r *Renderer) renderCodeSpan(w util.BufWriter, source []byte, n ast.Node, entering bool) (ast.WalkStatus, error) {
+	if !entering {
+		_, _ = w.WriteString(font(n.Parent()))
+		return ast.WalkContinue, nil
+	}
+	_, _ = w.WriteString(font(n))
+	for c := n.FirstChild(); c != nil; c = c.NextSibling() {
+		segment := c.(*ast.Text).Segment
+		value := segment.Value(source)
+		if bytes.HasSuffix(value, []byte("\n")) {
+			r.Writer.RawWrite(w, value[:len(value)-1])
+			_ = w.WriteByte(' ')
+		} else {
+			r.Writer.RawWrite(w, value)
+		}
+	}
+	return ast.WalkSkipChildren, nil
+}
+
+func (r *Renderer) renderEmphasis(
+	w util.BufWriter, source []byte, n ast.Node, entering bool) (ast.WalkStatus, error) {
+	if entering {
+		_, _ = w.WriteString(font(n))
+	} else {
+		_, _ = w.WriteString(font(n.Parent()))
+	}
+	return ast.WalkContinue, nil
+}
+
+// renderLink renders links and images as texts followed by URLs.
+func (r *Renderer) renderLink(w util.BufWriter, source []byte, n ast.Node, entering bool) (ast.WalkStatus, error) {
+	if entering {
+		return ast.WalkContinue, nil
+	}
+	var destination []byte
+	switch typed := n.(type) {
+	case *ast.Link:
+		destination = typed.Destination
+	case *ast.Image:
+		destination = typed.Destination
+	}
+	// links to the sections of the manual page do not have meanings.
+	if len(destination) != 0 && destination[0] != '#' {
+		_ = w.WriteByte(' ')
+		r.writeURL(w, destination)
+	}
+	return ast.WalkContinue, nil
+}
+
+// isLineStart returns true if the given inline node is rendered at the
+// beginning of a line. Emphases and code spans start with font escapes.
+func isLineStart(n ast.Node) bool {
+	return renderutil.IsLineStart(n, true, func(p ast.Node) bool {
+		return p.Kind() != ast.KindEmphasis && p.Kind() != ast.KindCodeSpan
+	}, nil)
+}
+
+// writeText writes the given value of the inline node.
+// Writers escape control characters at the beginning of values, but
+// control characters that are not at the beginning of lines need not
+// be escaped.
+func (r *Renderer) writeText(w util.BufWriter, n ast.Node, value []byte, raw bool) {
+	if len(value) != 0 && (value[0] == '.' || value[0] == '\'') && !isLineStart(n) {
+		_ = w.WriteByte(value[0])
+		value = value[1:]
+	}
+	if raw {
+		r.Writer.RawWrite(w, value)
+	} else {
+		r.Writer.Write(w, value)
+	}
+}
+
+func (r *Renderer) renderText(w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	if !entering {
+		return ast.WalkContinue, nil
+	}
+	n := node.(*ast.Text)
+	segment := n.Segment
+	r.writeText(w, n, segment.Value(source), n.IsRaw())
+	if n.HardLineBreak() {
+		_, _ = w.WriteString("\n.br\n")
+	} else if n.SoftLineBreak() {
+		_ = w.WriteByte('\n')
+	}
+	return ast.WalkContinue, nil
+}
+
+func (r *Renderer) renderString(
+	w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	if !entering {
+		return ast.WalkContinue, nil
+	}
+	n := node.(*ast.String)
+	r.writeText(w, n, n.Value, n.IsCode() || n.IsRaw())
+	return ast.WalkContinue, nil
+}
+
+// A Writer interface writes textual contents to a writer.
+type Writer interface {
+	// Write writes the given source to writer with resolving references and unescaping
+	// backslash escaped characters.
+	Write(writer util.BufWriter, source []byte)
+
+	// RawWrite writes the given source to writer without resolving references and
+	// unescaping backslash escaped characters.
+	RawWrite(writer util.BufWriter, source []byte)
+}
+
+type defaultWriter struct {
+}
+
+// NewWriter returns a new Writer.
+func NewWriter() Writer {
+	return &defaultWriter{}
+}
+
+var manEscapes = [256][]byte{
+	'\\': []byte(`\e`),
+	'-':  []byte(`\-`),
+	0:    []byte("�"),
+}
+
+func (d *defaultWriter) RawWrite(writer util.BufWriter, source []byte) {
+	n := 0
+	l := len(source)
+	for i := range l {
+		v := manEscapes[source[i]]
+		// control characters at the beginning of lines start requests.
+		// source is assumed to start at the beginning of a line.
+		if (source[i] == '.' || source[i] == '\'') && (i == 0 || source[i-1] == '\n') {
+			v = []byte{'\\', '&', source[i]}
+		}
+		if v != nil {
+			_, _ = writer.Write(source[i-n : i])
+			n = 0
+			_, _ = writer.Write(v)
+			continue
+		}
+		n++
+	}
+	if n != 0 {
+		_, _ = writer.Write(source[l-n:])
+	}
+}
+
+func (d *defaultWriter) Write(writer util.BufWriter, source []byte) {
+	source = util.UnescapePunctuations(source)
+	source = util.ResolveNumericReferences(source)
+	source = util.ResolveEntityNames(source)
+	d.RawWrite(writer, source)
+}
+
+// DefaultWriter is a default instance of the Writer.
+var DefaultWriter = NewWriter()
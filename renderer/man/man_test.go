@@ -0,0 +1,170 @@
+package man_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/extension"
+	"github.com/yuin/goldmark/renderer"
+	"github.com/yuin/goldmark/renderer/man"
+	"github.com/yuin/goldmark/testutil"
+	"github.com/yuin/goldmark/text"
+	"github.com/yuin/goldmark/util"
+)
+
+func newMarkdown(opts ...man.Option) goldmark.Markdown {
+	return goldmark.New(
+		goldmark.WithRenderer(renderer.NewRenderer(
+			renderer.WithNodeRenderers(util.Prioritized(man.NewRenderer(opts...), 100)),
+		)),
+		goldmark.WithExtensions(
+			extension.GFM,
+			extension.DefinitionList,
+			extension.Footnote,
+		),
+	)
+}
+
+func TestRenderer(t *testing.T) {
+	markdown := newMarkdown()
+	testutil.DoTestCase(
+		markdown,
+		testutil.MarkdownTestCase{
+			No:          1,
+			Description: "Core nodes and escaping",
+			Markdown: `# NAME
+
+gm \- convert *Markdown* to **HTML**
+
+## Details
+
+### Deep *em*
+
+.dot and 'quote at the beginning of lines
+line with \\ backslash  
+hard break
+
+- a
+- b
+  1. x
+  2. y
+
+> quoted
+
+    .PP code\n
+
+See [site](http://example.com) and [section](#name).
+`,
+			Expected: `.SH
+NAME
+.PP
+gm \- convert \fIMarkdown\fR to \fBHTML\fR
+.SS
+Details
+.PP
+\fBDeep \f(BIem\fB\fR
+.PP
+\&.dot and 'quote at the beginning of lines
+line with \e backslash
+.br
+hard break
+.IP \(bu 2
+a
+.IP \(bu 2
+b
+.RS
+.IP "1." 4
+x
+.IP "2." 4
+y
+.RE
+.PP
+.RS
+.PP
+quoted
+.RE
+.PP
+.RS 4
+.nf
+\&.PP code\en
+.fi
+.RE
+.PP
+See site \[la]http://example.com\[ra] and section.
+`,
+		},
+		t,
+	)
+
+	testutil.DoTestCase(
+		markdown,
+		testutil.MarkdownTestCase{
+			No:          2,
+			Description: "Extension nodes",
+			Markdown: `-v, --verbose
+:   Print ` + "`--more`" + ` output.
+
+    Second paragraph.
+
+-q
+:   Quiet.[^1]
+
+- [x] done
+
+| a | b |
+|:-|-:|
+| x | y |
+
+[^1]: the note.
+`,
+			Expected: `.TP
+\-v, \-\-verbose
+Print \fB\-\-more\fR output.
+.IP
+Second paragraph.
+.TP
+\-q
+Quiet.[1]
+.IP \(bu 2
+[x] done
+.PP
+.TS
+allbox;
+lb rb
+l r.
+a	b
+x	y
+.TE
+.SH
+NOTES
+.IP "[1]" 4
+the note.
+`,
+		},
+		t,
+	)
+}
+
+func TestHeader(t *testing.T) {
+	markdown := newMarkdown(man.WithHeader(man.Header{
+		Title:   "GM",
+		Section: "1",
+		Manual:  `"User" Commands`,
+	}))
+	source := []byte("# NAME\n")
+	doc := markdown.Parser().Parse(text.NewReader(source))
+	doc.(*ast.Document).AddMeta("date", "October 2026")
+	var b bytes.Buffer
+	if err := markdown.Renderer().Render(&b, source, doc); err != nil {
+		t.Fatal(err)
+	}
+	expected := `.TH "GM" "1" "October 2026" "" "\(dqUser\(dq Commands"
+.SH
+NAME
+`
+	if b.String() != expected {
+		t.Errorf("expected:\n%s\ngot:\n%s", expected, b.String())
+	}
+}
@@ -0,0 +1,125 @@
+package renderer
+
+import (
+	"bufio"
+	"io"
+
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/util"
+)
+
+// A Target struct is a pair of a Renderer and a writer that RenderMulti
+// renders to.
+type Target struct {
+	// Renderer is a Renderer for this target.
+	Renderer Renderer
+
+	// Writer is a writer that rendered contents are written to.
+	Writer io.Writer
+}
+
+// targetState holds a walking state of a target.
+type targetState struct {
+	renderer *renderer
+	writer   util.BufWriter
+
+	// skipping is a node whose children are skipped by this target.
+	skipping ast.Node
+	stopped  bool
+}
+
+// RenderMulti renders the given AST node to the given targets in a single
+// traversal. NodeRendererFuncs of the targets are called in the order of
+// the targets for each node.
+//
+// WalkStatus returned by NodeRendererFuncs affects only their target: a
+// target can skip children of a node while other targets descend into the
+// children, and a target that returns WalkStop is not called any more.
+// Errors returned by NodeRendererFuncs stop the traversal.
+//
+// Renderers that are not created by NewRenderer are rendered separately by
+// their Render after the traversal.
+func RenderMulti(source []byte, n ast.Node, targets ...Target) error {
+	var states []*targetState
+	var others []Target
+	for _, t := range targets {
+		r, ok := t.Renderer.(*renderer)
+		if !ok {
+			others = append(others, t)
+			continue
+		}
+		r.init()
+		writer, ok := t.Writer.(util.BufWriter)
+		if !ok {
+			writer = bufio.NewWriter(t.Writer)
+		}
+		states = append(states, &targetState{renderer: r, writer: writer})
+	}
+
+	if err := renderTargets(source, n, states); err != nil {
+		return err
+	}
+	for _, state := range states {
+		if err := state.writer.Flush(); err != nil {
+			return err
+		}
+	}
+	for _, t := range others {
+		if err := t.Renderer.Render(t.Writer, source, n); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func renderTargets(source []byte, n ast.Node, states []*targetState) error {
+	// sources holds sources of ast.SourceOwner nodes that are being rendered.
+	sources := [][]byte{ast.SourceOf(n, source)}
+	return ast.Walk(n, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		owner, ok := n.(ast.SourceOwner)
+		ok = ok && owner.OwnSource() != nil
+		if ok && !entering {
+			sources = sources[:len(sources)-1]
+		}
+		active := 0
+		for _, state := range states {
+			if state.stopped {
+				continue
+			}
+			if state.skipping != nil && (entering || state.skipping != n) {
+				continue
+			}
+			state.skipping = nil
+			f := state.renderer.nodeRendererFunc(n.Kind())
+			if f == nil {
+				active++
+				continue
+			}
+			s, err := f(state.writer, sources[len(sources)-1], n, entering)
+			if err != nil {
+				return ast.WalkStop, err
+			}
+			switch {
+			case s == ast.WalkStop:
+				state.stopped = true
+			case s == ast.WalkSkipChildren && entering:
+				state.skipping = n
+			default:
+				active++
+			}
+		}
+		if ok && entering {
+			sources = append(sources, owner.OwnSource())
+		}
+		if active != 0 {
+			return ast.WalkContinue, nil
+		}
+		for _, state := range states {
+			if !state.stopped {
+				// all targets skip children of this node or its ancestors.
+				return ast.WalkSkipChildren, nil
+			}
+		}
+		return ast.WalkStop, nil
+	})
+}
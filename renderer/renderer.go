@@ -1,4 +1,9 @@
 // Package renderer renders the given AST to certain formats.
+//
+// Subpackages other than html implement NodeRenderers for other formats.
+// HTML renderers of the extensions are registered with the priority 500,
+// so these NodeRenderers must be registered with a higher priority(a lower
+// value) than 500 to render extension nodes in their formats.
 package renderer
 
 import (
@@ -14,6 +19,7 @@ import (
 type Config struct {
 	Options       map[OptionName]any
 	NodeRenderers util.PrioritizedSlice
+	BufWriterPool bool
 }
 
 // NewConfig returns a new Config.
@@ -46,6 +52,19 @@ func WithNodeRenderers(ps ...util.PrioritizedValue) Option {
 	return &withNodeRenderers{ps}
 }
 
+type withBufWriterPool struct {
+}
+
+func (o *withBufWriterPool) SetConfig(c *Config) {
+	c.BufWriterPool = true
+}
+
+// WithBufWriterPool is a functional option that reuses buffered writers
+// that are created by Render when a given writer is not a util.BufWriter.
+func WithBufWriterPool() Option {
+	return &withBufWriterPool{}
+}
+
 type withOption struct {
 	name  OptionName
 	value any
@@ -86,6 +105,10 @@ type NodeRendererFuncRegisterer interface {
 
 // A Renderer interface renders given AST node to given
 // writer with given Renderer.
+//
+// Renderers created by NewRenderer are initialized by the first call of
+// Render. Render is safe for concurrent use, but AddOptions must not be
+// called after the first call of Render.
 type Renderer interface {
 	Render(w io.Writer, source []byte, n ast.Node) error
 
@@ -99,6 +122,10 @@ type renderer struct {
 	nodeRendererFuncsTmp map[ast.NodeKind]NodeRendererFunc
 	maxKind              int
 	nodeRendererFuncs    []NodeRendererFunc
+	bufWriterPool        *sync.Pool
+	fingerprintConfig    *Config
+	fingerprint          []byte
+	fingerprintSync      sync.Once
 	initSync             sync.Once
 }
 
@@ -118,6 +145,17 @@ func NewRenderer(options ...Option) Renderer {
 	return r
 }
 
+// Fingerprint implements util.Fingerprinter.Fingerprint.
+// Fingerprint initializes this renderer, so options can not be added after
+// calling Fingerprint. The fingerprint is computed at the first call.
+func (r *renderer) Fingerprint() []byte {
+	r.init()
+	r.fingerprintSync.Do(func() {
+		r.fingerprint = util.Fingerprint(r.fingerprintConfig)
+	})
+	return r.fingerprint
+}
+
 func (r *renderer) AddOptions(opts ...Option) {
 	for _, opt := range opts {
 		opt.SetConfig(r.config)
@@ -131,8 +169,7 @@ func (r *renderer) Register(kind ast.NodeKind, v NodeRendererFunc) {
 	}
 }
 
-// Render renders the given AST node to the given writer with the given Renderer.
-func (r *renderer) Render(w io.Writer, source []byte, n ast.Node) error {
+func (r *renderer) init() {
 	r.initSync.Do(func() {
 		r.options = r.config.Options
 		r.config.NodeRenderers.Sort()
@@ -151,19 +188,51 @@ func (r *renderer) Render(w io.Writer, source []byte, n ast.Node) error {
 		for kind, nr := range r.nodeRendererFuncsTmp {
 			r.nodeRendererFuncs[kind] = nr
 		}
+		if r.config.BufWriterPool {
+			r.bufWriterPool = &sync.Pool{
+				New: func() any {
+					return bufio.NewWriter(nil)
+				},
+			}
+		}
+		r.fingerprintConfig = r.config
 		r.config = nil
 		r.nodeRendererFuncsTmp = nil
 	})
+}
+
+// Render renders the given AST node to the given writer with the given Renderer.
+func (r *renderer) Render(w io.Writer, source []byte, n ast.Node) error {
+	r.init()
 	writer, ok := w.(util.BufWriter)
 	if !ok {
-		writer = bufio.NewWriter(w)
+		if r.bufWriterPool != nil {
+			bw := r.bufWriterPool.Get().(*bufio.Writer)
+			bw.Reset(w)
+			defer func() {
+				bw.Reset(nil)
+				r.bufWriterPool.Put(bw)
+			}()
+			writer = bw
+		} else {
+			writer = bufio.NewWriter(w)
+		}
 	}
+	// sources holds sources of ast.SourceOwner nodes that are being rendered.
+	sources := [][]byte{ast.SourceOf(n, source)}
 	err := ast.Walk(n, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
 		s := ast.WalkStatus(ast.WalkContinue)
 		var err error
-		f := r.nodeRendererFuncs[n.Kind()]
-		if f != nil {
-			s, err = f(writer, source, n, entering)
+		owner, ok := n.(ast.SourceOwner)
+		ok = ok && owner.OwnSource() != nil
+		if ok && !entering {
+			sources = sources[:len(sources)-1]
+		}
+		if f := r.nodeRendererFunc(n.Kind()); f != nil {
+			s, err = f(writer, sources[len(sources)-1], n, entering)
+		}
+		if ok && entering {
+			sources = append(sources, owner.OwnSource())
 		}
 		return s, err
 	})
@@ -172,3 +241,10 @@ func (r *renderer) Render(w io.Writer, source []byte, n ast.Node) error {
 	}
 	return writer.Flush()
 }
+
+func (r *renderer) nodeRendererFunc(kind ast.NodeKind) NodeRendererFunc {
+	if int(kind) < len(r.nodeRendererFuncs) {
+		return r.nodeRendererFuncs[kind]
+	}
+	return nil
+}
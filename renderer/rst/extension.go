@@ -0,0 +1,211 @@
+package rst
+
+import (
+	"fmt"
+
+	"github.com/yuin/goldmark/ast"
+	east "github.com/yuin/goldmark/extension/ast"
+	"github.com/yuin/goldmark/renderer/internal/renderutil"
+	"github.com/yuin/goldmark/util"
+)
+
+// tableGrid returns cells of the table.
+// Columns that are spanned by cells are nil.
+func tableGrid(table *east.Table) [][]*east.TableCell {
+	var grid [][]*east.TableCell
+	remaining := make([]int, len(table.Alignments))
+	for r := table.FirstChild(); r != nil; r = r.NextSibling() {
+		if r.Kind() != east.KindTableRow && r.Kind() != east.KindTableHeader {
+			continue
+		}
+		row := make([]*east.TableCell, len(remaining))
+		col := 0
+		for c := r.FirstChild(); c != nil; c = c.NextSibling() {
+			for col < len(remaining) && remaining[col] > 0 {
+				col++
+			}
+			if col >= len(remaining) {
+				break
+			}
+			cell := c.(*east.TableCell)
+			row[col] = cell
+			for k := col; k < col+cell.ColSpan && k < len(remaining); k++ {
+				remaining[k] = cell.RowSpan
+			}
+			col += cell.ColSpan
+		}
+		for i := range remaining {
+			if remaining[i] > 0 {
+				remaining[i]--
+			}
+		}
+		grid = append(grid, row)
+	}
+	return grid
+}
+
+// renderTable renders tables as list tables. Spanned cells are rendered
+// as empty cells because list tables do not support spans.
+func (r *Renderer) renderTable(
+	w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	if !entering {
+		return ast.WalkContinue, nil
+	}
+	n := node.(*east.Table)
+	r.writeBlockSeparator(w, n)
+	writeID(w, n)
+	_, _ = w.WriteString(".. list-table::")
+	first := n.FirstChild()
+	if first != nil && first.Kind() == east.KindCaption {
+		caption, err := renderutil.RenderChildrenToBytes(source, r.funcs, first)
+		if err != nil {
+			return ast.WalkStop, err
+		}
+		_ = w.WriteByte(' ')
+		_, _ = w.Write(caption)
+		first = first.NextSibling()
+	}
+	_ = w.WriteByte('\n')
+	if first != nil && first.Kind() == east.KindTableHeader {
+		_, _ = w.WriteString("   :header-rows: 1\n")
+	}
+	_ = w.WriteByte('\n')
+	for _, row := range tableGrid(n) {
+		for i, cell := range row {
+			marker := "     - "
+			if i == 0 {
+				marker = "   * - "
+			}
+			var contents []byte
+			if cell != nil {
+				var err error
+				contents, err = renderutil.RenderChildrenToBytes(source, r.funcs, cell)
+				if err != nil {
+					return ast.WalkStop, err
+				}
+			}
+			writeIndented(w, contents, marker)
+		}
+	}
+	return ast.WalkSkipChildren, nil
+}
+
+// renderStrikethrough renders strikethroughs as the 'strike' role.
+// The role is defined at the beginning of the document.
+func (r *Renderer) renderStrikethrough(
+	w util.BufWriter, source []byte, n ast.Node, entering bool) (ast.WalkStatus, error) {
+	if isInMarkup(n) {
+		return ast.WalkContinue, nil
+	}
+	if entering {
+		writeMarkupStart(w, source, n, ":strike:`")
+		writeInterpreted(w, plainText(n, source))
+		writeMarkupEnd(w, source, n, "`")
+	}
+	return ast.WalkSkipChildren, nil
+}
+
+func (r *Renderer) renderTaskCheckBox(
+	w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	if !entering {
+		return ast.WalkContinue, nil
+	}
+	if node.(*east.TaskCheckBox).IsChecked {
+		_, _ = w.WriteString("[x] ")
+	} else {
+		_, _ = w.WriteString("[ ] ")
+	}
+	return ast.WalkContinue, nil
+}
+
+// renderDefinitionTerm renders terms of definition lists.
+// reStructuredText allows only one term for each definition, so
+// consecutive terms are joined.
+func (r *Renderer) renderDefinitionTerm(
+	w util.BufWriter, source []byte, n ast.Node, entering bool) (ast.WalkStatus, error) {
+	if entering {
+		if prev := n.PreviousSibling(); prev != nil && prev.Kind() == east.KindDefinitionDescription {
+			_ = w.WriteByte('\n')
+		}
+		return ast.WalkContinue, nil
+	}
+	if next := n.NextSibling(); next != nil && next.Kind() == east.KindDefinitionTerm {
+		_, _ = w.WriteString(", ")
+	} else {
+		_ = w.WriteByte('\n')
+	}
+	return ast.WalkContinue, nil
+}
+
+func (r *Renderer) renderDefinitionDescription(
+	w util.BufWriter, source []byte, n ast.Node, entering bool) (ast.WalkStatus, error) {
+	if !entering {
+		return ast.WalkContinue, nil
+	}
+	if prev := n.PreviousSibling(); prev != nil && prev.Kind() == east.KindDefinitionDescription {
+		_ = w.WriteByte('\n')
+	}
+	contents, err := renderutil.RenderChildrenToBytes(source, r.funcs, n)
+	if err != nil {
+		return ast.WalkStop, err
+	}
+	writeIndented(w, contents, "   ")
+	return ast.WalkSkipChildren, nil
+}
+
+func (r *Renderer) renderFootnoteLink(
+	w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	if entering {
+		n := node.(*east.FootnoteLink)
+		writeMarkupStart(w, source, n, fmt.Sprintf("[%d", n.Index))
+		writeMarkupEnd(w, source, n, "]_")
+	}
+	return ast.WalkContinue, nil
+}
+
+func (r *Renderer) renderFootnote(
+	w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	if !entering {
+		return ast.WalkContinue, nil
+	}
+	n := node.(*east.Footnote)
+	r.writeBlockSeparator(w, n)
+	contents, err := renderutil.RenderChildrenToBytes(source, r.funcs, n)
+	if err != nil {
+		return ast.WalkStop, err
+	}
+	writeIndented(w, contents, fmt.Sprintf(".. [%d] ", n.Index))
+	return ast.WalkSkipChildren, nil
+}
+
+// renderFigure renders figures as figure directives.
+func (r *Renderer) renderFigure(
+	w util.BufWriter, source []byte, n ast.Node, entering bool) (ast.WalkStatus, error) {
+	if !entering {
+		return ast.WalkContinue, nil
+	}
+	r.writeBlockSeparator(w, n)
+	var caption []byte
+	var image *ast.Image
+	for c := n.FirstChild(); c != nil; c = c.NextSibling() {
+		switch typed := c.(type) {
+		case *ast.Image:
+			image = typed
+		case *east.FigureCaption:
+			var err error
+			caption, err = renderutil.RenderChildrenToBytes(source, r.funcs, typed)
+			if err != nil {
+				return ast.WalkStop, err
+			}
+		}
+	}
+	if image == nil {
+		return ast.WalkSkipChildren, nil
+	}
+	r.writeImageDirective(w, source, "figure", image)
+	if len(caption) != 0 {
+		_ = w.WriteByte('\n')
+		writeIndented(w, caption, "   ")
+	}
+	return ast.WalkSkipChildren, nil
+}
@@ -0,0 +1,735 @@
+// Package rst implements renderer that outputs reStructuredText.
+package rst
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/yuin/goldmark/ast"
+	east "github.com/yuin/goldmark/extension/ast"
+	"github.com/yuin/goldmark/renderer"
+	"github.com/yuin/goldmark/renderer/internal/renderutil"
+	"github.com/yuin/goldmark/util"
+)
+
+// A Renderer struct is an implementation of renderer.NodeRenderer that renders
+// nodes as reStructuredText.
+type Renderer struct {
+	funcs map[ast.NodeKind]renderer.NodeRendererFunc
+}
+
+// NewRenderer returns a new Renderer.
+func NewRenderer() renderer.NodeRenderer {
+	r := &Renderer{}
+	r.funcs = map[ast.NodeKind]renderer.NodeRendererFunc{
+		// blocks
+
+		ast.KindDocument:                r.renderDocument,
+		ast.KindHeading:                 r.renderHeading,
+		ast.KindBlockquote:              r.renderBlockquote,
+		ast.KindCodeBlock:               r.renderCodeBlock,
+		ast.KindFencedCodeBlock:         r.renderCodeBlock,
+		ast.KindHTMLBlock:               r.renderHTMLBlock,
+		ast.KindList:                    r.renderList,
+		ast.KindListItem:                r.renderListItem,
+		ast.KindParagraph:               r.renderParagraph,
+		ast.KindTextBlock:               r.renderParagraph,
+		ast.KindThematicBreak:           r.renderThematicBreak,
+		ast.KindLinkReferenceDefinition: renderNothing,
+
+		// inlines
+
+		ast.KindAutoLink: r.renderAutoLink,
+		ast.KindCodeSpan: r.renderCodeSpan,
+		ast.KindEmphasis: r.renderEmphasis,
+		ast.KindImage:    r.renderImage,
+		ast.KindLink:     r.renderLink,
+		ast.KindRawHTML:  renderNothing,
+		ast.KindText:     r.renderText,
+		ast.KindString:   r.renderString,
+
+		// extensions
+
+		east.KindTable:                  r.renderTable,
+		east.KindStrikethrough:          r.renderStrikethrough,
+		east.KindTaskCheckBox:           r.renderTaskCheckBox,
+		east.KindDefinitionList:         r.renderList,
+		east.KindDefinitionTerm:         r.renderDefinitionTerm,
+		east.KindDefinitionDescription:  r.renderDefinitionDescription,
+		east.KindFootnoteLink:           r.renderFootnoteLink,
+		east.KindFootnoteBacklink:       renderNothing,
+		east.KindFootnote:               r.renderFootnote,
+		east.KindFootnoteList:           r.renderList,
+		east.KindAbbreviation:           renderContents,
+		east.KindAbbreviationDefinition: renderNothing,
+		east.KindFigure:                 r.renderFigure,
+	}
+	return r
+}
+
+// RegisterFuncs implements NodeRenderer.RegisterFuncs .
+func (r *Renderer) RegisterFuncs(reg renderer.NodeRendererFuncRegisterer) {
+	for kind, f := range r.funcs {
+		reg.Register(kind, f)
+	}
+}
+
+func renderNothing(_ util.BufWriter, _ []byte, _ ast.Node, _ bool) (ast.WalkStatus, error) {
+	return ast.WalkSkipChildren, nil
+}
+
+func renderContents(_ util.BufWriter, _ []byte, _ ast.Node, _ bool) (ast.WalkStatus, error) {
+	return ast.WalkContinue, nil
+}
+
+// writeIndented writes the given lines with indentation.
+// The first line is prefixed by first, and other lines are indented by
+// the width of first.
+func writeIndented(w util.BufWriter, lines []byte, first string) {
+	indent := strings.Repeat(" ", utf8.RuneCountInString(first))
+	for i, line := range bytes.Split(lines, []byte("\n")) {
+		switch {
+		case i == 0:
+			_, _ = w.WriteString(strings.TrimRight(first, " "))
+			if len(line) != 0 {
+				_, _ = w.WriteString(first[len(strings.TrimRight(first, " ")):])
+			}
+		case len(line) != 0:
+			_, _ = w.WriteString(indent)
+		}
+		_, _ = w.Write(line)
+		_ = w.WriteByte('\n')
+	}
+}
+
+// writeBlockSeparator writes a blank line before the given block. Empty
+// comments are written after lists to end them.
+func (r *Renderer) writeBlockSeparator(w util.BufWriter, n ast.Node) {
+	prev := renderutil.PreviousBlock(n, ast.KindLinkReferenceDefinition)
+	if prev == nil {
+		return
+	}
+	_ = w.WriteByte('\n')
+	switch {
+	// adjacent lists are merged, and indented blocks after lists are
+	// parts of the lists. Empty comments end the lists.
+	case prev.Kind() == n.Kind() && (n.Kind() == ast.KindList || n.Kind() == east.KindDefinitionList),
+		(prev.Kind() == ast.KindList || prev.Kind() == east.KindDefinitionList) && n.Kind() == ast.KindBlockquote:
+		_, _ = w.WriteString("..\n\n")
+	}
+}
+
+// hasKind returns true if the given node has a descendant of the given kind.
+func hasKind(n ast.Node, kind ast.NodeKind) bool {
+	found := false
+	_ = ast.Walk(n, func(c ast.Node, entering bool) (ast.WalkStatus, error) {
+		if entering && c.Kind() == kind {
+			found = true
+			return ast.WalkStop, nil
+		}
+		return ast.WalkContinue, nil
+	})
+	return found
+}
+
+func (r *Renderer) renderDocument(
+	w util.BufWriter, source []byte, n ast.Node, entering bool) (ast.WalkStatus, error) {
+	// reStructuredText does not have strikethroughs.
+	if entering && hasKind(n, east.KindStrikethrough) {
+		_, _ = w.WriteString(".. role:: strike\n\n")
+	}
+	return ast.WalkContinue, nil
+}
+
+// headingRank returns a rank of the given heading.
+// Ranks of sections in reStructuredText must not be skipped, so levels of
+// headings like 1, 3 are converted into ranks like 1, 2.
+func headingRank(heading *ast.Heading) int {
+	var levels []int
+	_ = ast.Walk(heading.OwnerDocument(), func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		h, ok := n.(*ast.Heading)
+		if !entering || !ok {
+			return ast.WalkContinue, nil
+		}
+		for len(levels) != 0 && levels[len(levels)-1] >= h.Level {
+			levels = levels[:len(levels)-1]
+		}
+		levels = append(levels, h.Level)
+		if h == heading {
+			return ast.WalkStop, nil
+		}
+		return ast.WalkSkipChildren, nil
+	})
+	return len(levels)
+}
+
+// adornments are characters of section adornments for each rank.
+var adornments = []byte{'=', '=', '-', '~', '^', '"'}
+
+// writeID writes an explicit hyperlink target if the given node has an id attribute.
+func writeID(w util.BufWriter, n ast.Node) {
+	if id, ok := n.AttributeString("id"); ok {
+		if value, ok := id.([]byte); ok {
+			_, _ = fmt.Fprintf(w, ".. _%s:\n\n", value)
+		}
+	}
+}
+
+func (r *Renderer) renderHeading(
+	w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	if !entering {
+		return ast.WalkContinue, nil
+	}
+	n := node.(*ast.Heading)
+	r.writeBlockSeparator(w, n)
+	title, err := renderutil.RenderChildrenToBytes(source, r.funcs, n)
+	if err != nil {
+		return ast.WalkStop, err
+	}
+	// sections can not be nested in other blocks.
+	if n.Parent() == nil || n.Parent().Kind() != ast.KindDocument {
+		_, _ = fmt.Fprintf(w, "**%s**\n", bytes.ReplaceAll(title, []byte("**"), nil))
+		return ast.WalkSkipChildren, nil
+	}
+	writeID(w, n)
+	rank := headingRank(n)
+	adornment := adornments[min(rank, len(adornments))-1]
+	// adornments must be at least as long as titles.
+	line := bytes.Repeat([]byte{adornment}, max(utf8.RuneCount(title), 4))
+	if rank == 1 {
+		_, _ = w.Write(line)
+		_ = w.WriteByte('\n')
+	}
+	_, _ = w.Write(title)
+	_ = w.WriteByte('\n')
+	_, _ = w.Write(line)
+	_ = w.WriteByte('\n')
+	return ast.WalkSkipChildren, nil
+}
+
+func (r *Renderer) renderBlockquote(
+	w util.BufWriter, source []byte, n ast.Node, entering bool) (ast.WalkStatus, error) {
+	if !entering {
+		return ast.WalkContinue, nil
+	}
+	r.writeBlockSeparator(w, n)
+	contents, err := renderutil.RenderChildrenToBytes(source, r.funcs, n)
+	if err != nil {
+		return ast.WalkStop, err
+	}
+	writeIndented(w, contents, "    ")
+	return ast.WalkSkipChildren, nil
+}
+
+// writeDirective writes a directive that has the given lines as contents.
+func writeDirective(w util.BufWriter, directive string, lines [][]byte) {
+	_, _ = w.WriteString(directive)
+	_, _ = w.WriteString("\n\n")
+	for _, line := range lines {
+		if len(util.TrimRightSpace(line)) != 0 {
+			_, _ = w.WriteString("   ")
+		}
+		_, _ = w.Write(line)
+	}
+	if len(lines) != 0 && !bytes.HasSuffix(lines[len(lines)-1], []byte("\n")) {
+		_ = w.WriteByte('\n')
+	}
+}
+
+// blockLines returns lines of the given block.
+func blockLines(n ast.Node, source []byte) [][]byte {
+	var lines [][]byte
+	l := n.Lines().Len()
+	for i := range l {
+		line := n.Lines().At(i)
+		lines = append(lines, line.Value(source))
+	}
+	if html, ok := n.(*ast.HTMLBlock); ok && html.HasClosure() {
+		lines = append(lines, html.ClosureLine.Value(source))
+	}
+	return lines
+}
+
+func (r *Renderer) renderCodeBlock(
+	w util.BufWriter, source []byte, n ast.Node, entering bool) (ast.WalkStatus, error) {
+	if !entering {
+		return ast.WalkContinue, nil
+	}
+	r.writeBlockSeparator(w, n)
+	directive := "::"
+	if fcb, ok := n.(*ast.FencedCodeBlock); ok {
+		if language := fcb.Language(source); language != nil {
+			directive = fmt.Sprintf(".. code-block:: %s", language)
+		}
+	}
+	writeDirective(w, directive, blockLines(n, source))
+	return ast.WalkSkipChildren, nil
+}
+
+func (r *Renderer) renderHTMLBlock(
+	w util.BufWriter, source []byte, n ast.Node, entering bool) (ast.WalkStatus, error) {
+	if entering {
+		r.writeBlockSeparator(w, n)
+		writeDirective(w, ".. raw:: html", blockLines(n, source))
+	}
+	return ast.WalkSkipChildren, nil
+}
+
+func (r *Renderer) renderList(w util.BufWriter, source []byte, n ast.Node, entering bool) (ast.WalkStatus, error) {
+	if entering {
+		r.writeBlockSeparator(w, n)
+	}
+	return ast.WalkContinue, nil
+}
+
+// writeItemSeparator writes a blank line between items of the list.
+// Items that contain multiple blocks like nested lists must be separated
+// by blank lines.
+func writeItemSeparator(w util.BufWriter, n ast.Node, tight bool) {
+	prev := n.PreviousSibling()
+	if prev == nil {
+		return
+	}
+	if !tight || prev.ChildCount() > 1 || n.ChildCount() > 1 {
+		_ = w.WriteByte('\n')
+	}
+}
+
+func (r *Renderer) renderListItem(
+	w util.BufWriter, source []byte, n ast.Node, entering bool) (ast.WalkStatus, error) {
+	if !entering {
+		return ast.WalkContinue, nil
+	}
+	list := n.Parent().(*ast.List)
+	writeItemSeparator(w, n, list.IsTight)
+	marker := "- "
+	if list.IsOrdered() {
+		number := list.Start
+		for c := n.PreviousSibling(); c != nil; c = c.PreviousSibling() {
+			number++
+		}
+		marker = fmt.Sprintf("%d%c ", number, list.Marker)
+	}
+	contents, err := renderutil.RenderChildrenToBytes(source, r.funcs, n)
+	if err != nil {
+		return ast.WalkStop, err
+	}
+	writeIndented(w, contents, marker)
+	return ast.WalkSkipChildren, nil
+}
+
+// hasHardLineBreak returns true if the given block has hard line breaks.
+func hasHardLineBreak(n ast.Node) bool {
+	for c := n.FirstChild(); c != nil; c = c.NextSibling() {
+		if t, ok := c.(*ast.Text); ok && t.HardLineBreak() {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *Renderer) renderParagraph(
+	w util.BufWriter, source []byte, n ast.Node, entering bool) (ast.WalkStatus, error) {
+	if !entering {
+		return ast.WalkContinue, nil
+	}
+	r.writeBlockSeparator(w, n)
+	if image, ok := n.FirstChild().(*ast.Image); ok && n.ChildCount() == 1 {
+		r.writeImageDirective(w, source, "image", image)
+		return ast.WalkSkipChildren, nil
+	}
+	contents, err := renderutil.RenderChildrenToBytes(source, r.funcs, n)
+	if err != nil {
+		return ast.WalkStop, err
+	}
+	// paragraphs that have hard line breaks are rendered as line blocks.
+	if hasHardLineBreak(n) {
+		for _, line := range bytes.Split(contents, []byte("\n")) {
+			_, _ = w.WriteString("| ")
+			_, _ = w.Write(line)
+			_ = w.WriteByte('\n')
+		}
+	} else {
+		_, _ = w.Write(contents)
+		_ = w.WriteByte('\n')
+	}
+	r.writeSubstitutions(w, source, n)
+	return ast.WalkSkipChildren, nil
+}
+
+func (r *Renderer) renderThematicBreak(
+	w util.BufWriter, source []byte, n ast.Node, entering bool) (ast.WalkStatus, error) {
+	if entering {
+		r.writeBlockSeparator(w, n)
+		_, _ = w.WriteString("----------\n")
+	}
+	return ast.WalkContinue, nil
+}
+
+// isStartDelimiter returns true if inline markups can start after the given character.
+func isStartDelimiter(c byte) bool {
+	return c == 0 || util.IsSpace(c) || bytes.IndexByte([]byte(`'"([{<-/:`), c) > -1
+}
+
+// isEndDelimiter returns true if inline markups can end before the given character.
+func isEndDelimiter(c byte) bool {
+	return c == 0 || util.IsSpace(c) || bytes.IndexByte([]byte(`'")]}>-/:.,;!?\`), c) > -1
+}
+
+// writeMarkupStart writes a start-string of an inline markup.
+// Escaped spaces are written if the inline markup is not preceded by
+// a whitespace or punctuation.
+func writeMarkupStart(w util.BufWriter, source []byte, n ast.Node, start string) {
+	before, _ := neighbors(n, source)
+	if !isStartDelimiter(before) {
+		_, _ = w.WriteString(`\ `)
+	}
+	_, _ = w.WriteString(start)
+}
+
+// writeMarkupEnd writes an end-string of an inline markup.
+func writeMarkupEnd(w util.BufWriter, source []byte, n ast.Node, end string) {
+	_, _ = w.WriteString(end)
+	_, after := neighbors(n, source)
+	if !isEndDelimiter(after) {
+		_, _ = w.WriteString(`\ `)
+	}
+}
+
+// isInMarkup returns true if the given node is in other inline markups.
+// reStructuredText does not support nested inline markups.
+func isInMarkup(n ast.Node) bool {
+	for p := n.Parent(); p != nil && p.Type() == ast.TypeInline; p = p.Parent() {
+		switch p.Kind() {
+		case ast.KindEmphasis, ast.KindLink, east.KindStrikethrough:
+			return true
+		}
+	}
+	return false
+}
+
+func (r *Renderer) renderAutoLink(
+	w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	if entering {
+		// URLs and email addresses are linked automatically.
+		_, _ = w.Write(node.(*ast.AutoLink).Label(source))
+	}
+	return ast.WalkContinue, nil
+}
+
+func (r *Renderer) renderCodeSpan(w util.BufWriter, source []byte, n ast.Node, entering bool) (ast.WalkStatus, error) {
+	if !entering {
+		return ast.WalkContinue, nil
+	}
+	var buf bytes.Buffer
+	for c := n.FirstChild(); c != nil; c = c.NextSibling() {
+		segment := c.(*ast.Text).Segment
+		value := segment.Value(source)
+		if bytes.HasSuffix(value, []byte("\n")) {
+			_, _ = buf.Write(value[:len(value)-1])
+			_ = buf.WriteByte(' ')
+		} else {
+			_, _ = buf.Write(value)
+		}
+	}
+	// inline literals can not start and end with whitespaces.
+	value := bytes.TrimSpace(buf.Bytes())
+	if isInMarkup(n) || len(value) == 0 {
+		writeEscaped(w, value, 0, 0, false)
+		return ast.WalkSkipChildren, nil
+	}
+	writeMarkupStart(w, source, n, "``")
+	_, _ = w.Write(value)
+	writeMarkupEnd(w, source, n, "``")
+	return ast.WalkSkipChildren, nil
+}
+
+func (r *Renderer) renderEmphasis(
+	w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	if isInMarkup(node) {
+		return ast.WalkContinue, nil
+	}
+	marker := "*"
+	if node.(*ast.Emphasis).Level == 2 {
+		marker = "**"
+	}
+	if entering {
+		writeMarkupStart(w, source, node, marker)
+	} else {
+		writeMarkupEnd(w, source, node, marker)
+	}
+	return ast.WalkContinue, nil
+}
+
+// writeInterpreted writes the given text as an interpreted text.
+func writeInterpreted(w util.BufWriter, value []byte) {
+	for _, c := range value {
+		if c == '`' || c == '\\' || c == '<' {
+			_ = w.WriteByte('\\')
+		}
+		_ = w.WriteByte(c)
+	}
+}
+
+func (r *Renderer) renderLink(w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	n := node.(*ast.Link)
+	if isInMarkup(n) {
+		return ast.WalkContinue, nil
+	}
+	if !entering {
+		return ast.WalkSkipChildren, nil
+	}
+	text := plainText(n, source)
+	if len(text) == 0 {
+		text = n.Destination
+	}
+	writeMarkupStart(w, source, n, "`")
+	writeInterpreted(w, text)
+	_, _ = w.WriteString(" <")
+	if len(n.Destination) != 0 && n.Destination[0] == '#' {
+		// references to internal targets.
+		_, _ = w.Write(n.Destination[1:])
+		_ = w.WriteByte('_')
+	} else {
+		_, _ = w.Write(n.Destination)
+	}
+	// anonymous hyperlinks do not conflict with other links.
+	writeMarkupEnd(w, source, n, ">`__")
+	return ast.WalkSkipChildren, nil
+}
+
+// substitutionName returns a name of the substitution for the given image.
+func substitutionName(n *ast.Image, source []byte) []byte {
+	name := plainText(n, source)
+	if len(name) == 0 {
+		name = n.Destination
+	}
+	return bytes.ReplaceAll(name, []byte("|"), nil)
+}
+
+// renderImage renders inline images as substitution references.
+// Substitutions are defined after the paragraphs.
+func (r *Renderer) renderImage(w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	if !entering {
+		return ast.WalkContinue, nil
+	}
+	n := node.(*ast.Image)
+	if isInMarkup(n) {
+		writeEscaped(w, plainText(n, source), 0, 0, false)
+		return ast.WalkSkipChildren, nil
+	}
+	writeMarkupStart(w, source, n, "|")
+	_, _ = w.Write(substitutionName(n, source))
+	writeMarkupEnd(w, source, n, "|")
+	return ast.WalkSkipChildren, nil
+}
+
+// writeImageDirective writes the given image as a directive like 'image'.
+func (r *Renderer) writeImageDirective(w util.BufWriter, source []byte, directive string, n *ast.Image) {
+	_, _ = fmt.Fprintf(w, ".. %s:: %s\n", directive, n.Destination)
+	if alt := plainText(n, source); len(alt) != 0 {
+		_, _ = fmt.Fprintf(w, "   :alt: %s\n", alt)
+	}
+}
+
+// writeSubstitutions writes substitution definitions of images in the given block.
+func (r *Renderer) writeSubstitutions(w util.BufWriter, source []byte, n ast.Node) {
+	defined := map[string]bool{}
+	_ = ast.Walk(n, func(c ast.Node, entering bool) (ast.WalkStatus, error) {
+		image, ok := c.(*ast.Image)
+		if !entering || !ok || isInMarkup(image) {
+			return ast.WalkContinue, nil
+		}
+		name := substitutionName(image, source)
+		if !defined[string(name)] {
+			defined[string(name)] = true
+			_, _ = fmt.Fprintf(w, "\n.. |%s| image:: %s\n", name, image.Destination)
+		}
+		return ast.WalkSkipChildren, nil
+	})
+}
+
+// textValue returns a value of the given node if the node is a text.
+func textValue(n ast.Node, source []byte) []byte {
+	switch typed := n.(type) {
+	case *ast.Text:
+		segment := typed.Segment
+		return segment.Value(source)
+	case *ast.String:
+		return typed.Value
+	}
+	return nil
+}
+
+// neighbors returns the last character before the given node and the first
+// character after the given node. Characters at the beginning and the end
+// of lines are returned as 0.
+func neighbors(n ast.Node, source []byte) (byte, byte) {
+	var before, after byte
+	for c := n; c != nil && before == 0; c = c.Parent() {
+		if c.Type() == ast.TypeBlock {
+			break
+		}
+		prev := c.PreviousSibling()
+		if prev == nil {
+			continue
+		}
+		if t, ok := prev.(*ast.Text); ok && (t.SoftLineBreak() || t.HardLineBreak()) {
+			break
+		}
+		value := textValue(prev, source)
+		if len(value) == 0 {
+			// other inline markups end with punctuations.
+			before = '-'
+			break
+		}
+		before = value[len(value)-1]
+	}
+	for c := n; c != nil && after == 0; c = c.Parent() {
+		if c.Type() == ast.TypeBlock {
+			break
+		}
+		if t, ok := c.(*ast.Text); ok && (t.SoftLineBreak() || t.HardLineBreak()) {
+			break
+		}
+		next := c.NextSibling()
+		if next == nil {
+			continue
+		}
+		value := textValue(next, source)
+		if len(value) == 0 {
+			after = '-'
+			break
+		}
+		after = value[0]
+	}
+	return before, after
+}
+
+func (r *Renderer) renderText(w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	if !entering {
+		return ast.WalkContinue, nil
+	}
+	n := node.(*ast.Text)
+	segment := n.Segment
+	value := segment.Value(source)
+	if !n.IsRaw() {
+		value = renderutil.ResolveText(value)
+	}
+	before, after := neighbors(n, source)
+	writeEscaped(w, value, before, after, renderutil.IsLineStart(n, true, nil, nil))
+	if n.HardLineBreak() {
+		_ = w.WriteByte('\n')
+	} else if n.SoftLineBreak() {
+		if p := n.Parent(); p != nil && hasHardLineBreak(p) {
+			_ = w.WriteByte(' ')
+		} else {
+			_ = w.WriteByte('\n')
+		}
+	}
+	return ast.WalkContinue, nil
+}
+
+func (r *Renderer) renderString(
+	w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	if !entering {
+		return ast.WalkContinue, nil
+	}
+	n := node.(*ast.String)
+	value := n.Value
+	if !n.IsCode() && !n.IsRaw() {
+		value = renderutil.ResolveText(value)
+	}
+	before, after := neighbors(n, source)
+	writeEscaped(w, value, before, after, renderutil.IsLineStart(n, true, nil, nil))
+	return ast.WalkContinue, nil
+}
+
+// plainText returns a text of the given node without any markups.
+func plainText(n ast.Node, source []byte) []byte {
+	var buf bytes.Buffer
+	_ = ast.Walk(n, func(c ast.Node, entering bool) (ast.WalkStatus, error) {
+		if !entering {
+			return ast.WalkContinue, nil
+		}
+		switch typed := c.(type) {
+		case *ast.Text:
+			segment := typed.Segment
+			value := segment.Value(source)
+			if c.Parent() == nil || c.Parent().Kind() != ast.KindCodeSpan {
+				value = renderutil.ResolveText(value)
+			}
+			_, _ = buf.Write(value)
+			if typed.SoftLineBreak() || typed.HardLineBreak() {
+				_ = buf.WriteByte(' ')
+			}
+		case *ast.String:
+			_, _ = buf.Write(typed.Value)
+		case *ast.AutoLink:
+			_, _ = buf.Write(typed.Label(source))
+		}
+		return ast.WalkContinue, nil
+	})
+	return bytes.TrimSpace(buf.Bytes())
+}
+
+func isWordChar(c byte) bool {
+	return util.IsAlphaNumeric(c) || c >= 0x80
+}
+
+// isEnumerator returns true if the given value starts with an enumerator
+// of enumerated lists like '1.'.
+func isEnumerator(value []byte) bool {
+	i := 0
+	for i < len(value) && util.IsNumeric(value[i]) {
+		i++
+	}
+	return i != 0 && i < len(value) && (value[i] == '.' || value[i] == ')')
+}
+
+// lineStartChars are characters that start blocks like lists at the
+// beginning of lines.
+var lineStartChars = [256]bool{
+	'-': true, '+': true, '#': true, '.': true, ':': true, '>': true,
+}
+
+// writeEscaped writes the given text with escaping characters that have
+// special meanings in reStructuredText. before and after are characters
+// around the text. lineStart is true if the text starts at the beginning
+// of a line.
+func writeEscaped(w util.BufWriter, value []byte, before, after byte, lineStart bool) {
+	if lineStart && len(value) != 0 && lineStartChars[value[0]] {
+		_ = w.WriteByte('\\')
+	}
+	enumerator := lineStart && isEnumerator(value)
+	for i, c := range value {
+		switch c {
+		case '\\', '*', '`', '|':
+			_ = w.WriteByte('\\')
+		case '_':
+			// references like 'word_' and targets like '_word'.
+			prev, next := before, after
+			if i != 0 {
+				prev = value[i-1]
+			}
+			if i != len(value)-1 {
+				next = value[i+1]
+			}
+			if !isWordChar(prev) || !isWordChar(next) {
+				_ = w.WriteByte('\\')
+			}
+		case '.', ')':
+			if enumerator {
+				_ = w.WriteByte('\\')
+				enumerator = false
+			}
+		default:
+			if !util.IsNumeric(c) {
+				enumerator = false
+			}
+		}
+		_ = w.WriteByte(c)
+	}
+}
@@ -0,0 +1,126 @@
+package rst_test
+
+import (
+	"testing"
+
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/extension"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/renderer"
+	"github.com/yuin/goldmark/renderer/rst"
+	"github.com/yuin/goldmark/testutil"
+	"github.com/yuin/goldmark/util"
+)
+
+func TestRenderer(t *testing.T) {
+	markdown := goldmark.New(
+		goldmark.WithRenderer(renderer.NewRenderer(
+			renderer.WithNodeRenderers(util.Prioritized(rst.NewRenderer(), 100)),
+		)),
+		goldmark.WithExtensions(
+			extension.GFM,
+			extension.DefinitionList,
+			extension.Footnote,
+		),
+		goldmark.WithParserOptions(parser.WithAutoHeadingID()),
+	)
+	testutil.DoTestCase(
+		markdown,
+		testutil.MarkdownTestCase{
+			No:          1,
+			Description: "Core nodes and escaping",
+			Markdown: `# Release *1.2.0*
+
+Hello **bold _it_** ` + "`a_b`" + ` [site](http://example.com/a) [docs](docs/x.html) [top](#release-120) ![img](/i.png "T") <http://example.com>
+snake_case _under_score a*b x^2 {attr}  
+. dot
+
+- a
+  1. x
+  2. y
+- b
+
+  second paragraph
+
+> quote
+
+` + "```go\nfmt.Println(\"x\")\n```" + `
+
+***
+`,
+			Expected: `.. _release-120:
+
+===============
+Release *1.2.0*
+===============
+
+| Hello **bold it** ` + "``a_b`` `site <http://example.com/a>`__ `docs <docs/x.html>`__ `top <release-120_>`__" + ` |img| http://example.com snake_case \_under_score a\*b x^2 {attr}
+| \. dot
+
+.. |img| image:: /i.png
+
+- a
+
+  1. x
+  2. y
+
+- b
+
+  second paragraph
+
+..
+
+    quote
+
+.. code-block:: go
+
+   fmt.Println("x")
+
+----------
+`,
+		},
+		t,
+	)
+
+	testutil.DoTestCase(
+		markdown,
+		testutil.MarkdownTestCase{
+			No:          2,
+			Description: "Extension nodes",
+			Markdown: `- [x] done
+- [ ] ~~todo~~
+
+| Name | Qty |
+|:-|-:|
+| apple | 1 |
+| kiwi | 12[^1] |
+
+Term
+: description
+
+[^1]: the note.
+`,
+			Expected: `.. role:: strike
+
+- [x] done
+- [ ] :strike:` + "`todo`" + `
+
+.. list-table::
+   :header-rows: 1
+
+   * - Name
+     - Qty
+   * - apple
+     - 1
+   * - kiwi
+     - 12\ [1]_
+
+Term
+   description
+
+.. [1] the note.
+`,
+		},
+		t,
+	)
+}
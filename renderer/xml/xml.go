@@ -0,0 +1,568 @@
+// Package xml implements renderer that outputs the CommonMark XML representation.
+//
+// Core nodes are rendered as elements defined in the CommonMark DTD
+// (https://github.com/commonmark/commonmark-spec/blob/master/CommonMark.dtd)
+// like 'cmark -t xml' does. Nodes defined in the extension/ast package
+// are rendered as elements in the ExtensionNamespace.
+package xml
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/yuin/goldmark/ast"
+	east "github.com/yuin/goldmark/extension/ast"
+	"github.com/yuin/goldmark/renderer"
+	"github.com/yuin/goldmark/renderer/internal/renderutil"
+	"github.com/yuin/goldmark/util"
+)
+
+// Namespace is a namespace of the CommonMark XML.
+const Namespace = "http://commonmark.org/xml/1.0"
+
+// ExtensionNamespace is a namespace of the elements that represent
+// nodes of the goldmark extensions. Elements in this namespace have
+// the prefix 'ext'.
+const ExtensionNamespace = "https://github.com/yuin/goldmark/extension"
+
+// A Config struct has configurations for the XML based renderers.
+type Config struct {
+	Writer Writer
+}
+
+// NewConfig returns a new Config with defaults.
+func NewConfig() Config {
+	return Config{
+		Writer: DefaultWriter,
+	}
+}
+
+// SetOption implements renderer.NodeRenderer.SetOption.
+func (c *Config) SetOption(name renderer.OptionName, value any) {
+	switch name {
+	case optTextWriter:
+		c.Writer = value.(Writer)
+	}
+}
+
+// An Option interface sets options for XML based renderers.
+type Option interface {
+	SetXMLOption(*Config)
+}
+
+// TextWriter is an option name used in WithWriter.
+const optTextWriter renderer.OptionName = "XMLWriter"
+
+type withWriter struct {
+	value Writer
+}
+
+func (o *withWriter) SetConfig(c *renderer.Config) {
+	c.Options[optTextWriter] = o.value
+}
+
+func (o *withWriter) SetXMLOption(c *Config) {
+	c.Writer = o.value
+}
+
+// WithWriter is a functional option that allow you to set the given writer to
+// the renderer.
+func WithWriter(writer Writer) interface {
+	renderer.Option
+	Option
+} {
+	return &withWriter{writer}
+}
+
+// A Renderer struct is an implementation of renderer.NodeRenderer that renders
+// nodes as the CommonMark XML.
+type Renderer struct {
+	Config
+}
+
+// NewRenderer returns a new Renderer with given options.
+func NewRenderer(opts ...Option) renderer.NodeRenderer {
+	r := &Renderer{
+		Config: NewConfig(),
+	}
+
+	for _, opt := range opts {
+		opt.SetXMLOption(&r.Config)
+	}
+	return r
+}
+
+// RegisterFuncs implements NodeRenderer.RegisterFuncs .
+func (r *Renderer) RegisterFuncs(reg renderer.NodeRendererFuncRegisterer) {
+	// blocks
+
+	reg.Register(ast.KindDocument, r.renderDocument)
+	reg.Register(ast.KindHeading, r.renderHeading)
+	reg.Register(ast.KindBlockquote, r.renderContainer("block_quote"))
+	reg.Register(ast.KindCodeBlock, r.renderCodeBlock)
+	reg.Register(ast.KindFencedCodeBlock, r.renderCodeBlock)
+	reg.Register(ast.KindHTMLBlock, r.renderHTMLBlock)
+	reg.Register(ast.KindList, r.renderList)
+	reg.Register(ast.KindListItem, r.renderContainer("item"))
+	reg.Register(ast.KindParagraph, r.renderContainer("paragraph"))
+	reg.Register(ast.KindTextBlock, r.renderContainer("paragraph"))
+	reg.Register(ast.KindThematicBreak, r.renderThematicBreak)
+	reg.Register(ast.KindLinkReferenceDefinition, func(
+		_ util.BufWriter, _ []byte, _ ast.Node, _ bool) (ast.WalkStatus, error) {
+		return ast.WalkSkipChildren, nil
+	})
+
+	// inlines
+
+	reg.Register(ast.KindAutoLink, r.renderAutoLink)
+	reg.Register(ast.KindCodeSpan, r.renderCodeSpan)
+	reg.Register(ast.KindEmphasis, r.renderEmphasis)
+	reg.Register(ast.KindImage, r.renderLink)
+	reg.Register(ast.KindLink, r.renderLink)
+	reg.Register(ast.KindRawHTML, r.renderRawHTML)
+	reg.Register(ast.KindText, r.renderText)
+	reg.Register(ast.KindString, r.renderText)
+
+	// extensions
+
+	reg.Register(east.KindTable, r.renderExtension)
+	reg.Register(east.KindTableHeader, r.renderExtension)
+	reg.Register(east.KindTableRow, r.renderExtension)
+	reg.Register(east.KindTableCell, r.renderExtension)
+	reg.Register(east.KindCaption, r.renderExtension)
+	reg.Register(east.KindStrikethrough, r.renderExtension)
+	reg.Register(east.KindTaskCheckBox, r.renderExtension)
+	reg.Register(east.KindDefinitionList, r.renderExtension)
+	reg.Register(east.KindDefinitionTerm, r.renderExtension)
+	reg.Register(east.KindDefinitionDescription, r.renderExtension)
+	reg.Register(east.KindFootnoteLink, r.renderExtension)
+	reg.Register(east.KindFootnoteBacklink, r.renderExtension)
+	reg.Register(east.KindFootnote, r.renderExtension)
+	reg.Register(east.KindFootnoteList, r.renderExtension)
+	reg.Register(east.KindAbbreviation, r.renderExtension)
+	reg.Register(east.KindAbbreviationDefinition, r.renderExtension)
+	reg.Register(east.KindFigure, r.renderExtension)
+	reg.Register(east.KindFigureCaption, r.renderExtension)
+}
+
+// An attribute struct is a name and value pair of the XML attribute.
+type attribute struct {
+	name  string
+	value []byte
+}
+
+func attr(name, value string) attribute {
+	return attribute{name, []byte(value)}
+}
+
+// writeIndent writes an indentation for the given node.
+func (r *Renderer) writeIndent(w util.BufWriter, n ast.Node) {
+	for p := n.Parent(); p != nil; p = p.Parent() {
+		_, _ = w.WriteString("  ")
+	}
+}
+
+// writeStartTag writes a start tag of the element.
+// If empty is true, writeStartTag writes an empty element tag.
+func (r *Renderer) writeStartTag(w util.BufWriter, n ast.Node, name string, attrs []attribute, empty bool) {
+	r.writeIndent(w, n)
+	_ = w.WriteByte('<')
+	_, _ = w.WriteString(name)
+	for _, a := range attrs {
+		_ = w.WriteByte(' ')
+		_, _ = w.WriteString(a.name)
+		_, _ = w.WriteString(`="`)
+		r.Writer.RawWrite(w, a.value)
+		_ = w.WriteByte('"')
+	}
+	if empty {
+		_, _ = w.WriteString(" />\n")
+	} else {
+		_, _ = w.WriteString(">\n")
+	}
+}
+
+func (r *Renderer) writeEndTag(w util.BufWriter, n ast.Node, name string) {
+	r.writeIndent(w, n)
+	_, _ = w.WriteString("</")
+	_, _ = w.WriteString(name)
+	_, _ = w.WriteString(">\n")
+}
+
+// writeLiteral writes an element that has the given literal contents.
+func (r *Renderer) writeLiteral(w util.BufWriter, n ast.Node, name string, attrs []attribute, value []byte) {
+	r.writeIndent(w, n)
+	_ = w.WriteByte('<')
+	_, _ = w.WriteString(name)
+	for _, a := range attrs {
+		_ = w.WriteByte(' ')
+		_, _ = w.WriteString(a.name)
+		_, _ = w.WriteString(`="`)
+		r.Writer.RawWrite(w, a.value)
+		_ = w.WriteByte('"')
+	}
+	_, _ = w.WriteString(` xml:space="preserve">`)
+	r.Writer.RawWrite(w, value)
+	_, _ = w.WriteString("</")
+	_, _ = w.WriteString(name)
+	_, _ = w.WriteString(">\n")
+}
+
+func (r *Renderer) renderDocument(
+	w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	if entering {
+		_, _ = w.WriteString(`<?xml version="1.0" encoding="UTF-8"?>` + "\n")
+		_, _ = w.WriteString(`<!DOCTYPE document SYSTEM "CommonMark.dtd">` + "\n")
+		_, _ = fmt.Fprintf(w, `<document xmlns="%s" xmlns:ext="%s">`+"\n", Namespace, ExtensionNamespace)
+	} else {
+		_, _ = w.WriteString("</document>\n")
+	}
+	return ast.WalkContinue, nil
+}
+
+// renderContainer returns a function that renders a node as an element that
+// has child elements.
+func (r *Renderer) renderContainer(name string) renderer.NodeRendererFunc {
+	return func(w util.BufWriter, source []byte, n ast.Node, entering bool) (ast.WalkStatus, error) {
+		if entering {
+			r.writeStartTag(w, n, name, nil, false)
+		} else {
+			r.writeEndTag(w, n, name)
+		}
+		return ast.WalkContinue, nil
+	}
+}
+
+func (r *Renderer) renderHeading(
+	w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	n := node.(*ast.Heading)
+	if entering {
+		r.writeStartTag(w, n, "heading", []attribute{attr("level", strconv.Itoa(n.Level))}, false)
+	} else {
+		r.writeEndTag(w, n, "heading")
+	}
+	return ast.WalkContinue, nil
+}
+
+func linesValue(n ast.Node, source []byte) []byte {
+	var buf bytes.Buffer
+	l := n.Lines().Len()
+	for i := range l {
+		line := n.Lines().At(i)
+		_, _ = buf.Write(line.Value(source))
+	}
+	return buf.Bytes()
+}
+
+func (r *Renderer) renderCodeBlock(
+	w util.BufWriter, source []byte, n ast.Node, entering bool) (ast.WalkStatus, error) {
+	if !entering {
+		return ast.WalkContinue, nil
+	}
+	var attrs []attribute
+	if fcb, ok := n.(*ast.FencedCodeBlock); ok && fcb.Info != nil {
+		attrs = append(attrs, attribute{"info", renderutil.ResolveText(fcb.Info.Value(source))})
+	}
+	r.writeLiteral(w, n, "code_block", attrs, linesValue(n, source))
+	return ast.WalkSkipChildren, nil
+}
+
+func (r *Renderer) renderHTMLBlock(
+	w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	if !entering {
+		return ast.WalkContinue, nil
+	}
+	n := node.(*ast.HTMLBlock)
+	value := linesValue(n, source)
+	if n.HasClosure() {
+		value = append(value, n.ClosureLine.Value(source)...)
+	}
+	r.writeLiteral(w, n, "html_block", nil, value)
+	return ast.WalkSkipChildren, nil
+}
+
+func (r *Renderer) renderList(w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	n := node.(*ast.List)
+	if !entering {
+		r.writeEndTag(w, n, "list")
+		return ast.WalkContinue, nil
+	}
+	var attrs []attribute
+	if n.IsOrdered() {
+		delim := "period"
+		if n.Marker == ')' {
+			delim = "paren"
+		}
+		attrs = append(attrs, attr("type", "ordered"), attr("start", strconv.Itoa(n.Start)), attr("delim", delim))
+	} else {
+		attrs = append(attrs, attr("type", "bullet"))
+	}
+	attrs = append(attrs, attr("tight", strconv.FormatBool(n.IsTight)))
+	r.writeStartTag(w, n, "list", attrs, false)
+	return ast.WalkContinue, nil
+}
+
+func (r *Renderer) renderThematicBreak(
+	w util.BufWriter, source []byte, n ast.Node, entering bool) (ast.WalkStatus, error) {
+	if entering {
+		r.writeStartTag(w, n, "thematic_break", nil, true)
+	}
+	return ast.WalkContinue, nil
+}
+
+func (r *Renderer) renderAutoLink(
+	w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	if !entering {
+		return ast.WalkContinue, nil
+	}
+	n := node.(*ast.AutoLink)
+	url := n.URL(source)
+	if n.AutoLinkType == ast.AutoLinkEmail && !bytes.HasPrefix(bytes.ToLower(url), []byte("mailto:")) {
+		url = append([]byte("mailto:"), url...)
+	}
+	r.writeStartTag(w, n, "link", []attribute{{"destination", url}, attr("title", "")}, false)
+	child := ast.NewText()
+	child.SetParent(n)
+	r.writeLiteral(w, child, "text", nil, n.Label(source))
+	r.writeEndTag(w, n, "link")
+	return ast.WalkContinue, nil
+}
+
+func (r *Renderer) renderCodeSpan(w util.BufWriter, source []byte, n ast.Node, entering bool) (ast.WalkStatus, error) {
+	if !entering {
+		return ast.WalkContinue, nil
+	}
+	var buf bytes.Buffer
+	for c := n.FirstChild(); c != nil; c = c.NextSibling() {
+		segment := c.(*ast.Text).Segment
+		value := segment.Value(source)
+		if bytes.HasSuffix(value, []byte("\n")) {
+			_, _ = buf.Write(value[:len(value)-1])
+			_ = buf.WriteByte(' ')
+		} else {
+			_, _ = buf.Write(value)
+		}
+	}
+	r.writeLiteral(w, n, "code", nil, buf.Bytes())
+	return ast.WalkSkipChildren, nil
+}
+
+func (r *Renderer) renderEmphasis(
+	w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	n := node.(*ast.Emphasis)
+	name := "emph"
+	if n.Level == 2 {
+		name = "strong"
+	}
+	if entering {
+		r.writeStartTag(w, n, name, nil, false)
+	} else {
+		r.writeEndTag(w, n, name)
+	}
+	return ast.WalkContinue, nil
+}
+
+func (r *Renderer) renderLink(w util.BufWriter, source []byte, n ast.Node, entering bool) (ast.WalkStatus, error) {
+	name := "link"
+	var destination, title []byte
+	switch typed := n.(type) {
+	case *ast.Link:
+		destination, title = typed.Destination, typed.Title
+	case *ast.Image:
+		name = "image"
+		destination, title = typed.Destination, typed.Title
+	}
+	if entering {
+		r.writeStartTag(w, n, name, []attribute{
+			{"destination", destination},
+			{"title", title},
+		}, false)
+	} else {
+		r.writeEndTag(w, n, name)
+	}
+	return ast.WalkContinue, nil
+}
+
+func (r *Renderer) renderRawHTML(
+	w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	if !entering {
+		return ast.WalkContinue, nil
+	}
+	n := node.(*ast.RawHTML)
+	var buf bytes.Buffer
+	l := n.Segments.Len()
+	for i := range l {
+		segment := n.Segments.At(i)
+		_, _ = buf.Write(segment.Value(source))
+	}
+	r.writeLiteral(w, n, "html_inline", nil, buf.Bytes())
+	return ast.WalkSkipChildren, nil
+}
+
+// textValue returns a value of the Text or String node and
+// whether the node is a Text or String node.
+func textValue(n ast.Node, source []byte) ([]byte, bool) {
+	switch typed := n.(type) {
+	case *ast.Text:
+		if typed.IsRaw() {
+			return typed.Value(source), true
+		}
+		return renderutil.ResolveText(typed.Value(source)), true
+	case *ast.String:
+		if typed.IsRaw() || typed.IsCode() {
+			return typed.Value, true
+		}
+		return renderutil.ResolveText(typed.Value), true
+	}
+	return nil, false
+}
+
+func hasLineBreak(n ast.Node) bool {
+	t, ok := n.(*ast.Text)
+	return ok && (t.SoftLineBreak() || t.HardLineBreak())
+}
+
+// renderText renders adjacent Text and String nodes as a 'text' element
+// like the CommonMark reference implementation does.
+func (r *Renderer) renderText(w util.BufWriter, source []byte, n ast.Node, entering bool) (ast.WalkStatus, error) {
+	if !entering {
+		return ast.WalkContinue, nil
+	}
+	if prev := n.PreviousSibling(); prev != nil && !hasLineBreak(prev) {
+		if _, ok := textValue(prev, source); ok { // already rendered
+			return ast.WalkContinue, nil
+		}
+	}
+	var buf bytes.Buffer
+	last := n
+	for c := n; c != nil; c = c.NextSibling() {
+		value, ok := textValue(c, source)
+		if !ok {
+			break
+		}
+		_, _ = buf.Write(value)
+		last = c
+		if hasLineBreak(c) {
+			break
+		}
+	}
+	if buf.Len() != 0 {
+		r.writeLiteral(w, n, "text", nil, buf.Bytes())
+	}
+	if t, ok := last.(*ast.Text); ok {
+		if t.HardLineBreak() {
+			r.writeStartTag(w, n, "linebreak", nil, true)
+		} else if t.SoftLineBreak() {
+			r.writeStartTag(w, n, "softbreak", nil, true)
+		}
+	}
+	return ast.WalkContinue, nil
+}
+
+// extensionElementName returns a name of the element that represents
+// the given extension node like 'ext:table_cell'.
+func extensionElementName(kind ast.NodeKind) string {
+	name := kind.String()
+	var b strings.Builder
+	_, _ = b.WriteString("ext:")
+	for i, c := range name {
+		if c >= 'A' && c <= 'Z' {
+			if i != 0 {
+				_ = b.WriteByte('_')
+			}
+			c += 'a' - 'A'
+		}
+		_, _ = b.WriteRune(c)
+	}
+	return b.String()
+}
+
+// extensionAttributes returns attributes of the given extension node.
+func extensionAttributes(n ast.Node) []attribute {
+	switch typed := n.(type) {
+	case *east.Table:
+		aligns := make([]string, 0, len(typed.Alignments))
+		for _, a := range typed.Alignments {
+			aligns = append(aligns, a.String())
+		}
+		return []attribute{attr("alignments", strings.Join(aligns, " "))}
+	case *east.TableCell:
+		attrs := []attribute{attr("align", typed.Alignment.String())}
+		if typed.ColSpan > 1 {
+			attrs = append(attrs, attr("colspan", strconv.Itoa(typed.ColSpan)))
+		}
+		if typed.RowSpan > 1 {
+			attrs = append(attrs, attr("rowspan", strconv.Itoa(typed.RowSpan)))
+		}
+		return attrs
+	case *east.TaskCheckBox:
+		return []attribute{attr("checked", strconv.FormatBool(typed.IsChecked))}
+	case *east.DefinitionDescription:
+		return []attribute{attr("tight", strconv.FormatBool(typed.IsTight))}
+	case *east.FootnoteLink:
+		return []attribute{attr("index", strconv.Itoa(typed.Index))}
+	case *east.FootnoteBacklink:
+		return []attribute{attr("index", strconv.Itoa(typed.Index))}
+	case *east.Footnote:
+		return []attribute{attr("index", strconv.Itoa(typed.Index)), {"label", typed.Ref}}
+	case *east.Abbreviation:
+		return []attribute{{"title", typed.Title}}
+	case *east.AbbreviationDefinition:
+		return []attribute{{"label", typed.Label}, {"title", typed.Title}}
+	}
+	return nil
+}
+
+// renderExtension renders a node defined in the extension/ast package.
+func (r *Renderer) renderExtension(
+	w util.BufWriter, source []byte, n ast.Node, entering bool) (ast.WalkStatus, error) {
+	name := extensionElementName(n.Kind())
+	empty := !n.HasChildren()
+	if entering {
+		r.writeStartTag(w, n, name, extensionAttributes(n), empty)
+	} else if !empty {
+		r.writeEndTag(w, n, name)
+	}
+	return ast.WalkContinue, nil
+}
+
+// A Writer interface writes textual contents to a writer.
+type Writer interface {
+	// RawWrite writes the given source to writer with escaping
+	// characters that have special meanings in XML.
+	RawWrite(writer util.BufWriter, source []byte)
+}
+
+type defaultWriter struct {
+}
+
+// NewWriter returns a new Writer.
+func NewWriter() Writer {
+	return &defaultWriter{}
+}
+
+func (d *defaultWriter) RawWrite(writer util.BufWriter, source []byte) {
+	n := 0
+	l := len(source)
+	for i := range l {
+		v := util.EscapeHTMLByte(source[i])
+		if v == nil && source[i] == 0 {
+			v = []byte("�")
+		}
+		if v != nil {
+			_, _ = writer.Write(source[i-n : i])
+			n = 0
+			_, _ = writer.Write(v)
+			continue
+		}
+		n++
+	}
+	if n != 0 {
+		_, _ = writer.Write(source[l-n:])
+	}
+}
+
+// DefaultWriter is a default instance of the Writer.
+var DefaultWriter = NewWriter()
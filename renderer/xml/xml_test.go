@@ -0,0 +1,188 @@
+package xml_test
+
+import (
+	"testing"
+
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/extension"
+	"github.com/yuin/goldmark/renderer"
+	"github.com/yuin/goldmark/renderer/xml"
+	"github.com/yuin/goldmark/testutil"
+	"github.com/yuin/goldmark/util"
+)
+
+func newMarkdown(opts ...xml.Option) goldmark.Markdown {
+	return goldmark.New(
+		goldmark.WithRenderer(renderer.NewRenderer(
+			renderer.WithNodeRenderers(util.Prioritized(xml.NewRenderer(opts...), 100)),
+		)),
+		goldmark.WithExtensions(
+			extension.GFM,
+			extension.Footnote,
+		),
+	)
+}
+
+const header = `<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE document SYSTEM "CommonMark.dtd">
+<document xmlns="http://commonmark.org/xml/1.0" xmlns:ext="https://github.com/yuin/goldmark/extension">
+`
+
+func TestRenderer(t *testing.T) {
+	markdown := newMarkdown()
+	testutil.DoTestCase(
+		markdown,
+		testutil.MarkdownTestCase{
+			No:          1,
+			Description: "Blocks",
+			Markdown: "# Hello *world*\n\n" +
+				"3) a\n" +
+				"4) b\n\n" +
+				"- c\n\n" +
+				"- d\n\n" +
+				"> quote\n\n" +
+				"```go  linenos\n" +
+				"x < y\n" +
+				"```\n\n" +
+				"<div>\n" +
+				"</div>\n\n" +
+				"***\n",
+			Expected: header + `  <heading level="1">
+    <text xml:space="preserve">Hello </text>
+    <emph>
+      <text xml:space="preserve">world</text>
+    </emph>
+  </heading>
+  <list type="ordered" start="3" delim="paren" tight="true">
+    <item>
+      <paragraph>
+        <text xml:space="preserve">a</text>
+      </paragraph>
+    </item>
+    <item>
+      <paragraph>
+        <text xml:space="preserve">b</text>
+      </paragraph>
+    </item>
+  </list>
+  <list type="bullet" tight="false">
+    <item>
+      <paragraph>
+        <text xml:space="preserve">c</text>
+      </paragraph>
+    </item>
+    <item>
+      <paragraph>
+        <text xml:space="preserve">d</text>
+      </paragraph>
+    </item>
+  </list>
+  <block_quote>
+    <paragraph>
+      <text xml:space="preserve">quote</text>
+    </paragraph>
+  </block_quote>
+  <code_block info="go  linenos" xml:space="preserve">x &lt; y
+</code_block>
+  <html_block xml:space="preserve">&lt;div&gt;
+&lt;/div&gt;
+</html_block>
+  <thematic_break />
+</document>
+`,
+		},
+		t,
+	)
+
+	testutil.DoTestCase(
+		markdown,
+		testutil.MarkdownTestCase{
+			No:          2,
+			Description: "Inlines",
+			Markdown: "a &amp; \\*b\n" +
+				"c  \n" +
+				"`co de` <b>x</b> **[link](/url \"title\")** ![alt](/img.png) <a@example.com>\n",
+			Expected: header + `  <paragraph>
+    <text xml:space="preserve">a &amp; *b</text>
+    <softbreak />
+    <text xml:space="preserve">c</text>
+    <linebreak />
+    <code xml:space="preserve">co de</code>
+    <text xml:space="preserve"> </text>
+    <html_inline xml:space="preserve">&lt;b&gt;</html_inline>
+    <text xml:space="preserve">x</text>
+    <html_inline xml:space="preserve">&lt;/b&gt;</html_inline>
+    <text xml:space="preserve"> </text>
+    <strong>
+      <link destination="/url" title="title">
+        <text xml:space="preserve">link</text>
+      </link>
+    </strong>
+    <text xml:space="preserve"> </text>
+    <image destination="/img.png" title="">
+      <text xml:space="preserve">alt</text>
+    </image>
+    <text xml:space="preserve"> </text>
+    <link destination="mailto:a@example.com" title="">
+      <text xml:space="preserve">a@example.com</text>
+    </link>
+  </paragraph>
+</document>
+`,
+		},
+		t,
+	)
+
+	testutil.DoTestCase(
+		markdown,
+		testutil.MarkdownTestCase{
+			No:          3,
+			Description: "Extension nodes",
+			Markdown: "| a | b |\n" +
+				"|:-|-:|\n" +
+				"| ~~s~~ | c[^1] |\n\n" +
+				"- [x] done\n\n" +
+				"[^1]: note\n",
+			Expected: header + `  <ext:table alignments="left right">
+    <ext:table_header>
+      <ext:table_cell align="left">
+        <text xml:space="preserve">a</text>
+      </ext:table_cell>
+      <ext:table_cell align="right">
+        <text xml:space="preserve">b</text>
+      </ext:table_cell>
+    </ext:table_header>
+    <ext:table_row>
+      <ext:table_cell align="left">
+        <ext:strikethrough>
+          <text xml:space="preserve">s</text>
+        </ext:strikethrough>
+      </ext:table_cell>
+      <ext:table_cell align="right">
+        <text xml:space="preserve">c</text>
+        <ext:footnote_link index="1" />
+      </ext:table_cell>
+    </ext:table_row>
+  </ext:table>
+  <list type="bullet" tight="true">
+    <item>
+      <paragraph>
+        <ext:task_check_box checked="true" />
+        <text xml:space="preserve">done</text>
+      </paragraph>
+    </item>
+  </list>
+  <ext:footnote_list>
+    <ext:footnote index="1" label="1">
+      <paragraph>
+        <text xml:space="preserve">note</text>
+        <ext:footnote_backlink index="1" />
+      </paragraph>
+    </ext:footnote>
+  </ext:footnote_list>
+</document>
+`,
+		},
+		t,
+	)
+}
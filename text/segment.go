@@ -174,6 +174,14 @@ func NewSegments() *Segments {
 	}
 }
 
+// NewSegmentsWithBuffer returns a new Segments that stores segments in the
+// given buffer until the buffer is full. The buffer must be empty.
+func NewSegmentsWithBuffer(buf []Segment) *Segments {
+	return &Segments{
+		values: buf[:0],
+	}
+}
+
 // Append appends the given segment after the tail of the collection.
 func (s *Segments) Append(t Segment) {
 	s.values = append(s.values, t)
@@ -217,6 +225,17 @@ func (s *Segments) Clear() {
 	s.values = nil
 }
 
+// Clone returns a copy of the collection that does not share elements with
+// this collection.
+func (s *Segments) Clone() *Segments {
+	if s.values == nil {
+		return &Segments{}
+	}
+	values := make([]Segment, len(s.values))
+	copy(values, s.values)
+	return &Segments{values: values}
+}
+
 // Unshift insert the given Segment to head of the collection.
 func (s *Segments) Unshift(v Segment) {
 	s.values = append(s.values[0:1], s.values[0:]...)
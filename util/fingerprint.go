@@ -0,0 +1,198 @@
+package util
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"reflect"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// A Fingerprinter interface is implemented by objects that can return
+// fingerprints of their configurations.
+type Fingerprinter interface {
+	// Fingerprint returns a fingerprint of the configuration of this object.
+	// Objects that have the same configuration return the same fingerprint.
+	Fingerprint() []byte
+}
+
+// Fingerprint returns a SHA-256 hash of the given values.
+//
+// Values are traversed deeply, including unexported fields and values that
+// pointers refer to, so values that have the same contents have the same
+// fingerprint. Values of types in the sync and sync/atomic packages are
+// ignored.
+//
+// Functions are identified by their names. Closures and method values may
+// capture different values, so each closure gets a fingerprint that is
+// unique to the call and the process. Values that have closures never have
+// the same fingerprint.
+func Fingerprint(values ...any) []byte {
+	h := sha256.New()
+	f := &fingerprinter{
+		w:       bufio.NewWriter(h),
+		visited: map[uintptr]int{},
+	}
+	for _, v := range values {
+		f.write(reflect.ValueOf(v))
+	}
+	_ = f.w.Flush()
+	return h.Sum(nil)
+}
+
+type fingerprinter struct {
+	w *bufio.Writer
+
+	// visited holds indices of pointers that have been written.
+	visited map[uintptr]int
+}
+
+func (f *fingerprinter) write(v reflect.Value) {
+	if !v.IsValid() {
+		_, _ = f.w.WriteString("nil;")
+		return
+	}
+	t := v.Type()
+	_, _ = f.w.WriteString(t.String())
+	if pkg := t.PkgPath(); pkg == "sync" || pkg == "sync/atomic" {
+		_ = f.w.WriteByte(';')
+		return
+	}
+	switch v.Kind() {
+	case reflect.Pointer:
+		if v.IsNil() {
+			_, _ = f.w.WriteString("(nil);")
+			return
+		}
+		if i, ok := f.visited[v.Pointer()]; ok {
+			fmt.Fprintf(f.w, "(#%d);", i)
+			return
+		}
+		f.visited[v.Pointer()] = len(f.visited)
+		f.write(v.Elem())
+	case reflect.Interface:
+		f.write(v.Elem())
+	case reflect.Struct:
+		_ = f.w.WriteByte('{')
+		for i := 0; i < v.NumField(); i++ {
+			_, _ = f.w.WriteString(t.Field(i).Name)
+			_ = f.w.WriteByte(':')
+			f.write(v.Field(i))
+		}
+		_ = f.w.WriteByte('}')
+	case reflect.Slice, reflect.Array:
+		if v.Kind() == reflect.Slice && v.IsNil() {
+			_, _ = f.w.WriteString("(nil);")
+			return
+		}
+		fmt.Fprintf(f.w, "[%d]", v.Len())
+		if t.Elem().Kind() == reflect.Uint8 && v.Kind() == reflect.Slice {
+			_, _ = f.w.Write(v.Bytes())
+			return
+		}
+		for i := 0; i < v.Len(); i++ {
+			f.write(v.Index(i))
+		}
+	case reflect.Map:
+		if v.IsNil() {
+			_, _ = f.w.WriteString("(nil);")
+			return
+		}
+		// keys are sorted by their contents instead of their addresses so
+		// that fingerprints do not depend on processes.
+		keys := v.MapKeys()
+		names := make([]string, len(keys))
+		for i, k := range keys {
+			names[i] = encode(k) + "\x00" + encode(v.MapIndex(k))
+		}
+		sort.Sort(&mapKeys{keys, names})
+		fmt.Fprintf(f.w, "[%d]", len(keys))
+		for _, k := range keys {
+			f.write(k)
+			f.write(v.MapIndex(k))
+		}
+	case reflect.Func:
+		if v.IsNil() {
+			_, _ = f.w.WriteString("(nil);")
+			return
+		}
+		name := runtime.FuncForPC(v.Pointer()).Name()
+		if isClosure(name) {
+			fmt.Fprintf(f.w, "(%s#%s-%d);", name, processID(), closureCount.Add(1))
+			return
+		}
+		fmt.Fprintf(f.w, "(%s);", name)
+	case reflect.Chan, reflect.UnsafePointer:
+		_ = f.w.WriteByte(';')
+	default:
+		fmt.Fprintf(f.w, "(%v);", v)
+	}
+}
+
+// encode returns an encoding of the given value that does not depend on
+// addresses.
+func encode(v reflect.Value) string {
+	var buf bytes.Buffer
+	f := &fingerprinter{
+		w:       bufio.NewWriter(&buf),
+		visited: map[uintptr]int{},
+	}
+	f.write(v)
+	_ = f.w.Flush()
+	return buf.String()
+}
+
+// closureCount is a number of closures that have been fingerprinted.
+var closureCount atomic.Int64
+
+// processID returns a random ID of this process.
+var processID = sync.OnceValue(func() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+})
+
+// isClosure returns true if the given function name is a name of a closure
+// like "pkg.F.func1" or a method value like "pkg.T.M-fm".
+func isClosure(name string) bool {
+	if strings.HasSuffix(name, "-fm") {
+		return true
+	}
+	for i := strings.Index(name, ".func"); i > -1; {
+		j := i + len(".func")
+		if j < len(name) && name[j] >= '0' && name[j] <= '9' {
+			return true
+		}
+		k := strings.Index(name[j:], ".func")
+		if k < 0 {
+			break
+		}
+		i = j + k
+	}
+	return false
+}
+
+type mapKeys struct {
+	keys  []reflect.Value
+	names []string
+}
+
+func (m *mapKeys) Len() int {
+	return len(m.keys)
+}
+
+func (m *mapKeys) Less(i, j int) bool {
+	return m.names[i] < m.names[j]
+}
+
+func (m *mapKeys) Swap(i, j int) {
+	m.keys[i], m.keys[j] = m.keys[j], m.keys[i]
+	m.names[i], m.names[j] = m.names[j], m.names[i]
+}